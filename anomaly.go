@@ -0,0 +1,118 @@
+package pmml2lua
+
+import (
+	"strconv"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// AnomalyDetectionModel generates the LUA code for the element. Only isolation forests are
+// supported, where the embedded mining model contains the isolation trees as its segments.
+func (s *Scope) AnomalyDetectionModel(v schema.AnomalyDetectionModel, global *Scope) *Scope {
+	threshold, inclusive := outlierThreshold(v.Output)
+	options := NewStatement().Append("{size=%d, threshold=%s", v.SampleDataSize, formatFloat(threshold))
+	if inclusive {
+		options.Append(", inclusive=true")
+	}
+	if v.AlgorithmType != "iforest" {
+		options.Error("algorithm type %s of model %s is not supported", v.AlgorithmType, v.ModelName)
+	}
+	if v.SampleDataSize < 2 {
+		options.Error("model %s requires a sample data size of at least 2", v.ModelName)
+	}
+
+	forest := v.Model.MiningModel
+	if forest == nil || forest.Segmentation == nil {
+		return s.With(NewStatement().Error("model %s requires a segmented mining model of isolation trees", v.ModelName))
+	}
+
+	trees := NewScope()
+	for _, segment := range forest.Segmentation.Segments {
+		trees.IsolationTree(segment, global)
+	}
+
+	global.Require("anomaly")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or anomaly.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			trees,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// outlierThreshold returns the anomaly score above which a record is flagged as an outlier. The
+// threshold is taken from the output field which compares the predicted score with a constant,
+// and is 0.5 if the output does not declare one.
+func outlierThreshold(v *schema.Output) (threshold float64, inclusive bool) {
+	if v == nil {
+		return 0.5, false
+	}
+
+	scores := make(map[string]bool, len(v.OutputFields))
+	for _, f := range v.OutputFields {
+		if f.Feature == "predictedValue" {
+			scores[f.Name] = true
+			continue
+		}
+
+		if f.Feature != "transformedValue" || f.Expression == nil || f.Expression.Apply == nil {
+			continue
+		}
+
+		apply := f.Expression.Apply
+		if len(apply.Expressions) != 2 {
+			continue
+		}
+
+		ref, constant := apply.Expressions[0].FieldRef, apply.Expressions[1].Constant
+		if ref == nil || constant == nil || !scores[ref.Field] {
+			continue
+		}
+
+		x, err := strconv.ParseFloat(string(constant.Value), 64)
+		if err != nil {
+			continue
+		}
+
+		switch apply.Function {
+		case "greaterThan":
+			return x, false
+		case "greaterOrEqual":
+			return x, true
+		}
+	}
+
+	return 0.5, false
+}
+
+// IsolationTree generates the LUA code for a segment of an isolation forest, which contains the
+// predicate of the segment along with its isolation tree.
+func (s *Scope) IsolationTree(v schema.Segment, global *Scope) *Scope {
+	if v.Predicate == nil {
+		return s.With(NewStatement().Error("segment %s has no predicate", v.ID))
+	}
+	if v.Model.TreeModel == nil {
+		return s.With(NewStatement().Error("segment %s is not an isolation tree", v.ID))
+	}
+
+	segment := NewStatement().Append("{")
+	if v.Weight != 1 {
+		segment.Append("weight=%s, ", formatFloat(v.Weight))
+	}
+
+	tree := *v.Model.TreeModel
+	return s.With(
+		segment.Append("test=function(v)"),
+		NewScope().With(
+			NewStatement().Return().Predicate(v.Predicate, global),
+		),
+		Append("end, tree=tree.NewTree(").TreeOptions(tree).Append("}, {"),
+		NewScope().Node(tree.Node, tree, global),
+		Append("})},"),
+	)
+}
@@ -0,0 +1,90 @@
+local anomaly = {}
+
+-- NewModel creates an isolation forest with its options and its trees. Each tree is wrapped with
+-- the predicate and the weight of its segment. The options contain the anomaly score above which
+-- a record is flagged as an outlier, which is inclusive if specified.
+function anomaly.NewModel(options, trees)
+    local m = options
+    m.trees = trees
+    m.norm = anomaly.C(m.size)
+    for i=1, #trees do
+        trees[i].weight = trees[i].weight or 1
+    end
+
+    -- Function which isolates the record and returns the result table
+    m.eval = function(v)
+        return anomaly.Score(m, v)
+    end
+    return m
+end
+
+-- C returns the average path length of an unsuccessful search in a binary search tree of n
+-- records, which estimates the path length below the leaves which contain several records.
+function anomaly.C(n)
+    if n == nil or n <= 1 then
+        return 0
+    elseif n == 2 then
+        return 1
+    end
+    return 2 * (math.log(n - 1) + 0.5772156649015329) - 2 * (n - 1) / n
+end
+
+-- Score returns the normalized anomaly score of the record out of its average path length in
+-- the trees, along with the outlier flag. If no tree isolates the record, there is no score.
+function anomaly.Score(m, v)
+    local sum, weights = 0, 0
+    for i=1, #m.trees do
+        local s = m.trees[i]
+        if s.test(v) == true then
+            local h = anomaly.Walk(s.tree, v)
+            if h ~= nil then
+                sum = sum + s.weight * h
+                weights = weights + s.weight
+            end
+        end
+    end
+
+    if weights == 0 then
+        return nil
+    end
+
+    local score = 2 ^ (-(sum / weights) / m.norm)
+    local outlier = score > m.threshold
+    if m.inclusive then
+        outlier = score >= m.threshold
+    end
+    return {value = score, outlier = outlier}
+end
+
+-- Walk returns the path length of the record in the tree, starting from the root node whose
+-- predicate is true.
+function anomaly.Walk(t, v)
+    local nodes = t.root.children
+    for i=1, #nodes do
+        if nodes[i].test(v) == true then
+            return anomaly.PathLength(t, nodes[i], v, 0)
+        end
+    end
+    return nil
+end
+
+-- PathLength returns the depth of the node where the record is isolated, corrected by its record
+-- count. The record stops at a node when none of its child predicates is true, unless a missing
+-- value leads it to the default child of the node.
+function anomaly.PathLength(t, n, v, depth)
+    for i=1, #n.children do
+        local x = n.children[i].test(v)
+        if x == true then
+            return anomaly.PathLength(t, n.children[i], v, depth + 1)
+        elseif x == nil and t.missing == 'defaultChild' then
+            for j=1, #n.children do
+                if n.children[j].id == n.default then
+                    return anomaly.PathLength(t, n.children[j], v, depth + 1)
+                end
+            end
+        end
+    end
+    return depth + anomaly.C(n.count)
+end
+
+return anomaly
@@ -0,0 +1,118 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomalyDetectionModel(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/anomaly1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	// The average path length of an unsuccessful search in a binary search tree
+	c := func(n float64) float64 {
+		switch {
+		case n <= 1:
+			return 0
+		case n == 2:
+			return 1
+		}
+		return 2*(math.Log(n-1)+0.5772156649015329) - 2*(n-1)/n
+	}
+
+	s := makeScript(string(code))
+	tests := []struct {
+		input  map[string]interface{}
+		length [2]float64
+	}{
+		{input: map[string]interface{}{"x": 10, "y": 0}, length: [2]float64{2, 1}},
+		{input: map[string]interface{}{"x": 7, "y": 4}, length: [2]float64{2, 2 + c(6)}},
+		{input: map[string]interface{}{"x": 3, "y": 2}, length: [2]float64{2 + c(4), 2 + c(6)}},
+		{input: map[string]interface{}{"x": 1, "y": 5}, length: [2]float64{2 + c(2), 2}},
+		{input: map[string]interface{}{"x": 3}, length: [2]float64{1 + c(6), c(8)}},
+		{input: map[string]interface{}{}, length: [2]float64{c(8), c(8)}},
+	}
+
+	for _, tc := range tests {
+		expect := math.Pow(2, -(tc.length[0]+tc.length[1])/2/c(8))
+		r, err := runScript(s, tc.input)
+		assert.NoError(t, err)
+		assert.InDelta(t, expect, r.Value, 1e-9, tc.input)
+		assert.InDelta(t, expect, r.Output("AnomalyScore"), 1e-9, tc.input)
+		assert.Equal(t, expect > 0.5, r.Outlier, tc.input)
+	}
+
+	// The threshold of the outliers is taken from the output field which compares the score
+	code, err = Convert([]byte(strings.Replace(string(b), `</Output>`, `<OutputField name="Anomaly" feature="transformedValue" dataType="boolean">
+    <Apply function="greaterOrEqual"><FieldRef field="AnomalyScore"/><Constant dataType="double">0.4</Constant></Apply>
+  </OutputField>
+</Output>`, 1)))
+	assert.NoError(t, err)
+
+	s = makeScript(string(code))
+	for _, tc := range tests {
+		expect := math.Pow(2, -(tc.length[0]+tc.length[1])/2/c(8))
+		r, err := runScript(s, tc.input)
+		assert.NoError(t, err)
+		assert.Equal(t, expect >= 0.4, r.Outlier, tc.input)
+		assert.Equal(t, expect >= 0.4, r.Output("Anomaly"), tc.input)
+	}
+
+	// The weights of the segments are applied to the path lengths
+	code, err = Convert([]byte(strings.Replace(string(b), `<Segment id="1">`, `<Segment id="1" weight="3">`, 1)))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 10, "y": 0})
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(2, -(3*2+1)/4.0/c(8)), r.Value, 1e-9)
+
+	// Without any tree whose segment is selected, there is no score
+	code, err = Convert([]byte(strings.Replace(string(b), `<True/>
+    <TreeModel`, `<False/>
+    <TreeModel`, -1)))
+	assert.NoError(t, err)
+
+	r, err = runScript(makeScript(string(code)), map[string]interface{}{"x": 10, "y": 0})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	// The missing values lead to the default child, which is only specified by the root nodes
+	doc := strings.Replace(string(b), `<TreeModel functionName="regression">`, `<TreeModel functionName="regression" missingValueStrategy="defaultChild">`, -1)
+	code, err = Convert([]byte(strings.Replace(doc, `<Node id="0" recordCount="8">`, `<Node id="0" recordCount="8" defaultChild="2">`, -1)))
+	assert.NoError(t, err)
+
+	r, err = runScript(makeScript(string(code)), map[string]interface{}{"x": 3})
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(2, -(1+c(6)+2+c(6))/2/c(8)), r.Value, 1e-9)
+}
+
+func TestAnomalyDetectionModel_Error(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/anomaly1.xml")
+	assert.NoError(t, err)
+
+	doc := string(b)
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(doc, `algorithmType="iforest"`, `algorithmType="ocsvm"`, 1), err: "algorithm type ocsvm of model forest is not supported"},
+		{doc: strings.Replace(doc, `sampleDataSize="8"`, `sampleDataSize="1"`, 1), err: "model forest requires a sample data size of at least 2"},
+		{doc: strings.Replace(strings.Replace(doc, `<MiningModel functionName="regression">`, `<!--`, 1), `</MiningModel>`, `-->`, 1),
+			err: "model forest requires a segmented mining model of isolation trees"},
+		{doc: strings.Replace(strings.Replace(doc, `<Segmentation multipleModelMethod="average">`, `<!--`, 1), `</Segmentation>`, `-->`, 1),
+			err: "model forest requires a segmented mining model of isolation trees"},
+		{doc: strings.Replace(doc, "<Segment id=\"1\">\n    <True/>", `<Segment id="1">`, 1), err: "segment 1 has no predicate"},
+		{doc: strings.Replace(strings.Replace(doc, `<TreeModel functionName="regression">`, `<MiningModel functionName="regression">`, 1), `</TreeModel>`, `</MiningModel>`, 1),
+			err: "segment 1 is not an isolation tree"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
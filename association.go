@@ -0,0 +1,155 @@
+package pmml2lua
+
+import (
+	"strconv"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The features of the association rules which can be output
+var ruleFeatures = map[string]bool{
+	"antecedent": true, "consequent": true, "rule": true, "ruleId": true,
+	"confidence": true, "support": true, "lift": true, "leverage": true, "affinity": true,
+}
+
+// The algorithms which select the association rules of an output field
+var ruleAlgorithms = map[string]bool{
+	"recommendation": true, "exclusiveRecommendation": true, "ruleAssociation": true,
+}
+
+// The measures by which the association rules of an output field can be ranked
+var rankBases = map[string]bool{
+	"confidence": true, "support": true, "lift": true, "leverage": true, "affinity": true,
+}
+
+// AssociationModel generates the LUA code for the element. The items of the itemsets are resolved
+// so that each rule contains the values of its antecedent and consequent items. The item field
+// holds the basket of items rather than a single value, so the mining schema treats each item.
+func (s *Scope) AssociationModel(v schema.AssociationModel, global *Scope) *Scope {
+	item := ""
+	for _, f := range v.MiningSchema.MiningFields {
+		if f.UsageType == "" || f.UsageType == "active" {
+			item = f.Name
+			break
+		}
+	}
+
+	field, _ := global.DataField(item)
+	options := NewStatement().Append("{field=").String(item).Append(", dataType=").String(field.DataType)
+	if item == "" {
+		options.Error("model %s requires an active field for the items", v.ModelName)
+	}
+
+	// The values of the items of each itemset, by the id of the itemset
+	items := make(map[string]schema.Item, len(v.Items))
+	for _, i := range v.Items {
+		items[i.ID] = i
+	}
+
+	itemsets := make(map[string][]schema.Value, len(v.Itemsets))
+	for _, set := range v.Itemsets {
+		for _, ref := range set.ItemRefs {
+			i, ok := items[ref.ItemRef]
+			if !ok {
+				options.Error("itemset %s refers to an unknown item %s", set.ID, ref.ItemRef)
+			}
+			itemsets[set.ID] = append(itemsets[set.ID], schema.Value(i.Value))
+		}
+	}
+
+	rules := NewScope()
+	for i, rule := range v.AssociationRules {
+		rules.With(NewStatement().AssociationRule(rule, i, itemsets, field.DataType))
+	}
+
+	global.Require("association")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or association.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			rules,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// AssociationRule generates the LUA code for the element. The rules without an id are identified
+// by their 1-based index.
+func (s *Statement) AssociationRule(v schema.AssociationRule, index int, itemsets map[string][]schema.Value, dataType string) *Statement {
+	id := v.ID
+	if id == "" {
+		id = strconv.Itoa(index + 1)
+	}
+
+	s.Append("{id=").String(id)
+	for _, side := range []struct {
+		name, itemset string
+	}{{"antecedent", v.Antecedent}, {"consequent", v.Consequent}} {
+		values, ok := itemsets[side.itemset]
+		if !ok {
+			return s.Error("rule %s refers to an unknown itemset %s", id, side.itemset)
+		}
+
+		s.Append(", %s={", side.name)
+		for i, value := range values {
+			s.Literal(value, dataType)
+			if i+1 < len(values) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	s.Append(", support=%s, confidence=%s", formatFloat(v.Support), formatFloat(v.Confidence))
+	if v.Lift != nil {
+		s.Append(", lift=%s", formatFloat(*v.Lift))
+	}
+	if v.Leverage != nil {
+		s.Append(", leverage=%s", formatFloat(*v.Leverage))
+	}
+	if v.Affinity != nil {
+		s.Append(", affinity=%s", formatFloat(*v.Affinity))
+	}
+	return s.Append("},")
+}
+
+// RuleValue generates the LUA code which selects and ranks the association rules of an output
+// field, along with the feature of the rules which is output.
+func (s *Statement) RuleValue(v schema.OutputField) *Statement {
+	feature, algorithm, basis, order := v.RuleFeature, v.Algorithm, v.RankBasis, v.RankOrder
+	if feature == "" {
+		feature = "consequent"
+	}
+	if algorithm == "" {
+		algorithm = "exclusiveRecommendation"
+	}
+	if basis == "" {
+		basis = "confidence"
+	}
+	if order == "" {
+		order = "descending"
+	}
+
+	s.Append(", ruleFeature=").String(feature).
+		Append(", algorithm=").String(algorithm).
+		Append(", rankBasis=").String(basis).
+		Append(", rankOrder=").String(order)
+	if v.IsMultiValued {
+		s.Append(", multiple=true")
+	}
+
+	switch {
+	case !ruleFeatures[feature]:
+		s.Error("rule feature %s is not supported", feature)
+	case !ruleAlgorithms[algorithm]:
+		s.Error("algorithm %s is not supported", algorithm)
+	case !rankBases[basis]:
+		s.Error("rank basis %s is not supported", basis)
+	case order != "descending" && order != "ascending":
+		s.Error("rank order %s is not supported", order)
+	}
+	return s
+}
@@ -0,0 +1,161 @@
+local tree = require("tree")
+local association = {}
+
+-- NewModel creates an association model with its options and its rules. Each rule contains the
+-- values of its antecedent and consequent items along with its measures.
+function association.NewModel(options, rules)
+    local m = options
+    m.rules = rules
+    for i=1, #rules do
+        rules[i].index = i
+    end
+
+    -- Function which matches the basket of the record and returns the result table
+    m.eval = function(v)
+        return association.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the ids of the rules whose antecedent is in the basket of the record, by their
+-- descending confidence. The rules of the output fields are selected by the hidden ruleValue
+-- function of the result, which is not encoded along with it.
+function association.Score(m, v)
+    local basket = association.Basket(m, v[m.field])
+    if basket == nil then
+        return nil
+    end
+
+    local fired = association.Select(m, basket, 'recommendation', 'confidence', 'descending')
+    local r = {rules = {}}
+    for i=1, #fired do
+        r.rules[i] = fired[i].id
+    end
+
+    return setmetatable(r, {__index = {
+        ruleValue = function(f)
+            return association.RuleValue(m, basket, f)
+        end,
+    }})
+end
+
+-- Basket returns the set of the items of the input, which is either a collection of items or a
+-- single item.
+function association.Basket(m, x)
+    if Unknown(x) then
+        return nil
+    end
+
+    local items = {x}
+    if type(x) == 'table' or type(x) == 'userdata' then
+        items = {}
+        for i=1, #x do
+            items[i] = x[i]
+        end
+    end
+
+    local basket = {}
+    for i=1, #items do
+        local item = tree.Cast(items[i], m.dataType)
+        if item ~= nil then
+            basket[item] = true
+        end
+    end
+    return basket
+end
+
+-- Contains checks whether all of the items are in the basket.
+function association.Contains(basket, items)
+    for i=1, #items do
+        if not basket[items[i]] then
+            return false
+        end
+    end
+    return true
+end
+
+-- Matches checks whether the rule is selected by the algorithm for the basket. A recommendation
+-- only requires the antecedent to be in the basket, an exclusive recommendation also requires the
+-- consequent not to be, while a rule association requires both of them to be in the basket.
+function association.Matches(rule, basket, algorithm)
+    if not association.Contains(basket, rule.antecedent) then
+        return false
+    elseif algorithm == 'exclusiveRecommendation' then
+        return not association.Contains(basket, rule.consequent)
+    elseif algorithm == 'ruleAssociation' then
+        return association.Contains(basket, rule.consequent)
+    end
+    return true
+end
+
+-- Select returns the rules which are selected by the algorithm for the basket, ranked by the
+-- measure in the order. The rules without the measure are not ranked, and the ties are broken
+-- by the order of declaration of the rules.
+function association.Select(m, basket, algorithm, basis, order)
+    local out = {}
+    for i=1, #m.rules do
+        local rule = m.rules[i]
+        if rule[basis] ~= nil and association.Matches(rule, basket, algorithm) then
+            table.insert(out, rule)
+        end
+    end
+
+    table.sort(out, function(a, b)
+        if a[basis] ~= b[basis] then
+            if order == 'ascending' then
+                return a[basis] < b[basis]
+            end
+            return a[basis] > b[basis]
+        end
+        return a.index < b.index
+    end)
+    return out
+end
+
+-- RuleValue returns the feature of the rule at the rank of the output field, or the features of
+-- every rule up to the rank if the output field is multi-valued.
+function association.RuleValue(m, basket, f)
+    local rules = association.Select(m, basket, f.algorithm, f.rankBasis, f.rankOrder)
+    local rank = f.rank or 1
+    if f.multiple then
+        local out = {}
+        for i=1, math.min(rank, #rules) do
+            out[i] = association.Feature(rules[i], f.ruleFeature)
+        end
+        return out
+    end
+
+    if rules[rank] == nil then
+        return nil
+    end
+    return association.Feature(rules[rank], f.ruleFeature)
+end
+
+-- Feature returns the feature of the rule. The antecedent and the consequent are the value of
+-- their item, or the list of the values of their items if there are several.
+function association.Feature(rule, feature)
+    if feature == 'antecedent' or feature == 'consequent' then
+        local items = rule[feature]
+        if #items == 1 then
+            return items[1]
+        end
+        return items
+    elseif feature == 'rule' then
+        return '{' .. association.Join(rule.antecedent) .. '}->{' .. association.Join(rule.consequent) .. '}'
+    elseif feature == 'ruleId' then
+        return rule.id
+    end
+    return rule[feature]
+end
+
+-- Join concatenates the values of the items, which are converted to strings first as the items
+-- can be numbers or booleans.
+function association.Join(items)
+    local out = {}
+    for i=1, #items do
+        out[i] = tostring(items[i])
+    end
+    return table.concat(out, ',')
+end
+
+return association
@@ -0,0 +1,150 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssociationModel(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/association1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	tests := []struct {
+		basket  interface{}
+		rules   []string
+		outputs map[string]interface{}
+	}{
+		{basket: []string{"Cracker", "Water"}, rules: []string{"1", "2", "3", "5"}, outputs: map[string]interface{}{
+			"Recommendation": "Water",
+			"Exclusive1":     "Banana",
+			"Exclusive2":     []interface{}{"Coke", "Nachos"},
+			"Confidence":     0.5,
+			"Association":    "{Cracker}->{Water}",
+			"TopRules":       []interface{}{"5", "3"},
+		}},
+		{basket: []string{"Coke", "Banana"}, rules: []string{"4"}, outputs: map[string]interface{}{
+			"Recommendation": "Nachos",
+			"Exclusive1":     "Nachos",
+			"Confidence":     0.67,
+			"TopRules":       []interface{}{"4"},
+		}},
+		{basket: "Cracker", rules: []string{"1", "5"}, outputs: map[string]interface{}{
+			"Recommendation": "Water",
+			"Exclusive1":     "Water",
+			"Exclusive2":     []interface{}{"Coke", "Nachos"},
+			"Confidence":     1.0,
+			"TopRules":       []interface{}{"5", "1"},
+		}},
+		{basket: []interface{}{"Coke", "Nachos"}, rules: []string{"4"}, outputs: map[string]interface{}{
+			"Recommendation": "Nachos",
+			"Association":    "{Coke}->{Nachos}",
+			"TopRules":       []interface{}{},
+		}},
+	}
+
+	for _, tc := range tests {
+		r, err := runScript(s, map[string]interface{}{"item": tc.basket})
+		assert.NoError(t, err)
+		assert.Nil(t, r.Value)
+		assert.Equal(t, tc.rules, r.Rules, tc.basket)
+		assert.Equal(t, tc.outputs, r.Outputs, tc.basket)
+	}
+
+	r, err := runScript(s, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestAssociationModel_Ranking(t *testing.T) {
+	doc := `<PMML version="4.4">
+		<DataDictionary><DataField name="item" optype="categorical" dataType="integer"/></DataDictionary>
+		<AssociationModel functionName="associationRules" numberOfTransactions="4" minimumSupport="0" minimumConfidence="0" numberOfItems="3" numberOfItemsets="3" numberOfRules="3">
+			<MiningSchema><MiningField name="item"/></MiningSchema>
+			<Output>
+				<OutputField name="Lowest" feature="ruleValue" ruleFeature="antecedent" algorithm="recommendation" rankBasis="support" rankOrder="ascending" rank="3" isMultiValued="1"/>
+				<OutputField name="Leverage" feature="ruleValue" ruleFeature="leverage" algorithm="recommendation" rankBasis="leverage"/>
+				<OutputField name="Affinity" feature="ruleValue" ruleFeature="ruleId" algorithm="recommendation" rankBasis="affinity"/>
+			</Output>
+			<Item id="a" value="1"/><Item id="b" value="2"/><Item id="c" value="3"/>
+			<Itemset id="1"><ItemRef itemRef="a"/></Itemset>
+			<Itemset id="2"><ItemRef itemRef="b"/></Itemset>
+			<Itemset id="3"><ItemRef itemRef="c"/></Itemset>
+			<AssociationRule antecedent="1" consequent="3" support="0.5" confidence="0.5" leverage="0.1"/>
+			<AssociationRule antecedent="2" consequent="3" support="0.25" confidence="0.5" leverage="0.2"/>
+			<AssociationRule antecedent="2" consequent="1" support="0.5" confidence="0.5" affinity="0.3"/>
+		</AssociationModel>
+	</PMML>`
+
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	// The items are converted to the data type of the field, and the ties keep their order
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"item": []string{"1", "2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, r.Rules)
+	assert.Equal(t, []interface{}{2.0, 1.0, 2.0}, r.Output("Lowest"))
+	assert.Equal(t, 0.2, r.Output("Leverage"))
+	assert.Equal(t, "3", r.Output("Affinity"))
+}
+
+func TestAssociationModel_MiningSchema(t *testing.T) {
+	doc := `<PMML version="4.4">
+		<DataDictionary><DataField name="flag" optype="categorical" dataType="boolean"/></DataDictionary>
+		<AssociationModel functionName="associationRules" numberOfTransactions="2" minimumSupport="0" minimumConfidence="0" numberOfItems="2" numberOfItemsets="2" numberOfRules="1">
+			<MiningSchema><MiningField name="flag" missingValueReplacement="false"/></MiningSchema>
+			<Output>
+				<OutputField name="Rule" feature="ruleValue" ruleFeature="rule" algorithm="recommendation"/>
+			</Output>
+			<Item id="a" value="false"/><Item id="b" value="true"/>
+			<Itemset id="1"><ItemRef itemRef="a"/></Itemset>
+			<Itemset id="2"><ItemRef itemRef="b"/></Itemset>
+			<AssociationRule antecedent="1" consequent="2" support="0.5" confidence="0.5"/>
+		</AssociationModel>
+	</PMML>`
+
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	// The boolean items are written in the rules, and the missing basket is replaced
+	s := makeScript(string(code))
+	for _, input := range []map[string]interface{}{
+		{"flag": []bool{false}},
+		{"flag": false},
+		{},
+	} {
+		r, err := runScript(s, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, r.Rules, input)
+		assert.Equal(t, "{false}->{true}", r.Output("Rule"), input)
+	}
+}
+
+func TestAssociationModel_Error(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/association1.xml")
+	assert.NoError(t, err)
+
+	doc := string(b)
+	recommendation := `ruleFeature="consequent" algorithm="recommendation"`
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(doc, `<MiningField name="item" usageType="active"/>`, `<MiningField name="item" usageType="group"/>`, 1), err: "model groceries requires an active field for the items"},
+		{doc: strings.Replace(doc, `support="0.4"><ItemRef itemRef="1"/>`, `support="0.4"><ItemRef itemRef="9"/>`, 1), err: "itemset 1 refers to an unknown item 9"},
+		{doc: strings.Replace(doc, `antecedent="1" consequent="3"`, `antecedent="1" consequent="9"`, 1), err: "rule 1 refers to an unknown itemset 9"},
+		{doc: strings.Replace(doc, recommendation, `ruleFeature="other" algorithm="recommendation"`, 1), err: "rule feature other is not supported"},
+		{doc: strings.Replace(doc, recommendation, `ruleFeature="consequent" algorithm="other"`, 1), err: "algorithm other is not supported"},
+		{doc: strings.Replace(doc, recommendation, recommendation+` rankBasis="other"`, 1), err: "rank basis other is not supported"},
+		{doc: strings.Replace(doc, recommendation, recommendation+` rankOrder="other"`, 1), err: "rank order other is not supported"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,85 @@
+package pmml2lua
+
+import (
+	"math"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// BaselineModel generates the LUA code for the element. The z-value compares the mean of the
+// values of the field with the baseline distribution, while the CUSUM accumulates the log
+// likelihood ratio of the alternate distribution over the baseline one.
+func (s *Scope) BaselineModel(v schema.BaselineModel, global *Scope) *Scope {
+	test := v.TestDistributions
+	options := NewStatement().Append("{field=").String(test.Field).
+		Append(", statistic=").String(test.TestStatistic)
+
+	switch test.TestStatistic {
+	case "zValue":
+		mean, variance, ok := moments(test.Baseline)
+		switch {
+		case !ok:
+			options.Error("baseline distribution of %s is not supported", v.ModelName)
+		case variance <= 0:
+			options.Error("baseline distribution of %s requires a positive variance", v.ModelName)
+		}
+		options.Append(", mean=%s, sd=%s", formatFloat(mean), formatFloat(math.Sqrt(variance)))
+
+	case "CUSUM":
+		if test.Alternate == nil {
+			options.Error("CUSUM of %s requires an alternate distribution", v.ModelName)
+			break
+		}
+
+		options.Append(", reset=%s, baseline=", formatFloat(test.ResetValue)).
+			ContinuousDistribution(test.Baseline).
+			Append(", alternate=").
+			ContinuousDistribution(*test.Alternate)
+
+	default:
+		options.Error("test statistic %s is not supported", test.TestStatistic)
+	}
+
+	global.Require("baseline")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or baseline.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// ContinuousDistribution generates the LUA code for the element, which is the name of the
+// distribution followed by its parameters. Only the distributions with a density are supported.
+func (s *Statement) ContinuousDistribution(v schema.ContinuousDistribution) *Statement {
+	switch {
+	case v.Gaussian != nil:
+		return s.Append("{'gaussian', %s, %s}", formatFloat(v.Gaussian.Mean), formatFloat(v.Gaussian.Variance))
+	case v.Poisson != nil:
+		return s.Append("{'poisson', %s}", formatFloat(v.Poisson.Mean))
+	case v.Uniform != nil:
+		return s.Append("{'uniform', %s, %s}", formatFloat(v.Uniform.Lower), formatFloat(v.Uniform.Upper))
+	default:
+		return s.Error("distribution without a density is not supported")
+	}
+}
+
+// moments returns the mean and the variance of the distribution.
+func moments(v schema.ContinuousDistribution) (mean, variance float64, ok bool) {
+	switch {
+	case v.Gaussian != nil:
+		return v.Gaussian.Mean, v.Gaussian.Variance, true
+	case v.Poisson != nil:
+		return v.Poisson.Mean, v.Poisson.Mean, true
+	case v.Uniform != nil:
+		width := v.Uniform.Upper - v.Uniform.Lower
+		return (v.Uniform.Lower + v.Uniform.Upper) / 2, width * width / 12, true
+	case v.Any != nil:
+		return v.Any.Mean, v.Any.Variance, true
+	default:
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,68 @@
+local bayes = require("bayes")
+local baseline = {}
+
+-- NewModel creates a baseline model with its options, which contain the test statistic along
+-- with the moments or the distributions it requires.
+function baseline.NewModel(options)
+    local m = options
+
+    -- Function which computes the test statistic and returns the result table
+    m.eval = function(v)
+        return baseline.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the test statistic of the values of the field, which is either a series of
+-- values or a single value. If there is no value, there is no statistic.
+function baseline.Score(m, v)
+    local x = v[m.field]
+    if type(x) ~= 'table' then
+        x = {x}
+    end
+    if #x == 0 then
+        return nil
+    end
+
+    if m.statistic == 'zValue' then
+        return {value = baseline.ZValue(m, x)}
+    end
+    return {value = baseline.Cusum(m, x)}
+end
+
+-- ZValue returns the standard score of the mean of the values with respect to the baseline,
+-- whose standard deviation is scaled by the number of values.
+function baseline.ZValue(m, x)
+    local sum = 0
+    for i=1, #x do
+        sum = sum + x[i]
+    end
+    return (sum / #x - m.mean) / (m.sd / math.sqrt(#x))
+end
+
+-- Cusum returns the cumulative sum of the log likelihood ratios of the values, which is never
+-- lower than the reset value. The values which are impossible under both distributions are
+-- ignored.
+function baseline.Cusum(m, x)
+    local s = m.reset
+    for i=1, #x do
+        local p, q = baseline.Density(m.alternate, x[i]), baseline.Density(m.baseline, x[i])
+        if p > 0 or q > 0 then
+            s = math.max(m.reset, s + math.log(p / q))
+        end
+    end
+    return s
+end
+
+-- Density returns the density of the distribution at the value.
+function baseline.Density(d, x)
+    if d[1] == 'uniform' then
+        if x < d[2] or x > d[3] then
+            return 0
+        end
+        return 1 / (d[3] - d[2])
+    end
+    return bayes.distributions[d[1]](x, d[2], d[3])
+end
+
+return baseline
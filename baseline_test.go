@@ -0,0 +1,132 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineModel_ZValue(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/baseline1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	tests := []struct {
+		input  map[string]interface{}
+		expect float64
+	}{
+		{input: map[string]interface{}{"score": 13}, expect: 1.5},
+		{input: map[string]interface{}{"score": 7.0}, expect: -1.5},
+		{input: map[string]interface{}{"score": []float64{11, 12, 13, 12}}, expect: 2},
+		{input: map[string]interface{}{"score": []interface{}{9, "bad", 10, 11}}, expect: 0},
+	}
+
+	for _, tc := range tests {
+		r, err := runScript(s, tc.input)
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.expect, r.Value, 1e-9, tc.input)
+		assert.InDelta(t, tc.expect, r.Output("ZValue"), 1e-9, tc.input)
+	}
+
+	// Without any value, there is no statistic
+	for _, input := range []map[string]interface{}{{}, {"score": []float64{}}} {
+		r, err := runScript(s, input)
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+	}
+
+	// The moments of the other distributions are used as the baseline
+	for distribution, expect := range map[string]float64{
+		`<PoissonDistribution mean="9"/>`:              4 / 3.0,
+		`<UniformDistribution lower="4" upper="16"/>`:  3 / math.Sqrt(12),
+		`<AnyDistribution mean="11" variance="0.25"/>`: 4,
+	} {
+		doc := strings.Replace(string(b), `<GaussianDistribution mean="10" variance="4"/>`, distribution, 1)
+		code, err := Convert([]byte(doc))
+		assert.NoError(t, err)
+
+		r, err := runScript(makeScript(string(code)), map[string]interface{}{"score": 13})
+		assert.NoError(t, err)
+		assert.InDelta(t, expect, r.Value, 1e-9, distribution)
+	}
+}
+
+func TestBaselineModel_CUSUM(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/baseline2.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	// The log likelihood ratio of the two poisson distributions
+	poisson := func(k, mean float64) float64 {
+		p := math.Exp(-mean)
+		for i := 1.0; i <= k; i++ {
+			p *= mean / i
+		}
+		return p
+	}
+	ratio := func(k float64) float64 {
+		return math.Log(poisson(k, 5) / poisson(k, 2))
+	}
+
+	s := makeScript(string(code))
+	tests := []struct {
+		input  []float64
+		expect float64
+	}{
+		{input: []float64{1}, expect: 0},
+		{input: []float64{6}, expect: ratio(6)},
+		{input: []float64{6, 1, 7}, expect: math.Max(0, ratio(6)+ratio(1)) + ratio(7)},
+		{input: []float64{6, 0, 0, 0, 0, 4}, expect: ratio(4)},
+	}
+
+	for _, tc := range tests {
+		r, err := runScript(s, map[string]interface{}{"errors": tc.input})
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.expect, r.Value, 1e-9, tc.input)
+	}
+
+	// The statistic is never lower than the reset value
+	doc := strings.Replace(string(b), `resetValue="0"`, `resetValue="-1"`, 1)
+	code, err = Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"errors": []float64{0, 0, 0}})
+	assert.NoError(t, err)
+	assert.InDelta(t, -1, r.Value, 1e-9)
+
+	// The values which are impossible under both uniform distributions are ignored
+	doc = strings.Replace(string(b), `<PoissonDistribution mean="2"/>`, `<UniformDistribution lower="0" upper="4"/>`, 1)
+	doc = strings.Replace(doc, `<PoissonDistribution mean="5"/>`, `<UniformDistribution lower="0" upper="2"/>`, 1)
+	code, err = Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	r, err = runScript(makeScript(string(code)), map[string]interface{}{"errors": []float64{1, 20, 1.5}})
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*math.Log(2), r.Value, 1e-9)
+}
+
+func TestBaselineModel_Errors(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/baseline2.xml")
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(string(b), `testStatistic="CUSUM"`, `testStatistic="chiSquareIndependence"`, 1), err: "test statistic chiSquareIndependence is not supported"},
+		{doc: strings.Replace(string(b), `<PoissonDistribution mean="5"/>`, `<AnyDistribution mean="5" variance="1"/>`, 1), err: "distribution without a density is not supported"},
+		{doc: strings.Replace(strings.Replace(string(b), `<Alternate>`, `<!--`, 1), `</Alternate>`, `-->`, 1), err: "CUSUM of cusum requires an alternate distribution"},
+		{doc: strings.Replace(strings.Replace(string(b), `testStatistic="CUSUM"`, `testStatistic="zValue"`, 1), `<PoissonDistribution mean="2"/>`, `<GaussianDistribution mean="2" variance="0"/>`, 1), err: "baseline distribution of cusum requires a positive variance"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
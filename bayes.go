@@ -0,0 +1,139 @@
+package pmml2lua
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// NaiveBayesModel generates the LUA code for the element. The counts and the statistics of the
+// inputs are written in the order of the target values of the output.
+func (s *Scope) NaiveBayesModel(v schema.NaiveBayesModel, global *Scope) *Scope {
+	target, _ := global.DataField(v.BayesOutput.FieldName)
+	classes := NewScope()
+	index := make(map[schema.Value]int, len(v.BayesOutput.TargetValueCounts))
+	for i, c := range v.BayesOutput.TargetValueCounts {
+		index[c.Value] = i
+		classes.With(NewStatement().Append("{value=").Literal(c.Value, target.DataType).Append(", count=%s},", formatFloat(c.Count)))
+	}
+
+	if len(v.BayesOutput.TargetValueCounts) == 0 {
+		classes.With(NewStatement().Error("naive bayes model %s has no target value counts", v.ModelName))
+	}
+
+	inputs := NewScope()
+	for _, input := range v.Inputs {
+		inputs.BayesInput(input, index, global)
+	}
+
+	global.Require("bayes")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or bayes.NewModel({threshold=%s}, {", v.ModelName, v.ModelName, formatFloat(v.Threshold)),
+			classes,
+			Append("}, {"),
+			inputs,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// BayesInput generates the LUA code for the element. The input is either discrete, with the
+// counts of each of its values for each target value, or continuous, with the distribution of its
+// values for each target value. The value of a discrete input may be derived from the field, for
+// example by discretizing a continuous field.
+func (s *Scope) BayesInput(v schema.BayesInput, index map[schema.Value]int, global *Scope) *Scope {
+	field, _ := global.DataField(v.FieldName)
+	dataType := field.DataType
+	if v.DerivedField != nil {
+		dataType = v.DerivedField.DataType
+	}
+
+	input := NewStatement().Append("{field=").String(v.FieldName)
+	switch {
+	case len(v.PairCounts) > 0:
+		if dataType != "" {
+			input.Append(", dataType=").String(dataType)
+		}
+
+		input.Append(", counts={")
+		for i, pair := range v.PairCounts {
+			counts := make([]float64, len(index))
+			for _, c := range pair.TargetValueCounts {
+				if j, ok := index[c.Value]; ok {
+					counts[j] = c.Count
+				}
+			}
+
+			input.Append("[").Statement(pairKey(pair.Value, dataType)).Append("]=").Floats(counts)
+			if i+1 < len(v.PairCounts) {
+				input.Append(", ")
+			}
+		}
+		input.Append("}")
+
+	case len(v.TargetValueStats) > 0:
+		stats := make([]*schema.TargetValueStat, len(index))
+		for i, stat := range v.TargetValueStats {
+			if j, ok := index[stat.Value]; ok {
+				stats[j] = &v.TargetValueStats[i]
+			}
+		}
+
+		input.Append(", stats={")
+		for j, stat := range stats {
+			input.TargetValueStat(stat, v.FieldName)
+			if j+1 < len(stats) {
+				input.Append(", ")
+			}
+		}
+		input.Append("}")
+
+	default:
+		input.Error("bayes input %s has neither pair counts nor target value stats", v.FieldName)
+	}
+
+	if v.DerivedField == nil {
+		return s.With(input.Append("},"))
+	}
+
+	return s.With(
+		input.Append(", eval=function(v)"),
+		NewScope().With(
+			NewStatement().Return().Expression(v.DerivedField.Expression, global),
+		),
+		Append("end},"),
+	)
+}
+
+// TargetValueStat generates the LUA code for the element, which is the name of the distribution
+// followed by its parameters.
+func (s *Statement) TargetValueStat(v *schema.TargetValueStat, field string) *Statement {
+	switch {
+	case v == nil:
+		return s.Append("{}")
+	case v.Gaussian != nil:
+		return s.Append("{'gaussian', %s, %s}", formatFloat(v.Gaussian.Mean), formatFloat(v.Gaussian.Variance))
+	case v.Poisson != nil:
+		return s.Append("{'poisson', %s}", formatFloat(v.Poisson.Mean))
+	default:
+		return s.Error("distribution of %s is not supported", field)
+	}
+}
+
+// pairKey generates the key of the pair counts, which is the value written with the data type of
+// the input. Without a data type, a number is keyed by its value so that it matches however it is
+// written.
+func pairKey(v schema.Value, dataType string) *Statement {
+	if dataType != "" {
+		return NewStatement().Literal(v, dataType)
+	}
+	if f, err := strconv.ParseFloat(string(v), 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return NewStatement().Append(formatFloat(f))
+	}
+	return NewStatement().String(string(v))
+}
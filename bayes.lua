@@ -0,0 +1,133 @@
+local tree = require("tree")
+local bayes = {}
+
+-- NewModel creates a naive bayes model with its options, its target values and its inputs. Each
+-- target value contains its count, while each input contains either the counts of its values
+-- or the distributions of its values, in the order of the target values.
+function bayes.NewModel(options, classes, inputs)
+    local m = options
+    m.threshold = m.threshold or 0
+    m.classes = classes
+    m.inputs = inputs
+
+    -- Function which computes the posterior probabilities and returns the result table
+    m.eval = function(v)
+        return bayes.Classify(m, v)
+    end
+    return m
+end
+
+-- Classify returns the target value with the highest posterior probability, along with the
+-- probabilities of every target value. The likelihood of each target value is its count times
+-- the conditional probabilities of the inputs, and the inputs which are missing or whose value
+-- was not seen during the training are ignored.
+function bayes.Classify(m, v)
+    local n, l = #m.classes, {}
+    for j=1, n do
+        l[j] = m.classes[j].count
+    end
+
+    for i=1, #m.inputs do
+        local input = m.inputs[i]
+        local x = v[input.field]
+        if input.eval ~= nil then
+            x = input.eval(v)
+        end
+
+        if not Unknown(x) then
+            for j=1, n do
+                local p = bayes.Probability(m, input, x, j)
+                if p ~= nil then
+                    if p == 0 then
+                        p = m.threshold
+                    end
+                    l[j] = l[j] * p
+                end
+            end
+        end
+    end
+
+    local sum = 0
+    for j=1, n do
+        sum = sum + l[j]
+    end
+    if sum == 0 then
+        return nil
+    end
+
+    local r = {probabilities = {}}
+    local best = nil
+    for j=1, n do
+        r.probabilities[tostring(m.classes[j].value)] = l[j] / sum
+        if best == nil or l[j] > l[best] then
+            best = j
+        end
+    end
+
+    r.value = m.classes[best].value
+    return r
+end
+
+-- Key returns the key of the value in the pair counts, which is the value converted to the data
+-- type of the input. Without a data type, a number is keyed by its value so that it matches
+-- however it is written.
+function bayes.Key(x, dataType)
+    if dataType ~= nil then
+        return tree.Cast(x, dataType)
+    end
+
+    local n = tonumber(x)
+    if n ~= nil and n - n == 0 then
+        return n
+    end
+    return tostring(x)
+end
+
+-- Probability returns the conditional probability of the value of the input for the target
+-- value, or nil if the value of a discrete input was not seen during the training.
+function bayes.Probability(m, input, x, j)
+    if input.counts ~= nil then
+        local key = bayes.Key(x, input.dataType)
+        if key == nil then
+            return nil
+        end
+
+        local counts = input.counts[key]
+        if counts == nil then
+            return nil
+        end
+        if m.classes[j].count == 0 then
+            return 0
+        end
+        return counts[j] / m.classes[j].count
+    end
+
+    local stat = input.stats[j]
+    local fn = bayes.distributions[stat[1]]
+    if fn == nil then
+        return 0
+    end
+    return fn(x, stat[2], stat[3])
+end
+
+-- The probability functions of the distributions, by their name
+bayes.distributions = {
+    gaussian = function(x, mean, variance)
+        local d = x - mean
+        return math.exp(-d * d / (2 * variance)) / math.sqrt(2 * math.pi * variance)
+    end,
+    poisson = function(x, mean)
+        local k = math.floor(x)
+        if k < 0 then
+            return 0
+        end
+
+        local p = math.exp(-mean)
+        for i=1, k do
+            p = p * mean / i
+        end
+        return p
+    end,
+}
+
+return bayes
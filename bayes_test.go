@@ -0,0 +1,151 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaiveBayesModel(t *testing.T) {
+	gaussian := func(x, mean, variance float64) float64 {
+		return math.Exp(-(x-mean)*(x-mean)/(2*variance)) / math.Sqrt(2*math.Pi*variance)
+	}
+	poisson := func(k int, mean float64) float64 {
+		p := math.Exp(-mean)
+		for i := 1; i <= k; i++ {
+			p = p * mean / float64(i)
+		}
+		return p
+	}
+
+	td := []struct {
+		input map[string]interface{}
+		low   float64
+		high  float64
+	}{
+		{
+			input: map[string]interface{}{"gender": "male", "age": 25, "income": 4000, "accidents": 1},
+			low:   60 * 20 / 60.0 * 10 / 60.0 * gaussian(4000, 5000, 1e6) * poisson(1, 0.5),
+			high:  40 * 30 / 40.0 * 40 / 40.0 * gaussian(4000, 3000, 4e6) * poisson(1, 2),
+		},
+		{
+			input: map[string]interface{}{"gender": "female", "age": 45, "income": 6000, "accidents": 0},
+			low:   60 * 40 / 60.0 * 50 / 60.0 * gaussian(6000, 5000, 1e6) * poisson(0, 0.5),
+			high:  40 * 10 / 40.0 * 0.001 * gaussian(6000, 3000, 4e6) * poisson(0, 2),
+		},
+		{
+			input: map[string]interface{}{"gender": "male", "accidents": 3},
+			low:   60 * 20 / 60.0 * poisson(3, 0.5),
+			high:  40 * 30 / 40.0 * poisson(3, 2),
+		},
+		{
+			input: map[string]interface{}{},
+			low:   60,
+			high:  40,
+		},
+	}
+
+	b, err := ioutil.ReadFile("fixtures/bayes1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	for _, tt := range td {
+		r, err := runScript(s, tt.input)
+		assert.NoError(t, err)
+
+		low, high := tt.low/(tt.low+tt.high), tt.high/(tt.low+tt.high)
+		assert.InDelta(t, low, r.Probability("low"), 1e-9, tt.input)
+		assert.InDelta(t, high, r.Probability("high"), 1e-9, tt.input)
+		assert.InDelta(t, high, r.Output("ProbabilityHigh"), 1e-9, tt.input)
+		if low >= high {
+			assert.Equal(t, "low", r.Value, tt.input)
+		} else {
+			assert.Equal(t, "high", r.Value, tt.input)
+		}
+	}
+}
+
+func TestNaiveBayesModel_Error(t *testing.T) {
+	counts := `<TargetValueCounts><TargetValueCount value="a" count="1"/></TargetValueCounts>`
+	tests := []struct {
+		inputs string
+		output string
+		err    string
+	}{
+		{inputs: ``, output: ``, err: "naive bayes model x has no target value counts"},
+		{inputs: `<BayesInput fieldName="x"/>`, output: counts, err: "bayes input x has neither pair counts nor target value stats"},
+		{inputs: `<BayesInput fieldName="x"><TargetValueStats><TargetValueStat value="a"/></TargetValueStats></BayesInput>`, output: counts, err: "distribution of x is not supported"},
+		{inputs: `<BayesInput fieldName="x"><PairCounts value="abc">` + counts + `</PairCounts></BayesInput>`, output: counts, err: "value 'abc' can not be converted to integer"},
+	}
+
+	for _, tc := range tests {
+		_, err := Convert([]byte(`<PMML version="4.4">
+			<DataDictionary><DataField name="x" optype="categorical" dataType="integer"/></DataDictionary>
+			<NaiveBayesModel modelName="x" functionName="classification" threshold="0.001"><MiningSchema/>
+				<BayesInputs>` + tc.inputs + `</BayesInputs><BayesOutput fieldName="y">` + tc.output + `</BayesOutput>
+			</NaiveBayesModel>
+		</PMML>`))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
+
+func TestNaiveBayesModel_NumericKeys(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/bayes1.xml")
+	assert.NoError(t, err)
+
+	original, err := Convert(b)
+	assert.NoError(t, err)
+
+	// The genders are numbers, written differently in the model and in the inputs
+	doc := strings.NewReplacer(
+		`name="gender" optype="categorical" dataType="string"`, `name="gender" optype="categorical" dataType="double"`,
+		`"male"`, `"1.0"`,
+		`"female"`, `"2"`,
+	).Replace(string(b))
+	numeric, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		gender interface{}
+		value  string
+	}{{1, "male"}, {"1", "male"}, {2.0, "female"}, {"2.0", "female"}} {
+		expect, err := runScript(makeScript(string(original)), map[string]interface{}{"gender": tc.value, "accidents": 1})
+		assert.NoError(t, err)
+
+		r, err := runScript(makeScript(string(numeric)), map[string]interface{}{"gender": tc.gender, "accidents": 1})
+		assert.NoError(t, err)
+		assert.InDelta(t, expect.Probability("high"), r.Probability("high"), 1e-9, tc.gender)
+	}
+}
+
+func TestNaiveBayesModel_StringKeys(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/bayes1.xml")
+	assert.NoError(t, err)
+
+	original, err := Convert(b)
+	assert.NoError(t, err)
+
+	// The genders are strings which look like numbers, so they must not be keyed by their value
+	for _, genders := range [][2]string{{"007", "7"}, {"0x10", "16"}} {
+		doc := strings.NewReplacer(`"male"`, `"`+genders[0]+`"`, `"female"`, `"`+genders[1]+`"`).Replace(string(b))
+		code, err := Convert([]byte(doc))
+		assert.NoError(t, err)
+		assert.Contains(t, string(code), "['"+genders[0]+"']=")
+		assert.Contains(t, string(code), "['"+genders[1]+"']=")
+
+		for i, value := range []string{"male", "female"} {
+			expect, err := runScript(makeScript(string(original)), map[string]interface{}{"gender": value, "accidents": 1})
+			assert.NoError(t, err)
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"gender": genders[i], "accidents": 1})
+			assert.NoError(t, err)
+			assert.InDelta(t, expect.Probability("high"), r.Probability("high"), 1e-9, genders[i])
+		}
+	}
+}
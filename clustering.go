@@ -0,0 +1,156 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The distance and similarity measures of the comparison measures.
+var measures = map[string]string{
+	"euclidean":        "distance",
+	"squaredEuclidean": "distance",
+	"chebychev":        "distance",
+	"cityBlock":        "distance",
+	"minkowski":        "distance",
+	"simpleMatching":   "similarity",
+	"jaccard":          "similarity",
+	"tanimoto":         "similarity",
+	"binarySimilarity": "similarity",
+}
+
+// The functions which compare the values of a field.
+var compareFunctions = map[string]bool{
+	"absDiff":  true,
+	"gaussSim": true,
+	"delta":    true,
+	"equal":    true,
+}
+
+// ClusteringModel generates the LUA code for the element.
+func (s *Scope) ClusteringModel(v schema.ClusteringModel, global *Scope) *Scope {
+	options := NewStatement().ComparisonMeasure(v.ComparisonMeasure)
+	if v.ModelClass != "centerBased" {
+		options.Error("model class %s is not supported", v.ModelClass)
+	}
+
+	// The missing value weights of the center fields, which are all 1 by default
+	var weights []float64
+	if v.MissingValueWeights != nil {
+		var err error
+		if weights, err = v.MissingValueWeights.Floats(); err != nil {
+			options.Error("missing value weights of %s are invalid: %v", v.ModelName, err)
+		}
+	}
+
+	fields := NewScope()
+	count := 0
+	for _, f := range v.Fields {
+		if !f.IsCenterField {
+			continue
+		}
+
+		weight := 1.0
+		if count < len(weights) {
+			weight = weights[count]
+		}
+		fields.With(NewStatement().ClusteringField(f, v.ComparisonMeasure, weight))
+		count++
+	}
+
+	clusters := NewScope()
+	for i, c := range v.Clusters {
+		clusters.With(NewStatement().Cluster(c, i, count))
+	}
+
+	global.Require("clustering")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or clustering.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			fields,
+			Append("}, {"),
+			clusters,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// ComparisonMeasure generates the LUA code for the element, which is the opening of the table
+// with the kind and the measure along with its parameters.
+func (s *Statement) ComparisonMeasure(v schema.ComparisonMeasure) *Statement {
+	m := v.Measure
+	kind, ok := measures[m.Name]
+	switch {
+	case !ok:
+		s.Error("comparison measure %s is not supported", m.Name)
+	case v.Kind != "" && v.Kind != kind:
+		s.Error("comparison measure %s is not a %s", m.Name, v.Kind)
+	}
+
+	s.Append("{kind=").String(kind).Append(", measure=").String(m.Name)
+	switch m.Name {
+	case "minkowski":
+		if m.P <= 0 {
+			s.Error("minkowski measure requires a positive p-parameter")
+		}
+		s.Append(", p=%s", formatFloat(m.P))
+	case "binarySimilarity":
+		s.Append(", c={%s, %s, %s, %s}, d={%s, %s, %s, %s}",
+			formatFloat(m.C00), formatFloat(m.C01), formatFloat(m.C10), formatFloat(m.C11),
+			formatFloat(m.D00), formatFloat(m.D01), formatFloat(m.D10), formatFloat(m.D11))
+	}
+	return s
+}
+
+// ClusteringField generates the LUA code for the element. The compare function of the comparison
+// measure is used if the field does not specify its own.
+func (s *Statement) ClusteringField(v schema.ClusteringField, measure schema.ComparisonMeasure, missingWeight float64) *Statement {
+	compare := v.CompareFunction
+	if compare == "" {
+		compare = measure.CompareFunction
+	}
+
+	s.Append("{field=").String(v.Field).
+		Append(", weight=%s, missingWeight=%s, compare=", formatFloat(v.FieldWeight), formatFloat(missingWeight)).
+		String(compare)
+	if !compareFunctions[compare] {
+		s.Error("compare function %s is not supported", compare)
+	}
+
+	if v.SimilarityScale != nil {
+		s.Append(", scale=%s", formatFloat(*v.SimilarityScale))
+	} else if compare == "gaussSim" {
+		s.Error("field %s requires a similarity scale", v.Field)
+	}
+	return s.Append("},")
+}
+
+// Cluster generates the LUA code for the element. The clusters without an identifier are
+// identified by their 1-based index.
+func (s *Statement) Cluster(v schema.Cluster, index, size int) *Statement {
+	id := v.ID
+	if id == "" {
+		id = formatFloat(float64(index + 1))
+	}
+
+	if v.Array == nil {
+		return s.Error("cluster %s has no center", id)
+	}
+
+	center, err := v.Array.Floats()
+	switch {
+	case err != nil:
+		return s.Error("center of cluster %s is invalid: %v", id, err)
+	case len(center) != size:
+		return s.Error("center of cluster %s has %d values instead of %d", id, len(center), size)
+	}
+
+	s.Append("{id=").String(id)
+	if v.Name != "" {
+		s.Append(", name=").String(v.Name)
+	}
+
+	return s.Append(", center=").Floats(center).Append("},")
+}
@@ -0,0 +1,160 @@
+local clustering = {}
+
+-- NewModel creates a clustering model with its comparison measure, its center fields and its
+-- clusters. Each field contains its weight, its missing value weight and its compare function,
+-- and each cluster contains its id, its name and its center.
+function clustering.NewModel(options, fields, clusters)
+    local m = options
+    m.fields = fields
+    m.clusters = clusters
+
+    -- Function which compares the record with the clusters and returns the result table
+    m.eval = function(v)
+        return clustering.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the cluster which is the closest to the record, or the most similar one, along
+-- with the affinity of the record to every cluster. If all of the fields are missing, there is
+-- no prediction.
+function clustering.Score(m, v)
+    local x, present = {}, false
+    for i=1, #m.fields do
+        local value = v[m.fields[i].field]
+        if not Unknown(value) then
+            x[i] = value
+            present = true
+        end
+    end
+    if not present then
+        return nil
+    end
+
+    local r = {affinities = {}}
+    local best, score = nil, nil
+    for i=1, #m.clusters do
+        local c = m.clusters[i]
+        local d = clustering.Measure(m, m.fields, x, c.center)
+        r.affinities[c.id] = d
+        if best == nil or (m.kind == 'distance' and d < score) or (m.kind == 'similarity' and d > score) then
+            best = c
+            score = d
+        end
+    end
+
+    if best ~= nil then
+        r.value = best.id
+        r.entityId = best.id
+        r.displayValue = best.name
+        r.affinity = score
+    end
+    return r
+end
+
+-- Measure returns the distance or the similarity between the values of the record and the values
+-- of a center or an instance. The missing values are ignored, and the distance is adjusted by
+-- the ratio of the missing value weights of all of the fields to the weights of the fields
+-- which are present.
+function clustering.Measure(m, fields, x, y)
+    if m.kind == 'similarity' then
+        return clustering.Similarity(m, fields, x, y)
+    end
+
+    local sum, max, total, present = 0, 0, 0, 0
+    for i=1, #fields do
+        local f = fields[i]
+        local missingWeight = f.missingWeight or 1
+        total = total + missingWeight
+        if x[i] ~= nil then
+            present = present + missingWeight
+            local c = math.abs(clustering.Compare(f, x[i], y[i]))
+            if m.measure == 'chebychev' then
+                max = math.max(max, f.weight * c)
+            elseif m.measure == 'cityBlock' then
+                sum = sum + f.weight * c
+            elseif m.measure == 'minkowski' then
+                sum = sum + f.weight * c ^ m.p
+            else
+                sum = sum + f.weight * c * c
+            end
+        end
+    end
+
+    local adjust = 1
+    if present > 0 then
+        adjust = total / present
+    end
+
+    if m.measure == 'euclidean' then
+        return math.sqrt(sum * adjust)
+    elseif m.measure == 'chebychev' then
+        return max * adjust
+    elseif m.measure == 'minkowski' then
+        return (sum * adjust) ^ (1 / m.p)
+    end
+    return sum * adjust
+end
+
+-- Compare returns the comparison of two values of a field, according to its compare function.
+function clustering.Compare(f, x, y)
+    if f.compare == 'gaussSim' then
+        local d = x - y
+        return math.exp(-math.log(2) * d * d / (f.scale * f.scale))
+    elseif f.compare == 'delta' then
+        if x == y then
+            return 0
+        end
+        return 1
+    elseif f.compare == 'equal' then
+        if x == y then
+            return 1
+        end
+        return 0
+    end
+    return x - y
+end
+
+-- Similarity returns the similarity between two binary vectors, which depends on the number
+-- of fields where both values are 1, where only one of them is 1 and where both are 0.
+function clustering.Similarity(m, fields, x, y)
+    local a00, a01, a10, a11 = 0, 0, 0, 0
+    for i=1, #fields do
+        if x[i] ~= nil then
+            local a, b = clustering.Binary(x[i]), clustering.Binary(y[i])
+            if a and b then
+                a11 = a11 + 1
+            elseif a then
+                a10 = a10 + 1
+            elseif b then
+                a01 = a01 + 1
+            else
+                a00 = a00 + 1
+            end
+        end
+    end
+
+    local n, d = 0, 0
+    if m.measure == 'simpleMatching' then
+        n, d = a11 + a00, a11 + a10 + a01 + a00
+    elseif m.measure == 'jaccard' then
+        n, d = a11, a11 + a10 + a01
+    elseif m.measure == 'tanimoto' then
+        n, d = a11 + a00, a11 + 2 * (a10 + a01) + a00
+    else
+        n = m.c[1] * a00 + m.c[2] * a01 + m.c[3] * a10 + m.c[4] * a11
+        d = m.d[1] * a00 + m.d[2] * a01 + m.d[3] * a10 + m.d[4] * a11
+    end
+
+    if d == 0 then
+        return 0
+    end
+    return n / d
+end
+
+-- Binary returns whether the value is set in a binary vector.
+function clustering.Binary(x)
+    return x == 1 or x == true
+end
+
+return clustering
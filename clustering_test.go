@@ -0,0 +1,215 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusteringModel(t *testing.T) {
+	centers := map[string][]float64{"small": {1.6, 55}, "medium": {1.75, 70}, "large": {1.9, 95}}
+	names := map[string]string{"small": "Small people", "medium": "Medium people"}
+	weights, missingWeights := []float64{2, 1}, []float64{2, 1}
+
+	// distance computes the expected distance of the record to the center, where the missing
+	// values are represented by NaN
+	distance := func(measure string, x, y []float64) float64 {
+		sum, max, total, present := 0.0, 0.0, 0.0, 0.0
+		for i := range x {
+			total += missingWeights[i]
+			if math.IsNaN(x[i]) {
+				continue
+			}
+
+			present += missingWeights[i]
+			c := math.Abs(x[i] - y[i])
+			switch measure {
+			case "chebychev":
+				max = math.Max(max, weights[i]*c)
+			case "cityBlock":
+				sum += weights[i] * c
+			case "minkowski":
+				sum += weights[i] * math.Pow(c, 3)
+			default:
+				sum += weights[i] * c * c
+			}
+		}
+
+		adjust := total / present
+		switch measure {
+		case "euclidean":
+			return math.Sqrt(sum * adjust)
+		case "chebychev":
+			return max * adjust
+		case "minkowski":
+			return math.Pow(sum*adjust, 1/3.0)
+		default:
+			return sum * adjust
+		}
+	}
+
+	b, err := ioutil.ReadFile("fixtures/clustering1.xml")
+	assert.NoError(t, err)
+
+	for _, measure := range []string{"euclidean", "squaredEuclidean", "chebychev", "cityBlock", "minkowski"} {
+		t.Run(measure, func(t *testing.T) {
+			element := "<" + measure + "/>"
+			if measure == "minkowski" {
+				element = `<minkowski p-parameter="3"/>`
+			}
+
+			code, err := Convert([]byte(strings.Replace(string(b), "<euclidean/>", element, 1)))
+			assert.NoError(t, err)
+
+			s := makeScript(string(code))
+			for _, x := range [][]float64{{1.65, 60}, {1.8, 80}, {2, 90}, {math.NaN(), 71}, {1.62, math.NaN()}} {
+				input := map[string]interface{}{"age": 30}
+				if !math.IsNaN(x[0]) {
+					input["height"] = x[0]
+				}
+				if !math.IsNaN(x[1]) {
+					input["weight"] = x[1]
+				}
+
+				best, score := "", 0.0
+				r, err := runScript(s, input)
+				assert.NoError(t, err)
+				for _, id := range []string{"small", "medium", "large"} {
+					d := distance(measure, x, centers[id])
+					assert.InDelta(t, d, r.Affinity(id), 1e-9, x)
+					if best == "" || d < score {
+						best, score = id, d
+					}
+				}
+
+				assert.Equal(t, best, r.Value, x)
+				assert.Equal(t, best, r.EntityID, x)
+				assert.Equal(t, names[best], r.DisplayValue, x)
+				assert.InDelta(t, score, r.Output("Affinity"), 1e-9, x)
+				assert.InDelta(t, r.Affinity("small"), r.Output("AffinityToSmall"), 1e-9, x)
+				if best == "large" {
+					assert.Equal(t, "large", r.Output("Name"), x)
+				} else {
+					assert.Equal(t, names[best], r.Output("Name"), x)
+				}
+			}
+
+			r, err := runScript(s, map[string]interface{}{"age": 30})
+			assert.NoError(t, err)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestClusteringModel_CompareFunction(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<ClusteringModel modelName="compare" functionName="clustering" modelClass="centerBased">
+			<MiningSchema><MiningField name="x"/><MiningField name="y"/></MiningSchema>
+			<ComparisonMeasure kind="distance" compareFunction="delta"><cityBlock/></ComparisonMeasure>
+			<ClusteringField field="x" compareFunction="gaussSim" similarityScale="2"/>
+			<ClusteringField field="y"/>
+			<Cluster><Array n="2" type="real">1 0</Array></Cluster>
+			<Cluster><Array n="2" type="real">3 1</Array></Cluster>
+		</ClusteringModel>
+	</PMML>`))
+	assert.NoError(t, err)
+
+	gauss := func(d float64) float64 { return math.Exp(-math.Ln2 * d * d / 4) }
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 2, "y": 1})
+	assert.NoError(t, err)
+	assert.InDelta(t, gauss(1)+1, r.Affinity("1"), 1e-9)
+	assert.InDelta(t, gauss(-1)+0, r.Affinity("2"), 1e-9)
+	assert.Equal(t, "2", r.Value)
+}
+
+func TestClusteringModel_Similarity(t *testing.T) {
+	input := map[string]interface{}{"a": 1, "b": 1, "c": 0, "d": 0}
+	centers := [][4]float64{{1, 0, 1, 0}, {1, 1, 1, 1}}
+
+	td := []struct {
+		measure string
+		expect  func(a00, a01, a10, a11 float64) float64
+	}{
+		{measure: "<simpleMatching/>", expect: func(a00, a01, a10, a11 float64) float64 {
+			return (a11 + a00) / (a11 + a10 + a01 + a00)
+		}},
+		{measure: "<jaccard/>", expect: func(a00, a01, a10, a11 float64) float64 {
+			return a11 / (a11 + a10 + a01)
+		}},
+		{measure: "<tanimoto/>", expect: func(a00, a01, a10, a11 float64) float64 {
+			return (a11 + a00) / (a11 + 2*(a10+a01) + a00)
+		}},
+		{measure: `<binarySimilarity c00-parameter="1" c01-parameter="0" c10-parameter="0" c11-parameter="2" d00-parameter="1" d01-parameter="1" d10-parameter="1" d11-parameter="1"/>`, expect: func(a00, a01, a10, a11 float64) float64 {
+			return (a00 + 2*a11) / (a00 + a01 + a10 + a11)
+		}},
+	}
+
+	for _, tc := range td {
+		code, err := Convert([]byte(`<PMML version="4.4">
+			<ClusteringModel modelName="binary" functionName="clustering" modelClass="centerBased">
+				<MiningSchema><MiningField name="a"/><MiningField name="b"/><MiningField name="c"/><MiningField name="d"/></MiningSchema>
+				<ComparisonMeasure kind="similarity">` + tc.measure + `</ComparisonMeasure>
+				<ClusteringField field="a"/><ClusteringField field="b"/><ClusteringField field="c"/><ClusteringField field="d"/>
+				<Cluster id="first"><Array n="4" type="real">1 0 1 0</Array></Cluster>
+				<Cluster id="second"><Array n="4" type="real">1 1 1 1</Array></Cluster>
+			</ClusteringModel>
+		</PMML>`))
+		assert.NoError(t, err)
+
+		r, err := runScript(makeScript(string(code)), input)
+		assert.NoError(t, err)
+
+		best, score := "", 0.0
+		for i, id := range []string{"first", "second"} {
+			var a00, a01, a10, a11 float64
+			for j, name := range []string{"a", "b", "c", "d"} {
+				x, y := input[name] == 1, centers[i][j] == 1
+				switch {
+				case x && y:
+					a11++
+				case x:
+					a10++
+				case y:
+					a01++
+				default:
+					a00++
+				}
+			}
+
+			expect := tc.expect(a00, a01, a10, a11)
+			assert.InDelta(t, expect, r.Affinity(id), 1e-9, tc.measure)
+			if best == "" || expect > score {
+				best, score = id, expect
+			}
+		}
+		assert.Equal(t, best, r.Value, tc.measure)
+	}
+}
+
+func TestClusteringModel_Error(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/clustering1.xml")
+	assert.NoError(t, err)
+
+	doc := string(b)
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(doc, `modelClass="centerBased"`, `modelClass="distributionBased"`, 1), err: "model class distributionBased is not supported"},
+		{doc: strings.Replace(doc, `<euclidean/>`, `<hamming/>`, 1), err: "comparison measure hamming is not supported"},
+		{doc: strings.Replace(doc, `kind="distance"`, `kind="similarity"`, 1), err: "comparison measure euclidean is not a similarity"},
+		{doc: strings.Replace(doc, `<euclidean/>`, `<minkowski/>`, 1), err: "minkowski measure requires a positive p-parameter"},
+		{doc: strings.Replace(doc, `<Array n="2" type="real">2 1</Array>`, `<Array n="2" type="real">2 x</Array>`, 1), err: "missing value weights of people are invalid"},
+		{doc: strings.Replace(doc, `<ClusteringField field="weight"/>`, `<ClusteringField field="weight" compareFunction="table"/>`, 1), err: "compare function table is not supported"},
+		{doc: strings.Replace(doc, `<ClusteringField field="weight"/>`, `<ClusteringField field="weight" compareFunction="gaussSim"/>`, 1), err: "field weight requires a similarity scale"},
+		{doc: strings.Replace(doc, `<Array n="2" type="real">1.9 95</Array>`, ``, 1), err: "cluster large has no center"},
+		{doc: strings.Replace(doc, `<Array n="2" type="real">1.9 95</Array>`, `<Array n="2" type="real">1.9 x</Array>`, 1), err: "center of cluster large is invalid"},
+		{doc: strings.Replace(doc, `<Array n="2" type="real">1.9 95</Array>`, `<Array n="1" type="real">1.9</Array>`, 1), err: "center of cluster large has 1 values instead of 2"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,146 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// Expression generates the LUA code for the element.
+func (s *Statement) Expression(v *schema.Expression, global *Scope) *Statement {
+	if v == nil {
+		return s.Error(errNilElement.Error())
+	}
+
+	// The expressions are evaluated by the functions of the expression module
+	global.Require("expression")
+	switch {
+	case v.Constant != nil:
+		return s.Constant(*v.Constant)
+	case v.FieldRef != nil:
+		return s.FieldRef(*v.FieldRef, global)
+	case v.NormContinuous != nil:
+		return s.NormContinuous(*v.NormContinuous)
+	case v.NormDiscrete != nil:
+		return s.NormDiscrete(*v.NormDiscrete, global)
+	case v.Discretize != nil:
+		return s.Discretize(*v.Discretize)
+	case v.Apply != nil:
+		return s.Apply(*v.Apply, global)
+	}
+
+	return s.Error("expression is not supported")
+}
+
+// Constant generates the LUA code for the element.
+func (s *Statement) Constant(v schema.Constant) *Statement {
+	if v.Missing {
+		return s.Append("nil")
+	}
+	return s.Literal(v.Value, v.DataType)
+}
+
+// FieldRef generates the LUA code for the element.
+func (s *Statement) FieldRef(v schema.FieldRef, global *Scope) *Statement {
+	if v.MapMissingTo == "" {
+		return s.Field(v.Field)
+	}
+
+	field, _ := global.DataField(v.Field)
+	return s.Append("expression.Coalesce(").Field(v.Field).Append(", ").
+		Literal(v.MapMissingTo, field.DataType).Append(")")
+}
+
+// NormContinuous generates the LUA code for the element.
+func (s *Statement) NormContinuous(v schema.NormContinuous) *Statement {
+	if len(v.LinearNorms) < 2 {
+		return s.Error("normalization of %s requires at least two linear norms", v.Field)
+	}
+
+	s.Append("expression.NormContinuous(").Field(v.Field).Append(", {")
+	for i, n := range v.LinearNorms {
+		s.Append("{%s, %s}", formatFloat(n.Orig), formatFloat(n.Norm))
+		if i+1 < len(v.LinearNorms) {
+			s.Append(", ")
+		}
+	}
+
+	s.Append("}, ").String(v.Outliers)
+	if v.MapMissingTo != "" {
+		s.Append(", ").Value(v.MapMissingTo)
+	}
+	return s.Append(")")
+}
+
+// NormDiscrete generates the LUA code for the element.
+func (s *Statement) NormDiscrete(v schema.NormDiscrete, global *Scope) *Statement {
+	field, _ := global.DataField(v.Field)
+	s.Append("expression.NormDiscrete(").Field(v.Field).Append(", ").Literal(v.Value, field.DataType)
+	if v.MapMissingTo != "" {
+		s.Append(", ").Value(v.MapMissingTo)
+	}
+	return s.Append(")")
+}
+
+// Discretize generates the LUA code for the element.
+func (s *Statement) Discretize(v schema.Discretize) *Statement {
+	s.Append("expression.Discretize(").Field(v.Field).Append(", {")
+	for i, bin := range v.Bins {
+		s.Append("{").String(bin.Interval.Closure).
+			Append(", %s, %s, ", margin(bin.Interval.LeftMargin, -1), margin(bin.Interval.RightMargin, 1)).
+			Literal(bin.BinValue, v.DataType).
+			Append("}")
+		if i+1 < len(v.Bins) {
+			s.Append(", ")
+		}
+	}
+
+	s.Append("}, ").Optional(v.DefaultValue, v.DataType)
+	if v.MapMissingTo != "" {
+		s.Append(", ").Literal(v.MapMissingTo, v.DataType)
+	}
+	return s.Append(")")
+}
+
+// Apply generates the LUA code for the element. The arguments are evaluated eagerly and the
+// built-in functions are looked up by their name at runtime, while the user-defined functions
+// of the transformation dictionary are passed along with the options.
+func (s *Statement) Apply(v schema.Apply, global *Scope) *Statement {
+	defined := global.Require("expression").functions[v.Function]
+	s.Append("expression.Apply(").String(v.Function).Append(", {")
+	for i := range v.Expressions {
+		s.Expression(&v.Expressions[i], global)
+		if i+1 < len(v.Expressions) {
+			s.Append(", ")
+		}
+	}
+	if len(v.Expressions) > 0 {
+		s.Append("; ")
+	}
+	s.Append("n=%d}", len(v.Expressions))
+
+	if v.MapMissingTo == "" && v.DefaultValue == "" && v.InvalidValueTreatment == "" && !defined {
+		return s.Append(")")
+	}
+
+	s.Append(", {")
+	if defined {
+		s.Append("fn=functions[").String(v.Function).Append("], ")
+	}
+	if v.MapMissingTo != "" {
+		s.Append("missing=").Value(v.MapMissingTo).Append(", ")
+	}
+	if v.DefaultValue != "" {
+		s.Append("default=").Value(v.DefaultValue).Append(", ")
+	}
+	if v.InvalidValueTreatment != "" {
+		s.Append("invalid=").String(v.InvalidValueTreatment)
+	}
+	return s.Append("})")
+}
+
+// Optional writes the value with the data type, or nil if the value is not specified.
+func (s *Statement) Optional(v schema.Value, dataType string) *Statement {
+	if v == "" {
+		return s.Append("nil")
+	}
+	return s.Literal(v, dataType)
+}
@@ -0,0 +1,507 @@
+local tree = require("tree")
+local expression = {}
+
+-- Coalesce returns the value, or the replacement if the value is missing.
+function expression.Coalesce(x, replacement)
+    if Unknown(x) then
+        return replacement
+    end
+    return x
+end
+
+-- NormContinuous normalizes the number with the piecewise linear function defined by the
+-- norms, which are pairs of original and normalized values sorted by the original value.
+function expression.NormContinuous(x, norms, outliers, missing)
+    if Unknown(x) then
+        return missing
+    end
+
+    local first, last = norms[1], norms[#norms]
+    if x < first[1] or x > last[1] then
+        if outliers == 'asMissingValues' then
+            return missing
+        elseif outliers == 'asExtremeValues' then
+            return x < first[1] and first[2] or last[2]
+        end
+    end
+
+    -- Find the segment of the function, extrapolating from the first or the last one
+    local i = 2
+    while i < #norms and x > norms[i][1] do
+        i = i + 1
+    end
+
+    local a, b = norms[i-1], norms[i]
+    return a[2] + (x - a[1]) / (b[1] - a[1]) * (b[2] - a[2])
+end
+
+-- NormDiscrete returns 1 if the value is equal to the category and 0 otherwise.
+function expression.NormDiscrete(x, category, missing)
+    if Unknown(x) then
+        return missing
+    end
+    if x == category then
+        return 1
+    end
+    return 0
+end
+
+-- Discretize returns the value of the first bin whose interval contains the number. The bins
+-- are tables of the closure, the left and right margins and the value of the bin.
+function expression.Discretize(x, bins, default, missing)
+    if Unknown(x) then
+        return missing
+    end
+
+    x = tonumber(x)
+    for i=1, #bins do
+        if x ~= nil and tree.InInterval(x, bins[i]) then
+            return bins[i][4]
+        end
+    end
+    return default
+end
+
+-- Apply calls the function with the arguments, which is a table with n elements. The missing
+-- and default values are returned when an argument or the result is missing respectively.
+function expression.Apply(name, args, options)
+    options = options or {}
+    local fn = options.fn or expression.functions[name]
+    if fn == nil then
+        error("function '" .. name .. "' is not supported")
+    end
+
+    if not expression.missingAware[name] then
+        for i=1, args.n do
+            if Unknown(args[i]) then
+                return options.missing
+            end
+        end
+    end
+
+    -- The result is invalid when it is NaN or infinite, for which x - x is not zero
+    local ok, x = pcall(fn, unpack(args, 1, args.n))
+    if not ok or (type(x) == 'number' and x - x ~= 0) then
+        if options.invalid == 'asMissing' then
+            return options.default
+        elseif options.invalid == 'asIs' and ok then
+            return x
+        end
+        error("function '" .. name .. "' returned an invalid value")
+    end
+
+    if x == nil then
+        return options.default
+    end
+    return x
+end
+
+-- The functions which handle the missing values of their arguments themselves
+expression.missingAware = {
+    isMissing = true, isNotMissing = true, ['if'] = true,
+    ['and'] = true, ['or'] = true, coalesce = true,
+}
+
+-- Fold reduces the arguments with the binary function.
+local function fold(fn)
+    return function(...)
+        local args = {...}
+        local x = args[1]
+        for i=2, #args do
+            x = fn(x, args[i])
+        end
+        return x
+    end
+end
+
+-- Erf returns the error function, computed with its Taylor series near zero and with the
+-- continued fraction of the complementary error function elsewhere.
+function expression.Erf(x)
+    local ax = math.abs(x)
+    if ax < 3 then
+        local sum, term, n = x, x, 0
+        repeat
+            n = n + 1
+            term = -term * x * x / n
+            sum = sum + term / (2 * n + 1)
+        until math.abs(term / (2 * n + 1)) <= 1e-17 * math.abs(sum)
+        return 2 / math.sqrt(math.pi) * sum
+    end
+
+    local f = ax
+    for k=60, 1, -1 do
+        f = ax + (k / 2) / f
+    end
+
+    local y = 1 - math.exp(-ax * ax) / math.sqrt(math.pi) / f
+    if x < 0 then
+        return -y
+    end
+    return y
+end
+
+-- NormalCDF returns the cumulative distribution function of the standard normal distribution.
+function expression.NormalCDF(x)
+    return 0.5 * (1 + expression.Erf(x / math.sqrt(2)))
+end
+
+-- NormalIDF returns the inverse of the cumulative distribution function of the standard normal
+-- distribution, using the rational approximation of Acklam refined by a Newton step.
+function expression.NormalIDF(p)
+    if p <= 0 then
+        return -1/0
+    elseif p >= 1 then
+        return 1/0
+    end
+
+    local a = {-39.69683028665376, 220.9460984245205, -275.9285104469687, 138.3577518672690, -30.66479806614716, 2.506628277459239}
+    local b = {-54.47609879822406, 161.5858368580409, -155.6989798598866, 66.80131188771972, -13.28068155288572}
+    local c = {-0.007784894002430293, -0.3223964580411365, -2.400758277161838, -2.549732539343734, 4.374664141464968, 2.938163982698783}
+    local d = {0.007784695709041462, 0.3224671290700398, 2.445134137142996, 3.754408661907416}
+
+    local x
+    if p < 0.02425 then
+        local q = math.sqrt(-2 * math.log(p))
+        x = (((((c[1]*q+c[2])*q+c[3])*q+c[4])*q+c[5])*q+c[6]) / ((((d[1]*q+d[2])*q+d[3])*q+d[4])*q+1)
+    elseif p > 1 - 0.02425 then
+        local q = math.sqrt(-2 * math.log(1 - p))
+        x = -(((((c[1]*q+c[2])*q+c[3])*q+c[4])*q+c[5])*q+c[6]) / ((((d[1]*q+d[2])*q+d[3])*q+d[4])*q+1)
+    else
+        local q = p - 0.5
+        local r = q * q
+        x = (((((a[1]*r+a[2])*r+a[3])*r+a[4])*r+a[5])*r+a[6])*q / (((((b[1]*r+b[2])*r+b[3])*r+b[4])*r+b[5])*r+1)
+    end
+
+    local e = expression.NormalCDF(x) - p
+    return x - e * math.sqrt(2 * math.pi) * math.exp(x * x / 2)
+end
+
+-- Round rounds the number half away from zero.
+local function round(x)
+    if x < 0 then
+        return -math.floor(-x + 0.5)
+    end
+    return math.floor(x + 0.5)
+end
+
+-- The escapes of the regular expressions and their equivalent in LUA patterns, where a word
+-- character also includes the underscore.
+local regexClasses = {
+    d = '%d', D = '%D', s = '%s', S = '%S', w = '[%w_]', W = '[^%w_]',
+}
+local regexEscapes = {t = '\t', n = '\n', r = '\r', f = '\f'}
+
+-- The translated regular expressions, by their source
+local patterns = {}
+
+-- Literal returns the LUA pattern which matches the character, with both cases of a letter
+-- when the expression is case-insensitive.
+local function literal(c, ci)
+    if ci and c:match('%a') then
+        return '[' .. c:lower() .. c:upper() .. ']'
+    elseif c:match('%W') then
+        return '%' .. c
+    end
+    return c
+end
+
+-- Pattern translates the regular expression into the LUA patterns of its alternatives, as the
+-- LUA patterns have no alternation. The constructs without equivalent raise an error.
+function expression.Pattern(re)
+    if patterns[re] then
+        return patterns[re]
+    end
+
+    local function fail(reason)
+        error("regular expression '" .. re .. "' is not supported: " .. reason)
+    end
+
+    local out, items, depth, ci, i = {}, {}, 0, false, 1
+    if re:sub(1, 4) == '(?i)' then
+        ci, i = true, 5
+    end
+
+    -- Class translates the bracket expression which starts at the position
+    local function class(j)
+        local set = {'['}
+        if re:sub(j, j) == '^' then
+            set[2], j = '^', j + 1
+        end
+
+        local first = true
+        while true do
+            local c = re:sub(j, j)
+            if c == '' then
+                fail('unterminated class')
+            elseif c == ']' and not first then
+                return table.concat(set) .. ']', j
+            elseif c == '[' then
+                fail('nested class')
+            end
+
+            first = false
+            if c == '\\' then
+                j = j + 1
+                local e = re:sub(j, j)
+                if e == 'd' or e == 's' or e == 'w' then
+                    table.insert(set, e == 'w' and '%w_' or '%' .. e)
+                elseif regexEscapes[e] then
+                    table.insert(set, regexEscapes[e])
+                elseif e == '' or e:match('%w') then
+                    fail('escape \\' .. e .. ' in a class')
+                else
+                    table.insert(set, '%' .. e)
+                end
+            elseif re:sub(j + 1, j + 1) == '-' and re:sub(j + 2, j + 2) ~= ']' and re:sub(j + 2, j + 2) ~= '' then
+                local e = re:sub(j + 2, j + 2)
+                if not (c:match('%w') and e:match('%w')) then
+                    fail('range ' .. c .. '-' .. e)
+                end
+                table.insert(set, c .. '-' .. e)
+                if ci and c:match('%a') and e:match('%a') then
+                    local swap = c:match('%l') and string.upper or string.lower
+                    table.insert(set, swap(c) .. '-' .. swap(e))
+                end
+                j = j + 2
+            elseif ci and c:match('%a') then
+                table.insert(set, c:lower() .. c:upper())
+            else
+                table.insert(set, c:match('%W') and '%' .. c or c)
+            end
+            j = j + 1
+        end
+    end
+
+    -- Quantify applies the quantifier which follows the item, if any
+    local function quantify(item, j)
+        local q = re:sub(j, j)
+        if q == '*' or q == '+' or q == '?' then
+            local lazy = re:sub(j + 1, j + 1) == '?'
+            if not lazy then
+                return item .. q, j + 1
+            elseif q == '*' then
+                return item .. '-', j + 2
+            elseif q == '+' then
+                return item .. item .. '-', j + 2
+            end
+            fail('lazy optional')
+        elseif q == '{' then
+            local n, m, k = re:match('^{(%d+)(,?%d*)}()', j)
+            if n == nil then
+                fail('quantifier at ' .. j)
+            end
+
+            n = tonumber(n)
+            local s = string.rep(item, n)
+            if m == ',' then
+                s = s .. item .. '*'
+            elseif m ~= '' then
+                m = tonumber(m:sub(2))
+                if m < n then
+                    fail('quantifier {' .. n .. ',' .. m .. '}')
+                end
+                s = s .. string.rep(item .. '?', m - n)
+            end
+            if re:sub(k, k) == '?' then
+                fail('lazy quantifier')
+            end
+            return s, k
+        end
+        return item, j
+    end
+
+    while i <= #re do
+        local c, item = re:sub(i, i), nil
+        if c == '\\' then
+            local e = re:sub(i + 1, i + 1)
+            if regexClasses[e] then
+                item = regexClasses[e]
+            elseif regexEscapes[e] then
+                item = regexEscapes[e]
+            elseif e == '' or e:match('%w') then
+                fail('escape \\' .. e)
+            else
+                item = '%' .. e
+            end
+            i = i + 2
+        elseif c == '[' then
+            item, i = class(i + 1)
+            i = i + 1
+        elseif c == '.' then
+            item, i = '.', i + 1
+        elseif c == '(' then
+            if re:sub(i + 1, i + 1) == '?' then
+                fail('group ' .. re:sub(i, i + 2))
+            end
+            table.insert(items, '(')
+            depth, i = depth + 1, i + 1
+        elseif c == ')' then
+            if depth == 0 then
+                fail('unbalanced parenthesis')
+            elseif re:sub(i + 1, i + 1):match('[*+?{]') then
+                fail('quantified group')
+            end
+            table.insert(items, ')')
+            depth, i = depth - 1, i + 1
+        elseif c == '|' then
+            if depth > 0 then
+                fail('alternation in a group')
+            end
+            table.insert(out, table.concat(items))
+            items, i = {}, i + 1
+        elseif c == '^' and #items == 0 then
+            table.insert(items, '^')
+            i = i + 1
+        elseif c == '$' and (i == #re or re:sub(i + 1, i + 1) == '|') then
+            table.insert(items, '$')
+            i = i + 1
+        elseif c:match('[%^%$*+?{]') then
+            fail('character ' .. c .. ' at ' .. i)
+        else
+            item, i = literal(c, ci), i + 1
+        end
+
+        if item ~= nil then
+            item, i = quantify(item, i)
+            table.insert(items, item)
+        end
+    end
+
+    if depth > 0 then
+        fail('unbalanced parenthesis')
+    end
+
+    table.insert(out, table.concat(items))
+    patterns[re] = out
+    return out
+end
+
+-- Replacement translates the replacement of a regular expression, where the groups are
+-- referred to with a dollar sign and a backslash escapes the next character.
+function expression.Replacement(s)
+    local out, i = {}, 1
+    while i <= #s do
+        local c = s:sub(i, i)
+        if c == '\\' and i < #s then
+            c, i = s:sub(i + 1, i + 1), i + 1
+            table.insert(out, c == '%' and '%%' or c)
+        elseif c == '$' then
+            local n = s:match('^%d', i + 1)
+            if n == nil then
+                error("replacement '" .. s .. "' has an invalid group reference")
+            end
+            table.insert(out, '%' .. n)
+            i = i + 1
+        elseif c == '%' then
+            table.insert(out, '%%')
+        else
+            table.insert(out, c)
+        end
+        i = i + 1
+    end
+    return table.concat(out)
+end
+
+-- The built-in functions of the standard, by their name
+expression.functions = {
+    ['+'] = function(a, b) return a + b end,
+    ['-'] = function(a, b) return a - b end,
+    ['*'] = function(a, b) return a * b end,
+    ['/'] = function(a, b) return a / b end,
+    min = fold(math.min),
+    max = fold(math.max),
+    sum = fold(function(a, b) return a + b end),
+    product = fold(function(a, b) return a * b end),
+    avg = function(...) return expression.functions.sum(...) / select('#', ...) end,
+    median = function(...)
+        local args = {...}
+        table.sort(args)
+        local n = #args
+        if n % 2 == 1 then
+            return args[(n + 1) / 2]
+        end
+        return (args[n / 2] + args[n / 2 + 1]) / 2
+    end,
+    log10 = function(x) return math.log10(x) end,
+    ln = function(x) return math.log(x) end,
+    sqrt = function(x) return math.sqrt(x) end,
+    abs = function(x) return math.abs(x) end,
+    exp = function(x) return math.exp(x) end,
+    pow = function(x, y) return x ^ y end,
+    threshold = function(x, y) if x > y then return 1 end return 0 end,
+    floor = function(x) return math.floor(x) end,
+    ceil = function(x) return math.ceil(x) end,
+    round = round,
+    rint = round,
+    modulo = function(x, y) return x - math.floor(x / y) * y end,
+    expm1 = function(x) return math.exp(x) - 1 end,
+    ln1p = function(x) return math.log(1 + x) end,
+    sin = math.sin, cos = math.cos, tan = math.tan,
+    asin = math.asin, acos = math.acos, atan = math.atan, atan2 = math.atan2,
+    sinh = math.sinh, cosh = math.cosh, tanh = math.tanh,
+    hypot = function(x, y) return math.sqrt(x * x + y * y) end,
+    erf = expression.Erf,
+    stdNormalCDF = expression.NormalCDF,
+    stdNormalIDF = expression.NormalIDF,
+    stdNormalPDF = function(x) return math.exp(-x * x / 2) / math.sqrt(2 * math.pi) end,
+    normalCDF = function(x, mu, sigma) return expression.NormalCDF((x - mu) / sigma) end,
+    normalIDF = function(p, mu, sigma) return mu + sigma * expression.NormalIDF(p) end,
+    normalPDF = function(x, mu, sigma)
+        local z = (x - mu) / sigma
+        return math.exp(-z * z / 2) / (sigma * math.sqrt(2 * math.pi))
+    end,
+    isMissing = function(x) return Unknown(x) end,
+    isNotMissing = function(x) return not Unknown(x) end,
+    coalesce = function(...)
+        local args = {...}
+        for i=1, select('#', ...) do
+            if not Unknown(args[i]) then
+                return args[i]
+            end
+        end
+        return nil
+    end,
+    equal = function(a, b) return a == b end,
+    notEqual = function(a, b) return a ~= b end,
+    lessThan = function(a, b) return a < b end,
+    lessOrEqual = function(a, b) return a <= b end,
+    greaterThan = function(a, b) return a > b end,
+    greaterOrEqual = function(a, b) return a >= b end,
+    ['and'] = function(...) return tree.And({n = select('#', ...), ...}) end,
+    ['or'] = function(...) return tree.Or({n = select('#', ...), ...}) end,
+    ['not'] = function(x) return not x end,
+    isIn = function(x, ...) return tree.IsIn(x, {n = select('#', ...), ...}) end,
+    isNotIn = function(x, ...) return tree.IsNotIn(x, {n = select('#', ...), ...}) end,
+    ['if'] = function(cond, a, b)
+        if cond == nil then
+            return nil
+        elseif cond then
+            return a
+        end
+        return b
+    end,
+    uppercase = string.upper,
+    lowercase = string.lower,
+    stringLength = string.len,
+    substring = function(s, start, length) return string.sub(s, start, start + length - 1) end,
+    trimBlanks = function(s) return (string.gsub(s, "^%s*(.-)%s*$", "%1")) end,
+    concat = function(...) return table.concat({...}) end,
+    replace = function(s, pattern, replacement)
+        local alternatives = expression.Pattern(pattern)
+        if #alternatives > 1 then
+            error("regular expression '" .. pattern .. "' is not supported: alternation in a replacement")
+        end
+        return (string.gsub(s, alternatives[1], expression.Replacement(replacement)))
+    end,
+    matches = function(s, pattern)
+        for _, p in ipairs(expression.Pattern(pattern)) do
+            if string.find(s, p) ~= nil then
+                return true
+            end
+        end
+        return false
+    end,
+    formatNumber = function(x, format) return string.format(format, x) end,
+}
+
+return expression
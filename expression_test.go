@@ -0,0 +1,148 @@
+package pmml2lua
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+
+	"github.com/kelindar/pmml2lua/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpression(t *testing.T) {
+	td := []struct {
+		expr   string
+		input  map[string]interface{}
+		expect string
+	}{
+		{`<Constant dataType="integer">42</Constant>`, nil, "42"},
+		{`<Constant missing="true"/>`, nil, "(nil)"},
+		{`<FieldRef field="x"/>`, map[string]interface{}{"x": 3}, "3"},
+		{`<FieldRef field="x" mapMissingTo="7"/>`, nil, "7"},
+		{`<NormContinuous field="x"><LinearNorm orig="0" norm="0"/><LinearNorm orig="10" norm="1"/></NormContinuous>`, map[string]interface{}{"x": 5}, "0.5"},
+		{`<NormContinuous field="x"><LinearNorm orig="0" norm="0"/><LinearNorm orig="10" norm="1"/><LinearNorm orig="20" norm="3"/></NormContinuous>`, map[string]interface{}{"x": 25}, "4"},
+		{`<NormContinuous field="x" outliers="asExtremeValues"><LinearNorm orig="0" norm="0"/><LinearNorm orig="10" norm="1"/></NormContinuous>`, map[string]interface{}{"x": 15}, "1"},
+		{`<NormContinuous field="x" mapMissingTo="0.5"><LinearNorm orig="0" norm="0"/><LinearNorm orig="10" norm="1"/></NormContinuous>`, nil, "0.5"},
+		{`<NormDiscrete field="x" value="red"/>`, map[string]interface{}{"x": "red"}, "1"},
+		{`<NormDiscrete field="x" value="red"/>`, map[string]interface{}{"x": "blue"}, "0"},
+		{`<Discretize field="x" defaultValue="other">
+			<DiscretizeBin binValue="low"><Interval closure="openOpen" rightMargin="10"/></DiscretizeBin>
+			<DiscretizeBin binValue="high"><Interval closure="closedOpen" leftMargin="10"/></DiscretizeBin>
+		</Discretize>`, map[string]interface{}{"x": 10}, "high"},
+		{`<Discretize field="x" mapMissingTo="none"><DiscretizeBin binValue="low"><Interval closure="openOpen" rightMargin="10"/></DiscretizeBin></Discretize>`, nil, "none"},
+		{`<Apply function="+"><FieldRef field="x"/><Constant>2</Constant></Apply>`, map[string]interface{}{"x": 3}, "5"},
+		{`<Apply function="+" mapMissingTo="-1"><FieldRef field="x"/><Constant>2</Constant></Apply>`, nil, "-1"},
+		{`<Apply function="max"><Constant>1</Constant><Constant>5</Constant><Constant>3</Constant></Apply>`, nil, "5"},
+		{`<Apply function="avg"><Constant>1</Constant><Constant>5</Constant><Constant>3</Constant></Apply>`, nil, "3"},
+		{`<Apply function="median"><Constant>1</Constant><Constant>5</Constant><Constant>3</Constant><Constant>4</Constant></Apply>`, nil, "3.5"},
+		{`<Apply function="isMissing"><FieldRef field="x"/></Apply>`, nil, "true"},
+		{`<Apply function="if"><Apply function="greaterThan"><FieldRef field="x"/><Constant>1</Constant></Apply><Constant>a</Constant><Constant>b</Constant></Apply>`, map[string]interface{}{"x": 0}, "b"},
+		{`<Apply function="isIn"><FieldRef field="x"/><Constant>a</Constant><Constant>b</Constant></Apply>`, map[string]interface{}{"x": "b"}, "true"},
+		{`<Apply function="uppercase"><FieldRef field="x"/></Apply>`, map[string]interface{}{"x": "abc"}, "ABC"},
+		{`<Apply function="substring"><FieldRef field="x"/><Constant>2</Constant><Constant>3</Constant></Apply>`, map[string]interface{}{"x": "abcdef"}, "bcd"},
+		{`<Apply function="trimBlanks"><FieldRef field="x"/></Apply>`, map[string]interface{}{"x": "  a b "}, "a b"},
+		{`<Apply function="matches"><FieldRef field="x"/><Constant>\d{3}-\d{2,4}$</Constant></Apply>`, map[string]interface{}{"x": "call 555-1234"}, "true"},
+		{`<Apply function="matches"><FieldRef field="x"/><Constant>\d{3}-\d{2,4}$</Constant></Apply>`, map[string]interface{}{"x": "call 555-12345"}, "false"},
+		{`<Apply function="matches"><FieldRef field="x"/><Constant>(?i)^hello\s[a-z]+!?$</Constant></Apply>`, map[string]interface{}{"x": "HeLLo World!"}, "true"},
+		{`<Apply function="matches"><FieldRef field="x"/><Constant>^cat|dog$</Constant></Apply>`, map[string]interface{}{"x": "hotdog"}, "true"},
+		{`<Apply function="matches"><FieldRef field="x"/><Constant>^cat|dog$</Constant></Apply>`, map[string]interface{}{"x": "dogs"}, "false"},
+		{`<Apply function="matches"><FieldRef field="x"/><Constant>a.c</Constant></Apply>`, map[string]interface{}{"x": "a%c"}, "true"},
+		{`<Apply function="replace"><FieldRef field="x"/><Constant>(\w+)@(\w+)\.com</Constant><Constant>$2 at $1</Constant></Apply>`, map[string]interface{}{"x": "my_id@example.com"}, "example at my_id"},
+		{`<Apply function="replace"><FieldRef field="x"/><Constant>[^\d.]+</Constant><Constant>%</Constant></Apply>`, map[string]interface{}{"x": "12.5 kg"}, "12.5%"},
+		{`<Apply function="round"><Constant>-2.5</Constant></Apply>`, nil, "-3"},
+		{`<Apply function="stdNormalCDF"><Constant>0</Constant></Apply>`, nil, "0.5"},
+		{`<Apply function="ln" invalidValueTreatment="asMissing" defaultValue="0"><Constant>-1</Constant></Apply>`, nil, "0"},
+		{`<Apply function="ln" invalidValueTreatment="asMissing" defaultValue="0"><Constant>0</Constant></Apply>`, nil, "0"},
+		{`<Apply function="/" invalidValueTreatment="asMissing" defaultValue="0"><Constant>1</Constant><Constant>0</Constant></Apply>`, nil, "0"},
+		{`<Apply function="stdNormalIDF" invalidValueTreatment="asMissing" defaultValue="0"><Constant>1</Constant></Apply>`, nil, "0"},
+	}
+
+	for _, tc := range td {
+		var expr schema.Expression
+		assert.NoError(t, xml.Unmarshal([]byte(tc.expr), &expr), tc.expr)
+
+		body, global, code := scopeFor("<Constant/>", new(schema.Constant))
+		body.With(NewStatement().Return().Expression(&expr, global))
+
+		input := map[string]interface{}{}
+		for k, v := range tc.input {
+			input[k] = v
+		}
+
+		v, err := makeScript(code()).Run(context.Background(), input)
+		assert.NoError(t, err, tc.expr)
+		if err == nil {
+			assert.Equal(t, tc.expect, v.String(), tc.expr)
+		}
+	}
+}
+
+func TestExpression_Error(t *testing.T) {
+	tests := []*schema.Expression{
+		nil,
+		{},
+		{NormContinuous: &schema.NormContinuous{Field: "x"}},
+		{Constant: &schema.Constant{DataType: "integer", Value: "abc"}},
+	}
+
+	for _, tc := range tests {
+		_, err := NewStatement().Expression(tc, NewScope()).Compile()
+		assert.Error(t, err)
+	}
+}
+
+func TestApply_Invalid(t *testing.T) {
+	tests := []string{
+		`<Apply function="/"><Constant>1</Constant><Constant>0</Constant></Apply>`,
+		`<Apply function="ln" invalidValueTreatment="returnInvalid"><Constant>0</Constant></Apply>`,
+	}
+
+	for _, tc := range tests {
+		var expr schema.Expression
+		assert.NoError(t, xml.Unmarshal([]byte(tc), &expr), tc)
+
+		body, global, code := scopeFor("<Constant/>", new(schema.Constant))
+		body.With(NewStatement().Return().Expression(&expr, global))
+
+		_, err := makeScript(code()).Run(context.Background(), map[string]interface{}{})
+		assert.ErrorContains(t, err, "returned an invalid value", tc)
+	}
+}
+
+func TestApply_Unsupported(t *testing.T) {
+	body, global, code := scopeFor("<Constant/>", new(schema.Constant))
+	body.With(NewStatement().Return().Apply(schema.Apply{Function: "unknown"}, global))
+
+	_, err := makeScript(code()).Run(context.Background(), map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestApply_Regex(t *testing.T) {
+	tests := []struct {
+		function string
+		pattern  string
+	}{
+		{"matches", `(a|b)c`},
+		{"matches", `(ab)+`},
+		{"matches", `(?:ab)`},
+		{"matches", `a{2,1}`},
+		{"matches", `\bword`},
+		{"replace", `a|b`},
+	}
+
+	for _, tc := range tests {
+		args := []schema.Expression{
+			{FieldRef: &schema.FieldRef{Field: "x"}},
+			{Constant: &schema.Constant{Value: schema.Value(tc.pattern)}},
+		}
+		if tc.function == "replace" {
+			args = append(args, schema.Expression{Constant: &schema.Constant{Value: "c"}})
+		}
+
+		body, global, code := scopeFor("<Constant/>", new(schema.Constant))
+		body.With(NewStatement().Return().Apply(schema.Apply{Function: tc.function, Expressions: args}, global))
+
+		_, err := makeScript(code()).Run(context.Background(), map[string]interface{}{"x": "abc"})
+		assert.Error(t, err, tc.pattern)
+	}
+}
@@ -0,0 +1,132 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// GaussianProcessModel generates the LUA code for the element. Every kernel is written as its
+// amplitude, noise variance, exponent of the distances and length scale of each input, such that
+// k(x, z) = gamma * exp(-0.5 * sum(|x_i - z_i|^degree / scale_i)). The training instances are
+// embedded as a table of rows, each containing the target value and the values of the inputs.
+func (s *Scope) GaussianProcessModel(v schema.GaussianProcessModel, global *Scope) *Scope {
+	var inputs []string
+	for _, f := range v.MiningSchema.MiningFields {
+		if f.IsInput() {
+			inputs = append(inputs, f.Name)
+		}
+	}
+
+	target := v.MiningSchema.Target()
+	options := NewStatement().GaussianKernel(v, len(inputs)).Append(", inputs={")
+	for i, input := range inputs {
+		options.String(input)
+		if i+1 < len(inputs) {
+			options.Append(", ")
+		}
+	}
+	options.Append("}")
+
+	switch {
+	case v.FunctionName != "regression":
+		options.Error("function %s is not supported", v.FunctionName)
+	case target == "":
+		options.Error("model %s has no target", v.ModelName)
+	}
+
+	instances := NewScope()
+	if v.TrainingInstances.InlineTable == nil {
+		instances.With(NewStatement().Error("training instances of %s require an inline table", v.ModelName))
+	} else {
+		for i, row := range v.TrainingInstances.InlineTable.Rows {
+			instances.With(NewStatement().GaussianInstance(row, i, v, target, inputs))
+		}
+	}
+
+	global.Require("gaussian")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or gaussian.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			instances,
+			Append("})"),
+		).
+		Output(v.Output, target, "model."+v.ModelName+".eval(v)", global)
+}
+
+// GaussianKernel generates the LUA code for the kernel of the model, which is the opening of the
+// table with its parameters. The radial basis kernel has the same length scale for every input.
+func (s *Statement) GaussianKernel(v schema.GaussianProcessModel, size int) *Statement {
+	var gamma, noise, degree float64
+	var lambda []float64
+	var err error
+	switch {
+	case v.RadialBasisKernel != nil:
+		k := v.RadialBasisKernel
+		gamma, noise, degree = k.Gamma, k.NoiseVariance, 2
+		for i := 0; i < size; i++ {
+			lambda = append(lambda, k.Lambda)
+		}
+	case v.ARDSquaredExponentialKernel != nil:
+		k := v.ARDSquaredExponentialKernel
+		gamma, noise, degree = k.Gamma, k.NoiseVariance, 2
+		lambda, err = k.Lambda.Floats()
+	case v.AbsoluteExponentialKernel != nil:
+		k := v.AbsoluteExponentialKernel
+		gamma, noise, degree = k.Gamma, k.NoiseVariance, 1
+		lambda, err = k.Lambda.Floats()
+	case v.GeneralizedExponentialKernel != nil:
+		k := v.GeneralizedExponentialKernel
+		gamma, noise, degree = k.Gamma, k.NoiseVariance, k.Degree
+		lambda, err = k.Lambda.Floats()
+	default:
+		return s.Error("model %s has no kernel", v.ModelName)
+	}
+
+	switch {
+	case err != nil:
+		return s.Error("lambda of %s is invalid: %v", v.ModelName, err)
+	case len(lambda) != size:
+		return s.Error("lambda of %s has %d values instead of %d", v.ModelName, len(lambda), size)
+	}
+
+	// The squared kernels divide the squared distances by the squared length scales
+	scales := make([]float64, 0, len(lambda))
+	for _, l := range lambda {
+		if l <= 0 {
+			return s.Error("lambda of %s must be positive", v.ModelName)
+		}
+		if v.RadialBasisKernel != nil || v.ARDSquaredExponentialKernel != nil {
+			l *= l
+		}
+		scales = append(scales, l)
+	}
+
+	return s.Append("{gamma=%s, noise=%s, degree=%s, scales=", formatFloat(gamma), formatFloat(noise), formatFloat(degree)).
+		Floats(scales)
+}
+
+// GaussianInstance generates the LUA code for a row of the training instances, which is the
+// target value followed by the values of the inputs.
+func (s *Statement) GaussianInstance(row schema.Row, index int, v schema.GaussianProcessModel, target string, inputs []string) *Statement {
+	value, ok := row.Get(v.TrainingInstances.Column(target))
+	if !ok {
+		return s.Error("training instance %d has no %s", index+1, target)
+	}
+
+	s.Append("{").Literal(schema.Value(value), "double").Append(", {")
+	for i, input := range inputs {
+		value, ok := row.Get(v.TrainingInstances.Column(input))
+		if !ok {
+			return s.Error("training instance %d has no %s", index+1, input)
+		}
+
+		s.Literal(schema.Value(value), "double")
+		if i+1 < len(inputs) {
+			s.Append(", ")
+		}
+	}
+	return s.Append("}},")
+}
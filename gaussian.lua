@@ -0,0 +1,117 @@
+local gaussian = {}
+
+-- NewModel creates a gaussian process model with its kernel and its training instances. The
+-- weights of the instances are computed once, using the Cholesky decomposition of the covariance
+-- of the instances.
+function gaussian.NewModel(options, instances)
+    local m = options
+    m.instances = instances
+
+    -- The covariance of the instances, with the noise variance on its diagonal
+    local n, K, y = #instances, {}, {}
+    for i=1, n do
+        K[i], y[i] = {}, instances[i][1]
+        for j=1, n do
+            K[i][j] = gaussian.Kernel(m, instances[i][2], instances[j][2])
+        end
+        K[i][i] = K[i][i] + m.noise
+    end
+
+    m.L = gaussian.Cholesky(K)
+    m.alpha = gaussian.Backward(m.L, gaussian.Forward(m.L, y))
+
+    -- Function which predicts the value and returns the result table
+    m.eval = function(v)
+        return gaussian.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the mean of the predictive distribution, along with its standard deviation. If
+-- any input is missing, there is no prediction.
+function gaussian.Score(m, v)
+    local x = {}
+    for i=1, #m.inputs do
+        x[i] = v[m.inputs[i]]
+        if Unknown(x[i]) then
+            return nil
+        end
+    end
+
+    local k, value = {}, 0
+    for i=1, #m.instances do
+        k[i] = gaussian.Kernel(m, x, m.instances[i][2])
+        value = value + k[i] * m.alpha[i]
+    end
+
+    local w, variance = gaussian.Forward(m.L, k), gaussian.Kernel(m, x, x)
+    for i=1, #w do
+        variance = variance - w[i] * w[i]
+    end
+    return {value = value, standardError = math.sqrt(math.max(variance, 0))}
+end
+
+-- Kernel returns the covariance of two points.
+function gaussian.Kernel(m, x, z)
+    local sum = 0
+    for i=1, #m.scales do
+        sum = sum + math.abs(x[i] - z[i]) ^ m.degree / m.scales[i]
+    end
+    return m.gamma * math.exp(-0.5 * sum)
+end
+
+-- Cholesky returns the lower triangular matrix L such that L * L' is the matrix.
+function gaussian.Cholesky(a)
+    local n, L = #a, {}
+    for i=1, n do
+        L[i] = {}
+        for j=1, n do
+            L[i][j] = 0
+        end
+    end
+
+    for j=1, n do
+        local d = a[j][j]
+        for k=1, j-1 do
+            d = d - L[j][k] * L[j][k]
+        end
+        L[j][j] = math.sqrt(math.max(d, 0))
+
+        for i=j+1, n do
+            local s = a[i][j]
+            for k=1, j-1 do
+                s = s - L[i][k] * L[j][k]
+            end
+            L[i][j] = s / L[j][j]
+        end
+    end
+    return L
+end
+
+-- Forward solves L * x = b for a lower triangular matrix.
+function gaussian.Forward(L, b)
+    local x = {}
+    for i=1, #L do
+        local s = b[i]
+        for k=1, i-1 do
+            s = s - L[i][k] * x[k]
+        end
+        x[i] = s / L[i][i]
+    end
+    return x
+end
+
+-- Backward solves L' * x = b for a lower triangular matrix.
+function gaussian.Backward(L, b)
+    local x = {}
+    for i=#L, 1, -1 do
+        local s = b[i]
+        for k=i+1, #L do
+            s = s - L[k][i] * x[k]
+        end
+        x[i] = s / L[i][i]
+    end
+    return x
+end
+
+return gaussian
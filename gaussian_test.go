@@ -0,0 +1,172 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGaussianProcessModel(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/gaussian1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	instances := [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 2}}
+	targets := []float64{1, 3, 2, 0.5}
+	for _, x := range [][]float64{{0, 0}, {0.5, 0.5}, {2, -1}, {1, 2}, {10, 10}} {
+		mean, deviation := predictProcess(instances, targets, x, 0.1, func(a, b []float64) float64 {
+			d1, d2 := a[0]-b[0], a[1]-b[1]
+			return 2 * math.Exp(-0.5*(d1*d1/1+d2*d2/4))
+		})
+
+		r, err := runScript(s, map[string]interface{}{"x1": x[0], "x2": x[1]})
+		assert.NoError(t, err)
+		assert.InDelta(t, mean, r.Value, 1e-9, x)
+		assert.InDelta(t, mean, r.Output("Prediction"), 1e-9, x)
+		assert.InDelta(t, deviation, r.StandardError, 1e-9, x)
+		assert.InDelta(t, deviation, r.Output("Deviation"), 1e-9, x)
+	}
+
+	// Far from the instances, the prediction is the prior
+	r, err := runScript(s, map[string]interface{}{"x1": 100, "x2": 100})
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, r.Value, 1e-9)
+	assert.InDelta(t, math.Sqrt(2), r.StandardError, 1e-9)
+
+	// Without all of the inputs, there is no prediction
+	r, err = runScript(s, map[string]interface{}{"x1": 1})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestGaussianProcessModel_Kernels(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/gaussian1.xml")
+	assert.NoError(t, err)
+
+	kernel := `<ARDSquaredExponentialKernel gamma="2" noiseVariance="0.1">
+  <Lambda>
+    <Array n="2" type="real">1 2</Array>
+  </Lambda>
+</ARDSquaredExponentialKernel>`
+
+	instances := [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 2}}
+	targets := []float64{1, 3, 2, 0.5}
+	tests := []struct {
+		kernel string
+		noise  float64
+		eval   func(a, b []float64) float64
+	}{
+		{
+			kernel: `<RadialBasisKernel gamma="1.5" noiseVariance="0.2" lambda="0.5"/>`,
+			noise:  0.2,
+			eval: func(a, b []float64) float64 {
+				d1, d2 := a[0]-b[0], a[1]-b[1]
+				return 1.5 * math.Exp(-0.5*(d1*d1+d2*d2)/0.25)
+			},
+		},
+		{
+			kernel: `<AbsoluteExponentialKernel noiseVariance="0.5"><Lambda><Array n="2" type="real">2 0.5</Array></Lambda></AbsoluteExponentialKernel>`,
+			noise:  0.5,
+			eval: func(a, b []float64) float64 {
+				return math.Exp(-0.5 * (math.Abs(a[0]-b[0])/2 + math.Abs(a[1]-b[1])/0.5))
+			},
+		},
+		{
+			kernel: `<GeneralizedExponentialKernel gamma="3" degree="1.5"><Lambda><Array n="2" type="real">1 3</Array></Lambda></GeneralizedExponentialKernel>`,
+			noise:  1,
+			eval: func(a, b []float64) float64 {
+				return 3 * math.Exp(-0.5*(math.Pow(math.Abs(a[0]-b[0]), 1.5)/1+math.Pow(math.Abs(a[1]-b[1]), 1.5)/3))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		code, err := Convert([]byte(strings.Replace(string(b), kernel, tc.kernel, 1)))
+		assert.NoError(t, err)
+
+		s := makeScript(string(code))
+		for _, x := range [][]float64{{0.5, 0.5}, {2, -1}} {
+			mean, deviation := predictProcess(instances, targets, x, tc.noise, tc.eval)
+
+			r, err := runScript(s, map[string]interface{}{"x1": x[0], "x2": x[1]})
+			assert.NoError(t, err)
+			assert.InDelta(t, mean, r.Value, 1e-9, tc.kernel)
+			assert.InDelta(t, deviation, r.StandardError, 1e-9, tc.kernel)
+		}
+	}
+}
+
+func TestGaussianProcessModel_Errors(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/gaussian1.xml")
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(string(b), `functionName="regression"`, `functionName="classification"`, 1), err: "function classification is not supported"},
+		{doc: strings.Replace(string(b), `<Array n="2" type="real">1 2</Array>`, `<Array n="3" type="real">1 2 3</Array>`, 1), err: "lambda of process has 3 values instead of 2"},
+		{doc: strings.Replace(string(b), `<Array n="2" type="real">1 2</Array>`, `<Array n="2" type="real">1 0</Array>`, 1), err: "lambda of process must be positive"},
+		{doc: strings.Replace(string(b), `ARDSquaredExponentialKernel`, `UnknownKernel`, -1), err: "model process has no kernel"},
+		{doc: strings.Replace(string(b), `<c2>2</c2>`, ``, 1), err: "training instance 4 has no x2"},
+		{doc: strings.Replace(string(b), ` usageType="target"`, ``, 1), err: "lambda of process has 2 values instead of 3"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
+
+// predictProcess computes the mean and the standard deviation of the predictive distribution of
+// a gaussian process, by solving the linear systems with the gaussian elimination.
+func predictProcess(instances [][]float64, targets, x []float64, noise float64, kernel func(a, b []float64) float64) (float64, float64) {
+	n := len(instances)
+	solve := func(b []float64) []float64 {
+		a := make([][]float64, n)
+		for i := range a {
+			a[i] = make([]float64, n+1)
+			for j := range instances {
+				a[i][j] = kernel(instances[i], instances[j])
+			}
+			a[i][i] += noise
+			a[i][n] = b[i]
+		}
+
+		for i := 0; i < n; i++ {
+			for k := i + 1; k < n; k++ {
+				f := a[k][i] / a[i][i]
+				for j := i; j <= n; j++ {
+					a[k][j] -= f * a[i][j]
+				}
+			}
+		}
+
+		out := make([]float64, n)
+		for i := n - 1; i >= 0; i-- {
+			s := a[i][n]
+			for j := i + 1; j < n; j++ {
+				s -= a[i][j] * out[j]
+			}
+			out[i] = s / a[i][i]
+		}
+		return out
+	}
+
+	k := make([]float64, n)
+	for i := range instances {
+		k[i] = kernel(x, instances[i])
+	}
+
+	mean, variance := 0.0, kernel(x, x)
+	alpha, w := solve(targets), solve(k)
+	for i := range k {
+		mean += k[i] * alpha[i]
+		variance -= k[i] * w[i]
+	}
+	return mean, math.Sqrt(math.Max(variance, 0))
+}
@@ -0,0 +1,163 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The model types of the general regression models which are supported.
+var generalModelTypes = map[string]bool{
+	"regression":          true,
+	"generalLinear":       true,
+	"generalizedLinear":   true,
+	"multinomialLogistic": true,
+	"ordinalMultinomial":  true,
+}
+
+// GeneralRegressionModel generates the LUA code for the element.
+func (s *Scope) GeneralRegressionModel(v schema.GeneralRegressionModel, global *Scope) *Scope {
+	target := v.MiningSchema.Target()
+	if target == "" {
+		target = v.TargetVariableName
+	}
+
+	options := NewStatement().Append("{modelType=").String(v.ModelType).
+		Append(", functionName=").String(v.FunctionName)
+	if !generalModelTypes[v.ModelType] {
+		options.Error("model type %s is not supported", v.ModelType)
+	}
+	switch {
+	case v.ModelType == "ordinalMultinomial" && v.CumulativeLink != "":
+		options.Append(", link=").String(v.CumulativeLink)
+	case v.ModelType == "generalizedLinear" && v.LinkFunction != "":
+		options.Append(", link=").String(v.LinkFunction)
+	}
+	if v.LinkParameter != 0 {
+		options.Append(", linkParameter=%s", formatFloat(v.LinkParameter))
+	}
+	if v.DistParameter != 0 {
+		options.Append(", distParameter=%s", formatFloat(v.DistParameter))
+	}
+
+	// The offset and the number of trials are either constants or input fields
+	if v.OffsetVariable != "" {
+		options.Append(", offsetField=").String(v.OffsetVariable)
+	} else if v.OffsetValue != 0 {
+		options.Append(", offset=%s", formatFloat(v.OffsetValue))
+	}
+	if v.TrialsVariable != "" {
+		options.Append(", trialsField=").String(v.TrialsVariable)
+	} else if v.TrialsValue != 0 {
+		options.Append(", trials=%s", formatFloat(v.TrialsValue))
+	}
+
+	// The target categories of classification models, in their order
+	if v.FunctionName == "classification" {
+		categories := targetCategories(v, target, global)
+		options.Append(", categories={")
+		for i, c := range categories {
+			options.String(c)
+			if i+1 < len(categories) {
+				options.Append(", ")
+			}
+		}
+		options.Append("}")
+	}
+
+	global.Require("regression")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or regression.NewGeneral(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			NewScope().PPMatrix(v, global),
+			Append("}, {"),
+			NewScope().ParamMatrix(v.ParamMatrix),
+			Append("})"),
+		).
+		Output(v.Output, target, "model."+v.ModelName+".eval(v)", global)
+}
+
+// PPMatrix generates the LUA code for the element. The cells are grouped by parameter, and
+// each cell is either a factor with the value it matches or a covariate with its exponent.
+func (s *Scope) PPMatrix(v schema.GeneralRegressionModel, global *Scope) *Scope {
+	factors := make(map[string]bool, len(v.FactorList))
+	for _, f := range v.FactorList {
+		factors[f.Name] = true
+	}
+
+	order := make([]string, 0, len(v.PPMatrix))
+	cells := make(map[string][]schema.PPCell, len(v.PPMatrix))
+	for _, c := range v.PPMatrix {
+		if _, ok := cells[c.ParameterName]; !ok {
+			order = append(order, c.ParameterName)
+		}
+		cells[c.ParameterName] = append(cells[c.ParameterName], c)
+	}
+
+	for _, parameter := range order {
+		line := NewStatement().Append("[").String(parameter).Append("]={")
+		for i, c := range cells[parameter] {
+			line.Append("{field=").String(c.PredictorName)
+			if factors[c.PredictorName] {
+				field, _ := global.DataField(c.PredictorName)
+				line.Append(", value=").Literal(c.Value, field.DataType)
+			} else if c.Value != "" {
+				line.Append(", exponent=").Literal(c.Value, "double")
+			}
+			if c.TargetCategory != "" {
+				line.Append(", category=").String(c.TargetCategory)
+			}
+
+			line.Append("}")
+			if i+1 < len(cells[parameter]) {
+				line.Append(", ")
+			}
+		}
+		s.With(line.Append("},"))
+	}
+	return s
+}
+
+// ParamMatrix generates the LUA code for the element.
+func (s *Scope) ParamMatrix(v []schema.PCell) *Scope {
+	for _, c := range v {
+		line := NewStatement().Append("{parameter=").String(c.ParameterName).
+			Append(", beta=%s", formatFloat(c.Beta))
+		if c.TargetCategory != "" {
+			line.Append(", category=").String(c.TargetCategory)
+		}
+		s.With(line.Append("},"))
+	}
+	return s
+}
+
+// targetCategories returns the categories of the target field, either from the data dictionary
+// or, if the target field does not list its values, in their order of appearance in the model.
+func targetCategories(v schema.GeneralRegressionModel, target string, global *Scope) []string {
+	var categories []string
+	seen := make(map[string]bool)
+	add := func(c string) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			categories = append(categories, c)
+		}
+	}
+
+	if field, ok := global.DataField(target); ok {
+		for _, value := range field.Values {
+			if value.Property == "" || value.Property == "valid" {
+				add(string(value.Value))
+			}
+		}
+	}
+
+	if len(categories) == 0 {
+		for _, c := range v.ParamMatrix {
+			add(c.TargetCategory)
+		}
+		add(v.TargetReferenceCategory)
+	}
+	return categories
+}
@@ -0,0 +1,163 @@
+package pmml2lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneralRegressionModel(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/general1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	r, err := runScript(s, map[string]interface{}{"age": 40, "car": "large"})
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Exp(-0.14), r.Value, 1e-9)
+
+	r, err = runScript(s, map[string]interface{}{"age": 40, "car": "small"})
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Exp(-0.54), r.Value, 1e-9)
+
+	r, err = runScript(s, map[string]interface{}{"car": "small"})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestGeneralRegressionModel_Links(t *testing.T) {
+	const eta = -0.14
+	td := []struct {
+		link   string
+		extra  string
+		expect float64
+	}{
+		{link: "identity", expect: eta},
+		{link: "log", expect: math.Exp(eta)},
+		{link: "logit", expect: 1 / (1 + math.Exp(-eta))},
+		{link: "probit", expect: 0.5 * math.Erfc(-eta/math.Sqrt2)},
+		{link: "cloglog", expect: 1 - math.Exp(-math.Exp(eta))},
+		{link: "loglog", expect: math.Exp(-math.Exp(-eta))},
+		{link: "cauchit", expect: 0.5 + math.Atan(eta)/math.Pi},
+		{link: "power", extra: `linkParameter="-1"`, expect: 1 / eta},
+		{link: "power", expect: math.Exp(eta)},
+		{link: "oddspower", extra: `linkParameter="0.5"`, expect: 1 / (1 + math.Pow(1+0.5*eta, -2))},
+		{link: "oddspower", expect: 1 / (1 + math.Exp(-eta))},
+		{link: "negbin", extra: `distParameter="2"`, expect: 1 / (2 * (math.Exp(-eta) - 1))},
+		{link: "identity", extra: `trialsValue="3"`, expect: 3 * eta},
+	}
+
+	b, err := ioutil.ReadFile("fixtures/general1.xml")
+	assert.NoError(t, err)
+
+	for _, tc := range td {
+		t.Run(tc.link+tc.extra, func(t *testing.T) {
+			doc := strings.Replace(string(b), `linkFunction="log"`, fmt.Sprintf(`linkFunction="%s" %s`, tc.link, tc.extra), 1)
+			code, err := Convert([]byte(doc))
+			assert.NoError(t, err)
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"age": 40, "car": "large"})
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expect, r.Value, 1e-9)
+		})
+	}
+}
+
+func TestGeneralRegressionModel_Multinomial(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<DataDictionary>
+			<DataField name="x" optype="continuous" dataType="double"/>
+			<DataField name="color" optype="categorical" dataType="string"/>
+			<DataField name="y" optype="categorical" dataType="string">
+				<Value value="a"/><Value value="b"/><Value value="c"/>
+			</DataField>
+		</DataDictionary>
+		<GeneralRegressionModel functionName="classification" modelType="multinomialLogistic" targetReferenceCategory="c">
+			<MiningSchema><MiningField name="x"/><MiningField name="color"/><MiningField name="y" usageType="target"/></MiningSchema>
+			<ParameterList><Parameter name="p0"/><Parameter name="p1"/><Parameter name="p2"/></ParameterList>
+			<FactorList><Predictor name="color"/></FactorList>
+			<CovariateList><Predictor name="x"/></CovariateList>
+			<PPMatrix>
+				<PPCell value="1" predictorName="x" parameterName="p1"/>
+				<PPCell value="red" predictorName="color" parameterName="p2"/>
+			</PPMatrix>
+			<ParamMatrix>
+				<PCell targetCategory="a" parameterName="p0" beta="0.5"/>
+				<PCell targetCategory="a" parameterName="p1" beta="1"/>
+				<PCell targetCategory="a" parameterName="p2" beta="-0.5"/>
+				<PCell targetCategory="b" parameterName="p0" beta="-0.5"/>
+				<PCell targetCategory="b" parameterName="p1" beta="2"/>
+				<PCell targetCategory="b" parameterName="p2" beta="1"/>
+			</ParamMatrix>
+		</GeneralRegressionModel>
+	</PMML>`))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 1, "color": "red"})
+	assert.NoError(t, err)
+
+	sum := math.Exp(1) + math.Exp(2.5) + 1
+	assert.Equal(t, "b", r.Value)
+	assert.InDelta(t, math.Exp(1)/sum, r.Probability("a"), 1e-9)
+	assert.InDelta(t, math.Exp(2.5)/sum, r.Probability("b"), 1e-9)
+	assert.InDelta(t, 1/sum, r.Probability("c"), 1e-9)
+}
+
+func TestGeneralRegressionModel_Ordinal(t *testing.T) {
+	logit := func(y float64) float64 { return 1 / (1 + math.Exp(-y)) }
+	probit := func(y float64) float64 { return 0.5 * math.Erfc(-y/math.Sqrt2) }
+	td := map[string]func(float64) float64{
+		"logit":  logit,
+		"probit": probit,
+	}
+
+	for link, inverse := range td {
+		t.Run(link, func(t *testing.T) {
+			code, err := Convert([]byte(fmt.Sprintf(`<PMML version="4.4">
+				<DataDictionary>
+					<DataField name="x" optype="continuous" dataType="double"/>
+					<DataField name="y" optype="ordinal" dataType="string">
+						<Value value="low"/><Value value="mid"/><Value value="high"/>
+					</DataField>
+				</DataDictionary>
+				<GeneralRegressionModel functionName="classification" modelType="ordinalMultinomial" cumulativeLink="%s" targetVariableName="y">
+					<MiningSchema><MiningField name="x"/></MiningSchema>
+					<ParameterList><Parameter name="t1"/><Parameter name="t2"/><Parameter name="p1"/></ParameterList>
+					<CovariateList><Predictor name="x"/></CovariateList>
+					<PPMatrix><PPCell value="1" predictorName="x" parameterName="p1"/></PPMatrix>
+					<ParamMatrix>
+						<PCell targetCategory="low" parameterName="t1" beta="-1"/>
+						<PCell targetCategory="mid" parameterName="t2" beta="1"/>
+						<PCell parameterName="p1" beta="0.5"/>
+					</ParamMatrix>
+				</GeneralRegressionModel>
+			</PMML>`, link)))
+			assert.NoError(t, err)
+			assert.Contains(t, string(code), "categories={'low', 'mid', 'high'}")
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 2})
+			assert.NoError(t, err)
+			assert.Equal(t, "low", r.Value)
+			assert.InDelta(t, inverse(0), r.Probability("low"), 1e-9)
+			assert.InDelta(t, inverse(2)-inverse(0), r.Probability("mid"), 1e-9)
+			assert.InDelta(t, 1-inverse(2), r.Probability("high"), 1e-9)
+		})
+	}
+}
+
+func TestGeneralRegressionModel_ModelType(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/general1.xml")
+	assert.NoError(t, err)
+
+	for _, modelType := range []string{"CoxRegression", "unknown", ""} {
+		doc := strings.Replace(string(b), `modelType="generalizedLinear"`, `modelType="`+modelType+`"`, 1)
+		_, err := Convert([]byte(doc))
+		assert.ErrorContains(t, err, "model type "+modelType+" is not supported", modelType)
+	}
+}
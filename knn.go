@@ -0,0 +1,121 @@
+package pmml2lua
+
+import (
+	"strconv"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The scoring methods of the nearest neighbor models, by their function name.
+var scoringMethods = map[string]map[string]bool{
+	"classification": {"majorityVote": true, "weightedMajorityVote": true},
+	"regression":     {"average": true, "median": true, "weightedAverage": true},
+}
+
+// NearestNeighborModel generates the LUA code for the element. The training instances are
+// embedded as a table of rows, each containing the id of the instance, its target value and the
+// values of its inputs.
+func (s *Scope) NearestNeighborModel(v schema.NearestNeighborModel, global *Scope) *Scope {
+	options := NewStatement().ComparisonMeasure(v.ComparisonMeasure).
+		Append(", k=%d, threshold=%s", v.NumberOfNeighbors, formatFloat(v.Threshold))
+	if v.NumberOfNeighbors <= 0 {
+		options.Error("model %s requires a positive number of neighbors", v.ModelName)
+	}
+
+	// The scoring method depends on whether the target is categorical or continuous
+	target := v.MiningSchema.Target()
+	if target != "" {
+		method := v.ContinuousScoringMethod
+		if v.FunctionName == "classification" {
+			method = v.CategoricalScoringMethod
+		}
+
+		options.Append(", method=").String(method)
+		if methods, ok := scoringMethods[v.FunctionName]; !ok || !methods[method] {
+			options.Error("scoring method %s is not supported for %s", method, v.FunctionName)
+		}
+	}
+
+	inputs := NewScope()
+	for _, input := range v.Inputs {
+		inputs.With(NewStatement().KNNInput(input, v.ComparisonMeasure))
+	}
+
+	instances := NewScope()
+	if v.TrainingInstances.InlineTable == nil {
+		instances.With(NewStatement().Error("training instances of %s require an inline table", v.ModelName))
+	} else {
+		for i, row := range v.TrainingInstances.InlineTable.Rows {
+			instances.With(NewStatement().TrainingInstance(row, i, v, global))
+		}
+	}
+
+	global.Require("knn")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or knn.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			inputs,
+			Append("}, {"),
+			instances,
+			Append("})"),
+		).
+		Output(v.Output, target, "model."+v.ModelName+".eval(v)", global)
+}
+
+// KNNInput generates the LUA code for the element. The compare function of the comparison
+// measure is used if the input does not specify its own.
+func (s *Statement) KNNInput(v schema.KNNInput, measure schema.ComparisonMeasure) *Statement {
+	compare := v.CompareFunction
+	if compare == "" {
+		compare = measure.CompareFunction
+	}
+
+	s.Append("{field=").String(v.Field).Append(", weight=%s, compare=", formatFloat(v.FieldWeight)).String(compare)
+	if !compareFunctions[compare] || compare == "gaussSim" {
+		s.Error("compare function %s is not supported", compare)
+	}
+	return s.Append("},")
+}
+
+// TrainingInstance generates the LUA code for a row of the training instances. The instances
+// without an id variable are identified by their 1-based index.
+func (s *Statement) TrainingInstance(row schema.Row, index int, v schema.NearestNeighborModel, global *Scope) *Statement {
+	id := strconv.Itoa(index + 1)
+	if v.InstanceIDVariable != "" {
+		value, ok := row.Get(v.TrainingInstances.Column(v.InstanceIDVariable))
+		if !ok {
+			return s.Error("training instance %s has no %s", id, v.InstanceIDVariable)
+		}
+		id = value
+	}
+
+	s.Append("{").String(id).Append(", ")
+	if target := v.MiningSchema.Target(); target != "" {
+		value, ok := row.Get(v.TrainingInstances.Column(target))
+		if !ok {
+			return s.Error("training instance %s has no %s", id, target)
+		}
+		s.Literal(schema.Value(value), scoreType(v.FunctionName, target, global))
+	} else {
+		s.Append("nil")
+	}
+
+	s.Append(", {")
+	for i, input := range v.Inputs {
+		value, ok := row.Get(v.TrainingInstances.Column(input.Field))
+		if !ok {
+			return s.Error("training instance %s has no %s", id, input.Field)
+		}
+
+		field, _ := global.DataField(input.Field)
+		s.Literal(schema.Value(value), field.DataType)
+		if i+1 < len(v.Inputs) {
+			s.Append(", ")
+		}
+	}
+	return s.Append("}},")
+}
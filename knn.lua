@@ -0,0 +1,145 @@
+local clustering = require("clustering")
+local knn = {}
+
+-- NewModel creates a nearest neighbor model with its options, its inputs and its training
+-- instances. Each instance contains its id, its target value and the values of its inputs.
+function knn.NewModel(options, inputs, instances)
+    local m = options
+    m.inputs = inputs
+    m.instances = instances
+
+    -- Function which searches the nearest neighbors and returns the result table
+    m.eval = function(v)
+        return knn.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the prediction out of the target values of the nearest neighbors of the
+-- record, along with their ids. If all of the inputs are missing, there is no prediction.
+function knn.Score(m, v)
+    local x, present = {}, false
+    for i=1, #m.inputs do
+        local value = v[m.inputs[i].field]
+        if not Unknown(value) then
+            x[i] = value
+            present = true
+        end
+    end
+    if not present then
+        return nil
+    end
+
+    local neighbors = knn.Search(m, x)
+    local r = {neighbors = {}}
+    for i=1, #neighbors do
+        r.neighbors[i] = neighbors[i].instance[1]
+    end
+    r.entityId = r.neighbors[1]
+
+    if m.method ~= nil then
+        r.value = knn.methods[m.method](m, neighbors)
+    end
+    return r
+end
+
+-- Search returns the k nearest neighbors of the record, nearest first. The neighbors which are
+-- as near as each other are kept in the order of the training instances.
+function knn.Search(m, x)
+    local candidates = {}
+    for i=1, #m.instances do
+        local instance = m.instances[i]
+        candidates[i] = {
+            instance = instance,
+            distance = clustering.Measure(m, m.inputs, x, instance[3]),
+            index = i,
+        }
+    end
+
+    table.sort(candidates, function(a, b)
+        if a.distance ~= b.distance then
+            if m.kind == 'similarity' then
+                return a.distance > b.distance
+            end
+            return a.distance < b.distance
+        end
+        return a.index < b.index
+    end)
+
+    local neighbors = {}
+    for i=1, math.min(m.k, #candidates) do
+        neighbors[i] = candidates[i]
+    end
+    return neighbors
+end
+
+-- Vote returns the target value with the highest total weight, the ties being won by the
+-- target value of the nearest neighbor.
+function knn.Vote(neighbors, weight)
+    local votes, best = {}, nil
+    for i=1, #neighbors do
+        local value = neighbors[i].instance[2]
+        votes[value] = (votes[value] or 0) + weight(neighbors[i])
+    end
+
+    for i=1, #neighbors do
+        local value = neighbors[i].instance[2]
+        if best == nil or votes[value] > votes[best] then
+            best = value
+        end
+    end
+    return best
+end
+
+-- Weight returns the weight of the neighbor in the weighted scoring methods, which is the inverse
+-- of its distance, or its similarity itself if the model compares the records by similarity.
+function knn.Weight(m, n)
+    if m.kind == 'similarity' then
+        return n.distance
+    end
+    return 1 / (n.distance + m.threshold)
+end
+
+-- The scoring methods, by their name
+knn.methods = {
+    majorityVote = function(m, neighbors)
+        return knn.Vote(neighbors, function(n) return 1 end)
+    end,
+    weightedMajorityVote = function(m, neighbors)
+        return knn.Vote(neighbors, function(n) return knn.Weight(m, n) end)
+    end,
+    average = function(m, neighbors)
+        local sum = 0
+        for i=1, #neighbors do
+            sum = sum + neighbors[i].instance[2]
+        end
+        return sum / #neighbors
+    end,
+    weightedAverage = function(m, neighbors)
+        local sum, total = 0, 0
+        for i=1, #neighbors do
+            local w = knn.Weight(m, neighbors[i])
+            sum = sum + w * neighbors[i].instance[2]
+            total = total + w
+        end
+        if total == 0 then
+            return nil
+        end
+        return sum / total
+    end,
+    median = function(m, neighbors)
+        local y = {}
+        for i=1, #neighbors do
+            y[i] = neighbors[i].instance[2]
+        end
+        table.sort(y)
+
+        local n = #y
+        if n % 2 == 1 then
+            return y[(n + 1) / 2]
+        end
+        return (y[n / 2] + y[n / 2 + 1]) / 2
+    end,
+}
+
+return knn
@@ -0,0 +1,188 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNeighborModel_Classification(t *testing.T) {
+	rows := []struct {
+		id, species   string
+		length, width float64
+	}{
+		{"s1", "setosa", 1.4, 0.2}, {"s2", "setosa", 1.3, 0.3},
+		{"c1", "versicolor", 4.5, 1.5}, {"c2", "versicolor", 4.1, 1.3}, {"c3", "versicolor", 4.7, 1.4},
+		{"v1", "virginica", 6, 2.5}, {"v2", "virginica", 5.1, 1.9},
+	}
+
+	b, err := ioutil.ReadFile("fixtures/knn1.xml")
+	assert.NoError(t, err)
+
+	for _, method := range []string{"majorityVote", "weightedMajorityVote"} {
+		t.Run(method, func(t *testing.T) {
+			code, err := Convert([]byte(strings.Replace(string(b), `"majorityVote"`, `"`+method+`"`, 1)))
+			assert.NoError(t, err)
+
+			s := makeScript(string(code))
+			for _, x := range [][]float64{{1.5, 0.25}, {4.4, 1.4}, {5, 1.8}, {3, 1}, {5.5, 2.2}} {
+				type neighbor struct {
+					id, species string
+					distance    float64
+				}
+
+				neighbors := make([]neighbor, 0, len(rows))
+				for _, row := range rows {
+					dl, dw := x[0]-row.length, x[1]-row.width
+					neighbors = append(neighbors, neighbor{row.id, row.species, math.Sqrt(dl*dl + 2*dw*dw)})
+				}
+				sort.SliceStable(neighbors, func(i, j int) bool { return neighbors[i].distance < neighbors[j].distance })
+				neighbors = neighbors[:3]
+
+				votes, best := map[string]float64{}, ""
+				for _, n := range neighbors {
+					if method == "majorityVote" {
+						votes[n.species]++
+					} else {
+						votes[n.species] += 1 / (n.distance + 0.001)
+					}
+				}
+				for _, n := range neighbors {
+					if best == "" || votes[n.species] > votes[best] {
+						best = n.species
+					}
+				}
+
+				r, err := runScript(s, map[string]interface{}{"length": x[0], "width": x[1]})
+				assert.NoError(t, err)
+				assert.Equal(t, best, r.Value, x)
+				assert.Equal(t, []string{neighbors[0].id, neighbors[1].id, neighbors[2].id}, r.Neighbors, x)
+				assert.Equal(t, neighbors[0].id, r.EntityID, x)
+				assert.Equal(t, neighbors[0].id, r.Output("Neighbor1"), x)
+				assert.Equal(t, neighbors[2].id, r.Output("Neighbor3"), x)
+			}
+
+			r, err := runScript(s, map[string]interface{}{})
+			assert.NoError(t, err)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestNearestNeighborModel_Regression(t *testing.T) {
+	doc := `<PMML version="4.4">
+		<DataDictionary>
+			<DataField name="x" optype="continuous" dataType="double"/>
+			<DataField name="y" optype="continuous" dataType="double"/>
+			<DataField name="price" optype="continuous" dataType="double"/>
+		</DataDictionary>
+		<NearestNeighborModel modelName="price" functionName="regression" numberOfNeighbors="4" continuousScoringMethod="average">
+			<MiningSchema><MiningField name="x"/><MiningField name="y"/><MiningField name="price" usageType="predicted"/></MiningSchema>
+			<TrainingInstances>
+				<InstanceFields><InstanceField field="x"/><InstanceField field="y"/><InstanceField field="price"/></InstanceFields>
+				<InlineTable>
+					<row><x>0</x><y>0</y><price>10</price></row>
+					<row><x>1</x><y>0</y><price>20</price></row>
+					<row><x>0</x><y>2</y><price>40</price></row>
+					<row><x>3</x><y>3</y><price>80</price></row>
+					<row><x>9</x><y>9</y><price>1000</price></row>
+				</InlineTable>
+			</TrainingInstances>
+			<ComparisonMeasure kind="distance"><cityBlock/></ComparisonMeasure>
+			<KNNInputs><KNNInput field="x"/><KNNInput field="y"/></KNNInputs>
+		</NearestNeighborModel>
+	</PMML>`
+
+	// The distances of the record {x=1, y=1} to the first instances are 2, 1, 2 and 4
+	w := func(d float64) float64 { return 1 / (d + 0.001) }
+	td := []struct {
+		method string
+		expect float64
+	}{
+		{method: "average", expect: (10 + 20 + 40 + 80) / 4.0},
+		{method: "median", expect: (20 + 40) / 2.0},
+		{method: "weightedAverage", expect: (w(2)*10 + w(1)*20 + w(2)*40 + w(4)*80) / (w(2) + w(1) + w(2) + w(4))},
+	}
+
+	for _, tc := range td {
+		code, err := Convert([]byte(strings.Replace(doc, `"average"`, `"`+tc.method+`"`, 1)))
+		assert.NoError(t, err)
+
+		s := makeScript(string(code))
+		r, err := runScript(s, map[string]interface{}{"x": 1, "y": 1})
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.expect, r.Value, 1e-9, tc.method)
+		assert.Equal(t, []string{"2", "1", "3", "4"}, r.Neighbors, tc.method)
+	}
+
+	// The missing values are ignored and the distances adjusted
+	code, err := Convert([]byte(strings.Replace(doc, `numberOfNeighbors="4"`, `numberOfNeighbors="1"`, 1)))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"y": 2.5})
+	assert.NoError(t, err)
+	assert.Equal(t, 40.0, r.Value)
+	assert.Equal(t, []string{"3"}, r.Neighbors)
+}
+
+func TestNearestNeighborModel_Similarity(t *testing.T) {
+	doc := `<PMML version="4.4">
+		<DataDictionary>
+			<DataField name="x" optype="continuous" dataType="double"/>
+			<DataField name="y" optype="continuous" dataType="double"/>
+			<DataField name="z" optype="continuous" dataType="double"/>
+			<DataField name="price" optype="continuous" dataType="double"/>
+		</DataDictionary>
+		<NearestNeighborModel modelName="price" functionName="regression" numberOfNeighbors="3" continuousScoringMethod="weightedAverage">
+			<MiningSchema><MiningField name="x"/><MiningField name="y"/><MiningField name="z"/><MiningField name="price" usageType="predicted"/></MiningSchema>
+			<TrainingInstances>
+				<InstanceFields><InstanceField field="x"/><InstanceField field="y"/><InstanceField field="z"/><InstanceField field="price"/></InstanceFields>
+				<InlineTable>
+					<row><x>1</x><y>1</y><z>0</z><price>10</price></row>
+					<row><x>1</x><y>0</y><z>0</z><price>20</price></row>
+					<row><x>0</x><y>1</y><z>1</z><price>40</price></row>
+					<row><x>0</x><y>0</y><z>1</z><price>80</price></row>
+				</InlineTable>
+			</TrainingInstances>
+			<ComparisonMeasure kind="similarity"><jaccard/></ComparisonMeasure>
+			<KNNInputs><KNNInput field="x"/><KNNInput field="y"/><KNNInput field="z"/></KNNInputs>
+		</NearestNeighborModel>
+	</PMML>`
+
+	// The similarities of the record to the nearest instances are 2/3, 2/3 and 1/3, which are the
+	// weights of their target values
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 1, "y": 1, "z": 1})
+	assert.NoError(t, err)
+	assert.InDelta(t, (2/3.0*10+2/3.0*40+1/3.0*20)/(5/3.0), r.Value, 1e-9)
+	assert.Equal(t, []string{"1", "3", "2"}, r.Neighbors)
+}
+
+func TestNearestNeighborModel_Error(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/knn1.xml")
+	assert.NoError(t, err)
+
+	doc := string(b)
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(doc, `numberOfNeighbors="3"`, `numberOfNeighbors="0"`, 1), err: "model iris requires a positive number of neighbors"},
+		{doc: strings.Replace(strings.Replace(doc, `<InlineTable>`, `<!--`, 1), `</InlineTable>`, `-->`, 1), err: "training instances of iris require an inline table"},
+		{doc: strings.Replace(doc, `"majorityVote"`, `"average"`, 1), err: "scoring method average is not supported for classification"},
+		{doc: strings.Replace(doc, `functionName="classification"`, `functionName="mixed" continuousScoringMethod="average"`, 1), err: "scoring method average is not supported for mixed"},
+		{doc: strings.Replace(doc, `<KNNInput field="length"/>`, `<KNNInput field="length" compareFunction="gaussSim"/>`, 1), err: "compare function gaussSim is not supported"},
+		{doc: strings.Replace(doc, `<KNNInput field="length"/>`, `<KNNInput field="length"/><KNNInput field="height"/>`, 1), err: "training instance s1 has no height"},
+		{doc: strings.Replace(doc, `instanceIdVariable="id"`, `instanceIdVariable="name"`, 1), err: "training instance 1 has no name"},
+		{doc: strings.Replace(doc, `<data:species>setosa</data:species>`, ``, 1), err: "training instance s1 has no species"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
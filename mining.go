@@ -0,0 +1,121 @@
+package pmml2lua
+
+import (
+	"math"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// MiningSchema generates the LUA code for the element. The input record is replaced with a
+// new one which only contains the mining fields, after their missing, invalid and outlier
+// values are treated.
+func (s *Scope) MiningSchema(v schema.MiningSchema, global *Scope) *Scope {
+	fields := NewScope()
+	for _, f := range v.MiningFields {
+		fields.WithIf(f.IsInput(),
+			NewStatement().MiningField(f, global).Append(","),
+		)
+	}
+
+	if len(fields.dst) == 0 {
+		return s
+	}
+
+	return s.With(
+		Append("v = tree.Prepare(v, {"),
+		fields,
+		Append("})"),
+	)
+}
+
+// MiningField generates the LUA code for the element.
+func (s *Statement) MiningField(v schema.MiningField, global *Scope) *Statement {
+	field, _ := global.DataField(v.Name)
+	optype := field.Optype
+	if v.Optype != "" {
+		optype = v.Optype
+	}
+
+	s.Append("{name=").String(v.Name).
+		Append(", dataType=").String(field.DataType).
+		Append(", optype=").String(optype)
+
+	// Missing and invalid values treatment
+	if v.MissingValueReplacement != "" {
+		s.Append(", replacement=").Literal(v.MissingValueReplacement, field.DataType)
+	}
+	if v.MissingValueTreatment != "" {
+		s.Append(", treatment=").String(v.MissingValueTreatment)
+	}
+	if v.InvalidValueTreatment != "" {
+		s.Append(", invalid=").String(v.InvalidValueTreatment)
+	}
+	if v.InvalidValueReplacement != "" {
+		s.Append(", invalidValue=").Literal(v.InvalidValueReplacement, field.DataType)
+	}
+
+	// Outliers treatment
+	if v.Outliers != "" {
+		s.Append(", outliers=").String(v.Outliers)
+	}
+	if v.LowValue != nil {
+		s.Append(", low=%v", formatFloat(*v.LowValue))
+	}
+	if v.HighValue != nil {
+		s.Append(", high=%v", formatFloat(*v.HighValue))
+	}
+
+	// Valid, invalid and missing values of the field. The valid values of a categorical or
+	// ordinal field are exhaustive, so any other value is considered to be invalid.
+	closed := false
+	if len(field.Values) > 0 {
+		s.Append(", values={")
+		for i, value := range field.Values {
+			property := value.Property
+			if property == "" {
+				property = "valid"
+			}
+
+			// Missing and invalid markers do not need to be of the field's data type
+			s.Append("[")
+			switch property {
+			case "valid":
+				closed = closed || optype != "continuous"
+				s.Literal(value.Value, field.DataType)
+			default:
+				s.Value(value.Value)
+			}
+			s.Append("]=").String(property)
+			if i+1 < len(field.Values) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	if closed {
+		s.Append(", closed=true")
+	}
+
+	if len(field.Intervals) > 0 {
+		s.Append(", intervals={")
+		for i, interval := range field.Intervals {
+			s.Append("{").String(interval.Closure).
+				Append(", %s, %s}", margin(interval.LeftMargin, -1), margin(interval.RightMargin, 1))
+			if i+1 < len(field.Intervals) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	return s.Append("}")
+}
+
+// margin returns the LUA code of an interval margin, which is infinite when not specified.
+func margin(v *float64, sign int) string {
+	if v == nil {
+		return formatFloat(math.Inf(sign))
+	}
+	return formatFloat(*v)
+}
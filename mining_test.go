@@ -0,0 +1,86 @@
+package pmml2lua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kelindar/pmml2lua/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiningSchema(t *testing.T) {
+	input := `<PMML version="4.4">
+		<DataDictionary>
+			<DataField name="age" optype="continuous" dataType="integer">
+				<Interval closure="closedClosed" leftMargin="0" rightMargin="120"/>
+			</DataField>
+			<DataField name="color" optype="categorical" dataType="string">
+				<Value value="red"/>
+				<Value value="green"/>
+				<Value value="blue"/>
+				<Value value="n/a" property="missing"/>
+			</DataField>
+			<DataField name="income" optype="continuous" dataType="double"/>
+			<DataField name="score" optype="continuous" dataType="double"/>
+			<DataField name="class" optype="categorical" dataType="string"/>
+		</DataDictionary>
+		<TreeModel functionName="classification">
+			<MiningSchema>
+				<MiningField name="age" outliers="asExtremeValues" lowValue="18" highValue="99" missingValueReplacement="40" invalidValueTreatment="asMissing"/>
+				<MiningField name="color" invalidValueTreatment="asValue" invalidValueReplacement="red"/>
+				<MiningField name="income"/>
+				<MiningField name="score" outliers="asMissingValues" lowValue="0" highValue="1" missingValueReplacement="0.5"/>
+				<MiningField name="class" usageType="predicted"/>
+			</MiningSchema>
+			<Node><True/></Node>
+		</TreeModel>
+	</PMML>`
+
+	var out schema.PMML
+	body, global, code := scopeFor(input, &out)
+	global.DataDictionary(*out.DataDictionary)
+	body.With(Append("local field = v.field"))
+	body.MiningSchema(out.Models[0].TreeModel.MiningSchema, global).With(
+		Append("return v[field]"),
+	)
+
+	assert.Contains(t, code(), `{name='age', dataType='integer', optype='continuous', replacement=40, invalid='asMissing', outliers='asExtremeValues', low=18, high=99, intervals={{'closedClosed', 0, 120}}},`)
+	assert.Contains(t, code(), `{name='color', dataType='string', optype='categorical', invalid='asValue', invalidValue='red', values={['red']='valid', ['green']='valid', ['blue']='valid', ['n/a']='missing'}, closed=true},`)
+	assert.NotContains(t, code(), `name='class'`)
+
+	td := []struct {
+		input  map[string]interface{}
+		expect string
+		err    bool
+	}{
+		{input: map[string]interface{}{"age": nil}, expect: "40"},
+		{input: map[string]interface{}{"age": 10}, expect: "18"},
+		{input: map[string]interface{}{"age": 150}, expect: "40"},
+		{input: map[string]interface{}{"age": "35"}, expect: "35"},
+		{input: map[string]interface{}{"age": 35.5}, expect: "40"},
+		{input: map[string]interface{}{"color": "green"}, expect: "green"},
+		{input: map[string]interface{}{"color": "purple"}, expect: "red"},
+		{input: map[string]interface{}{"color": "n/a"}, expect: "(nil)"},
+		{input: map[string]interface{}{"income": 12.5}, expect: "12.5"},
+		{input: map[string]interface{}{"income": "abc"}, err: true},
+		{input: map[string]interface{}{"score": 0.25}, expect: "0.25"},
+		{input: map[string]interface{}{"score": 2}, expect: "0.5"},
+	}
+
+	s := makeScript(code())
+	for _, tt := range td {
+		for name := range tt.input {
+			tt.input["field"] = name
+			break
+		}
+
+		v, err := s.Run(context.Background(), tt.input)
+		if tt.err {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expect, v.String(), tt.input)
+	}
+}
@@ -0,0 +1,186 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The activation functions of the neurons.
+var activations = map[string]bool{
+	"threshold":   true,
+	"logistic":    true,
+	"tanh":        true,
+	"identity":    true,
+	"exponential": true,
+	"reciprocal":  true,
+	"square":      true,
+	"Gauss":       true,
+	"sine":        true,
+	"cosine":      true,
+	"Elliott":     true,
+	"arctan":      true,
+	"rectifier":   true,
+	"radialBasis": true,
+}
+
+// NeuralNetwork generates the LUA code for the element.
+func (s *Scope) NeuralNetwork(v schema.NeuralNetwork, global *Scope) *Scope {
+	options := NewStatement().Append("{functionName=").String(v.FunctionName)
+
+	inputs := NewScope()
+	for _, input := range v.Inputs {
+		inputs.NeuralInput(input, global)
+	}
+
+	layers := NewScope()
+	for _, layer := range v.Layers {
+		layers.NeuralLayer(layer, v)
+	}
+
+	outputs := NewScope()
+	for _, output := range v.Outputs {
+		outputs.NeuralOutput(output, global)
+	}
+
+	global.Require("neural")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or neural.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			inputs,
+			Append("}, {"),
+			layers,
+			Append("}, {"),
+			outputs,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// NeuralInput generates the LUA code for the element, which is the function computing the
+// value of the input neuron out of its derived field.
+func (s *Scope) NeuralInput(v schema.NeuralInput, global *Scope) *Scope {
+	return s.With(
+		NewStatement().Append("{id=").String(v.ID).Append(", eval=function(v)"),
+		NewScope().With(
+			NewStatement().Return().Expression(v.DerivedField.Expression, global),
+		),
+		Append("end},"),
+	)
+}
+
+// NeuralLayer generates the LUA code for the element. The activation function, the normalization
+// method, the threshold, the width and the altitude of the network are used for the layers which
+// do not specify their own.
+func (s *Scope) NeuralLayer(v schema.NeuralLayer, network schema.NeuralNetwork) *Scope {
+	activation := v.ActivationFunction
+	if activation == "" {
+		activation = network.ActivationFunction
+	}
+
+	normalization := v.NormalizationMethod
+	if normalization == "" {
+		normalization = network.NormalizationMethod
+	}
+
+	threshold, width, altitude := network.Threshold, network.Width, network.Altitude
+	if v.Threshold != nil {
+		threshold = *v.Threshold
+	}
+	if v.Width != nil {
+		width = v.Width
+	}
+	if v.Altitude != nil {
+		altitude = *v.Altitude
+	}
+
+	layer := NewStatement().Append("{activation=").String(activation)
+	if !activations[activation] {
+		layer.Error("activation function %s is not supported", activation)
+	}
+
+	switch normalization {
+	case "", "none":
+	case "softmax", "simplemax":
+		layer.Append(", normalization=").String(normalization)
+	default:
+		layer.Error("normalization method %s is not supported", normalization)
+	}
+
+	switch activation {
+	case "threshold":
+		layer.Append(", threshold=%s", formatFloat(threshold))
+	case "radialBasis":
+		layer.Append(", altitude=%s", formatFloat(altitude))
+		if width != nil {
+			layer.Append(", width=%s", formatFloat(*width))
+		}
+	}
+
+	neurons := NewScope()
+	for _, n := range v.Neurons {
+		if activation == "radialBasis" && width == nil && n.Width == nil {
+			neurons.With(NewStatement().Error("neuron %s requires a width", n.ID))
+			continue
+		}
+		neurons.With(NewStatement().Neuron(n))
+	}
+
+	return s.With(
+		layer.Append(", neurons={"),
+		neurons,
+		Append("}},"),
+	)
+}
+
+// Neuron generates the LUA code for the element.
+func (s *Statement) Neuron(v schema.Neuron) *Statement {
+	s.Append("{id=").String(v.ID).Append(", bias=%s", formatFloat(v.Bias))
+	if v.Width != nil {
+		s.Append(", width=%s", formatFloat(*v.Width))
+	}
+	if v.Altitude != nil {
+		s.Append(", altitude=%s", formatFloat(*v.Altitude))
+	}
+
+	s.Append(", weights={")
+	for i, c := range v.Connections {
+		s.Append("{").String(c.From).Append(", %s}", formatFloat(c.Weight))
+		if i+1 < len(v.Connections) {
+			s.Append(", ")
+		}
+	}
+	return s.Append("}},")
+}
+
+// NeuralOutput generates the LUA code for the element. The output neuron either gives the
+// probability of a category, or the value of the target field once denormalized.
+func (s *Scope) NeuralOutput(v schema.NeuralOutput, global *Scope) *Scope {
+	output := NewStatement().Append("{neuron=").String(v.OutputNeuron)
+	expr := v.DerivedField.Expression
+	switch {
+	case expr == nil:
+		output.Error("neural output %s has no expression", v.OutputNeuron)
+	case expr.NormDiscrete != nil:
+		field, _ := global.DataField(expr.NormDiscrete.Field)
+		output.Append(", value=").Literal(expr.NormDiscrete.Value, field.DataType)
+	case expr.NormContinuous != nil && len(expr.NormContinuous.LinearNorms) < 2:
+		output.Error("neural output %s requires at least two linear norms", v.OutputNeuron)
+	case expr.NormContinuous != nil:
+		output.Append(", norms={")
+		for i, n := range expr.NormContinuous.LinearNorms {
+			output.Append("{%s, %s}", formatFloat(n.Orig), formatFloat(n.Norm))
+			if i+1 < len(expr.NormContinuous.LinearNorms) {
+				output.Append(", ")
+			}
+		}
+		output.Append("}")
+	case expr.FieldRef != nil:
+	default:
+		output.Error("neural output %s is not supported", v.OutputNeuron)
+	}
+
+	return s.With(output.Append("},"))
+}
@@ -0,0 +1,166 @@
+local neural = {}
+
+-- NewModel creates a neural network with its options, its input neurons, its layers and its
+-- output neurons. Each input contains the function which computes its value, each layer contains
+-- its activation function, its normalization method and its neurons, and each output contains
+-- the neuron which gives either the probability of its category or the value of the target.
+function neural.NewModel(options, inputs, layers, outputs)
+    local m = options
+    m.inputs = inputs
+    m.layers = layers
+    m.outputs = outputs
+
+    -- Function which evaluates the network and returns the result table
+    m.eval = function(v)
+        return neural.Evaluate(m, v)
+    end
+    return m
+end
+
+-- Evaluate propagates the record through the layers of the network and returns the result, or
+-- nil if one of the inputs is missing.
+function neural.Evaluate(m, v)
+    local y = {}
+    for i=1, #m.inputs do
+        local x = m.inputs[i].eval(v)
+        if Unknown(x) then
+            return nil
+        end
+        y[m.inputs[i].id] = x
+    end
+
+    for i=1, #m.layers do
+        neural.Layer(m.layers[i], y)
+    end
+
+    if m.functionName == 'classification' then
+        return neural.Classify(m, y)
+    end
+    return neural.Predict(m, y)
+end
+
+-- Layer computes the outputs of the neurons of the layer out of the outputs of the neurons they
+-- are connected to, and normalizes them according to the normalization method of the layer.
+function neural.Layer(l, y)
+    local out = {}
+    for i=1, #l.neurons do
+        out[i] = neural.Neuron(l, l.neurons[i], y)
+    end
+
+    if l.normalization == 'softmax' then
+        local max, sum = math.max(unpack(out)), 0
+        for i=1, #out do
+            out[i] = math.exp(out[i] - max)
+            sum = sum + out[i]
+        end
+        for i=1, #out do
+            out[i] = out[i] / sum
+        end
+    elseif l.normalization == 'simplemax' then
+        local sum = 0
+        for i=1, #out do
+            sum = sum + out[i]
+        end
+        for i=1, #out do
+            out[i] = out[i] / sum
+        end
+    end
+
+    for i=1, #l.neurons do
+        y[l.neurons[i].id] = out[i]
+    end
+end
+
+-- Neuron returns the output of the neuron, which is its activation function applied to the
+-- weighted sum of its inputs and its bias. The radial basis neurons use the distance between
+-- their inputs and their weights instead, scaled by their width and their altitude.
+function neural.Neuron(l, n, y)
+    local z, weights = 0, n.weights
+    if l.activation == 'radialBasis' then
+        local width, altitude = n.width or l.width, n.altitude or l.altitude
+        for i=1, #weights do
+            local d = y[weights[i][1]] - weights[i][2]
+            z = z + d * d
+        end
+        z = z / (2 * width * width)
+        return math.exp(#weights * math.log(altitude) - z)
+    end
+
+    z = n.bias
+    for i=1, #weights do
+        z = z + y[weights[i][1]] * weights[i][2]
+    end
+    return neural.activations[l.activation](z, l)
+end
+
+-- The activation functions, by their name
+neural.activations = {
+    threshold = function(z, l)
+        if z > l.threshold then
+            return 1
+        end
+        return 0
+    end,
+    logistic = function(z) return 1 / (1 + math.exp(-z)) end,
+    tanh = function(z) return math.tanh(z) end,
+    identity = function(z) return z end,
+    exponential = function(z) return math.exp(z) end,
+    reciprocal = function(z) return 1 / z end,
+    square = function(z) return z * z end,
+    Gauss = function(z) return math.exp(-(z * z)) end,
+    sine = function(z) return math.sin(z) end,
+    cosine = function(z) return math.cos(z) end,
+    Elliott = function(z) return z / (1 + math.abs(z)) end,
+    arctan = function(z) return 2 * math.atan(z) / math.pi end,
+    rectifier = function(z) return math.max(0, z) end,
+}
+
+-- Classify returns the category with the highest probability along with the probabilities of
+-- every category, which are given by the output neurons.
+function neural.Classify(m, y)
+    local r = {probabilities = {}}
+    local best = nil
+    for i=1, #m.outputs do
+        local o = m.outputs[i]
+        local p = y[o.neuron]
+        r.probabilities[tostring(o.value)] = p
+        if best == nil or p > y[m.outputs[best].neuron] then
+            best = i
+        end
+    end
+
+    if best ~= nil then
+        r.value = m.outputs[best].value
+    end
+    return r
+end
+
+-- Predict returns the value of the target field, which is the output of the first output neuron
+-- denormalized through its linear norms.
+function neural.Predict(m, y)
+    local o = m.outputs[1]
+    if o == nil then
+        return nil
+    end
+
+    local x = y[o.neuron]
+    if o.norms ~= nil then
+        x = neural.Denormalize(x, o.norms)
+    end
+    return {value = x}
+end
+
+-- Denormalize returns the original value of a normalized value, which is the inverse of the
+-- piecewise linear interpolation of the linear norms. The values outside of the range of the
+-- norms are extrapolated from the first or the last interval.
+function neural.Denormalize(x, norms)
+    local i = 1
+    while i < #norms - 1 and x > norms[i + 1][2] do
+        i = i + 1
+    end
+
+    local a, b = norms[i], norms[i + 1]
+    return a[1] + (x - a[2]) * (b[1] - a[1]) / (b[2] - a[2])
+end
+
+return neural
@@ -0,0 +1,169 @@
+package pmml2lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeuralNetwork_Classification(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/neural1.xml")
+	assert.NoError(t, err)
+
+	for _, method := range []string{"softmax", "simplemax"} {
+		t.Run(method, func(t *testing.T) {
+			code, err := Convert([]byte(strings.Replace(string(b), `"softmax"`, `"`+method+`"`, 1)))
+			assert.NoError(t, err)
+			assert.Contains(t, string(code), "normalization='"+method+"'")
+
+			s := makeScript(string(code))
+			for _, tt := range []struct {
+				age float64
+				sex string
+			}{{age: 30, sex: "male"}, {age: 80, sex: "female"}, {age: 0, sex: "male"}} {
+				yes, no := approval(tt.age, tt.sex, method)
+				r, err := runScript(s, map[string]interface{}{"age": tt.age, "sex": tt.sex})
+				assert.NoError(t, err)
+				assert.InDelta(t, yes, r.Probability("yes"), 1e-9, tt)
+				assert.InDelta(t, no, r.Probability("no"), 1e-9, tt)
+				assert.InDelta(t, yes, r.Output("Probability"), 1e-9, tt)
+				if yes > no {
+					assert.Equal(t, "yes", r.Value, tt)
+				} else {
+					assert.Equal(t, "no", r.Value, tt)
+				}
+			}
+
+			r, err := runScript(s, map[string]interface{}{"sex": "male"})
+			assert.NoError(t, err)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestNeuralNetwork_Regression(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/neural2.xml")
+	assert.NoError(t, err)
+
+	for _, bias := range []float64{0.05, 0.35} {
+		code, err := Convert([]byte(strings.Replace(string(b), `bias="0.05"`, fmt.Sprintf(`bias="%v"`, bias), 1)))
+		assert.NoError(t, err)
+
+		s := makeScript(string(code))
+		for _, x := range []float64{1, -3, 4} {
+			y := bias + 0.8*math.Tanh(0.1+0.5*x) - 0.4*math.Tanh(-0.2+0.3*x)
+			expect := 100 * y
+			if y > 0.5 {
+				expect = 50 + (y-0.5)*300
+			}
+
+			r, err := runScript(s, map[string]interface{}{"x": x})
+			assert.NoError(t, err)
+			assert.InDelta(t, expect, r.Value, 1e-9, x)
+		}
+	}
+}
+
+func TestNeuralNetwork_Activation(t *testing.T) {
+	z := 0.5 + 2*0.3
+	td := []struct {
+		activation string
+		layer      string
+		expect     float64
+	}{
+		{activation: "threshold", layer: `threshold="1"`, expect: 1},
+		{activation: "threshold", layer: `threshold="1.5"`, expect: 0},
+		{activation: "logistic", expect: 1 / (1 + math.Exp(-z))},
+		{activation: "tanh", expect: math.Tanh(z)},
+		{activation: "identity", expect: z},
+		{activation: "exponential", expect: math.Exp(z)},
+		{activation: "reciprocal", expect: 1 / z},
+		{activation: "square", expect: z * z},
+		{activation: "Gauss", expect: math.Exp(-z * z)},
+		{activation: "sine", expect: math.Sin(z)},
+		{activation: "cosine", expect: math.Cos(z)},
+		{activation: "Elliott", expect: z / (1 + math.Abs(z))},
+		{activation: "arctan", expect: 2 * math.Atan(z) / math.Pi},
+		{activation: "rectifier", expect: z},
+		{activation: "radialBasis", layer: `width="2" altitude="1.5"`, expect: math.Exp(math.Log(1.5) - (0.3-2)*(0.3-2)/8)},
+	}
+
+	for _, tc := range td {
+		t.Run(tc.activation, func(t *testing.T) {
+			code, err := Convert([]byte(fmt.Sprintf(`<PMML version="4.4">
+				<NeuralNetwork modelName="net" functionName="regression" activationFunction="identity">
+					<MiningSchema><MiningField name="x"/><MiningField name="y" usageType="predicted"/></MiningSchema>
+					<NeuralInputs>
+						<NeuralInput id="0"><DerivedField><FieldRef field="x"/></DerivedField></NeuralInput>
+					</NeuralInputs>
+					<NeuralLayer activationFunction="%s" %s>
+						<Neuron id="1" bias="0.5"><Con from="0" weight="2"/></Neuron>
+					</NeuralLayer>
+					<NeuralOutputs>
+						<NeuralOutput outputNeuron="1"><DerivedField><FieldRef field="y"/></DerivedField></NeuralOutput>
+					</NeuralOutputs>
+				</NeuralNetwork>
+			</PMML>`, tc.activation, tc.layer)))
+			assert.NoError(t, err)
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 0.3})
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expect, r.Value, 1e-9)
+		})
+	}
+}
+
+func TestNeuralNetwork_Error(t *testing.T) {
+	layer := `<NeuralLayer><Neuron id="1" bias="0"/></NeuralLayer>`
+	output := func(expr string) string {
+		return `<NeuralOutputs><NeuralOutput outputNeuron="1"><DerivedField optype="continuous" dataType="double">` +
+			expr + `</DerivedField></NeuralOutput></NeuralOutputs>`
+	}
+
+	tests := []struct {
+		options string
+		body    string
+		err     string
+	}{
+		{options: `activationFunction="unknown"`, body: layer, err: "activation function unknown is not supported"},
+		{options: `activationFunction="identity" normalizationMethod="unknown"`, body: layer, err: "normalization method unknown is not supported"},
+		{options: `activationFunction="radialBasis"`, body: layer, err: "neuron 1 requires a width"},
+		{options: `activationFunction="identity"`, body: layer + output(``), err: "neural output 1 has no expression"},
+		{options: `activationFunction="identity"`, body: layer + output(`<Constant>1</Constant>`), err: "neural output 1 is not supported"},
+		{options: `activationFunction="identity"`, body: layer + output(`<NormContinuous field="y"/>`), err: "neural output 1 requires at least two linear norms"},
+		{options: `activationFunction="radialBasis" width="1"`, body: layer},
+	}
+
+	for _, tc := range tests {
+		_, err := Convert([]byte(`<PMML version="4.4">
+			<DataDictionary><DataField name="y" optype="continuous" dataType="double"/></DataDictionary>
+			<NeuralNetwork modelName="x" functionName="regression" ` + tc.options + `><MiningSchema/>` + tc.body + `</NeuralNetwork>
+		</PMML>`))
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.err)
+		}
+	}
+}
+
+// approval computes the probabilities of the neural network of the first fixture
+func approval(age float64, sex, normalization string) (float64, float64) {
+	male := 0.0
+	if sex == "male" {
+		male = 1
+	}
+
+	logistic := func(z float64) float64 { return 1 / (1 + math.Exp(-z)) }
+	h2 := logistic(-1 + 2*age/100 + 0.5*male)
+	h3 := logistic(0.5 - age/100 + male)
+	yes, no := 1.5*h2-h3, 0.2-0.5*h2+h3
+	if normalization == "softmax" {
+		yes, no = math.Exp(yes), math.Exp(no)
+	}
+	return yes / (yes + no), no / (yes + no)
+}
@@ -0,0 +1,116 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The result features which can be computed for an output field.
+var features = map[string]bool{
+	"predictedValue":        true,
+	"predictedDisplayValue": true,
+	"probability":           true,
+	"affinity":              true,
+	"entityId":              true,
+	"standardError":         true,
+	"confidence":            true,
+	"reasonCode":            true,
+	"transformedValue":      true,
+	"decision":              true,
+	"ruleValue":             true,
+}
+
+// Output generates the LUA code which returns the result of the model. If the model declares
+// an output, the output fields are computed out of the result and returned along with it.
+func (s *Scope) Output(v *schema.Output, target, result string, global *Scope) *Scope {
+	if v == nil || len(v.OutputFields) == 0 {
+		return s.With(NewStatement().Return().Append(result))
+	}
+
+	fields := NewScope()
+	for _, f := range v.OutputFields {
+		fields.OutputField(f, target, global)
+	}
+
+	global.Require("output")
+	return s.With(
+		Append("return output.Output(%s, v, {", result),
+		fields,
+		Append("})"),
+	)
+}
+
+// OutputField generates the LUA code for the element.
+func (s *Scope) OutputField(v schema.OutputField, target string, global *Scope) *Scope {
+	if v.TargetField != "" {
+		target = v.TargetField
+	}
+
+	field := NewStatement().Append("{name=").String(v.Name).Append(", feature=").String(v.Feature)
+	if !features[v.Feature] {
+		field.Error("output feature %s is not supported", v.Feature)
+	}
+	if v.SegmentID != "" {
+		field.Error("output field %s refers to segment %s, which is not supported", v.Name, v.SegmentID)
+	}
+	if v.Value != "" {
+		field.Append(", value=").String(string(v.Value))
+	}
+	if v.Rank != 1 {
+		field.Append(", rank=%d", v.Rank)
+	}
+	if !v.IsFinalResult {
+		field.Append(", final=false")
+	}
+
+	// The association rules are selected and ranked for each output field
+	if v.Feature == "ruleValue" {
+		field.RuleValue(v)
+	}
+
+	// The display values of the target field
+	if f, ok := global.DataField(target); ok && v.Feature == "predictedDisplayValue" {
+		field.DisplayValues(f)
+	}
+
+	// The transformed values and the decisions are computed by an expression
+	if v.Feature != "transformedValue" && v.Feature != "decision" {
+		return s.With(field.Append("},"))
+	}
+
+	if v.Expression == nil {
+		return s.With(field.Error("output field %s requires an expression", v.Name))
+	}
+
+	return s.With(
+		field.Append(", expr=function(v)"),
+		NewScope().With(
+			NewStatement().Return().Expression(v.Expression, global),
+		),
+		Append("end},"),
+	)
+}
+
+// DisplayValues generates the LUA table which maps the values of the field to their display
+// values.
+func (s *Statement) DisplayValues(v schema.DataField) *Statement {
+	first := true
+	for _, value := range v.Values {
+		if value.DisplayValue == "" {
+			continue
+		}
+
+		if first {
+			s.Append(", display={")
+		} else {
+			s.Append(", ")
+		}
+
+		first = false
+		s.Append("[").Literal(value.Value, v.DataType).Append("]=").String(value.DisplayValue)
+	}
+
+	if !first {
+		s.Append("}")
+	}
+	return s
+}
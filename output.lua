@@ -0,0 +1,95 @@
+local output = {}
+
+-- Output computes the output fields of the model out of its result and the input record. The
+-- output fields may refer to the input fields and to the output fields which precede them.
+function output.Output(r, v, fields)
+    local result = r or {}
+    local values = setmetatable({}, {__index = v})
+    local out = {}
+    for i=1, #fields do
+        local f = fields[i]
+        local x = output.features[f.feature](result, f, values)
+        values[f.name] = x
+        if f.final ~= false then
+            out[f.name] = x
+        end
+    end
+
+    if next(out) == nil then
+        return r
+    end
+
+    result.outputs = out
+    return result
+end
+
+-- Lookup returns the value of the map for the class of the output field, or for the predicted
+-- value if the output field does not specify a class.
+function output.Lookup(m, r, f)
+    local class = f.value
+    if class == nil then
+        class = r.value
+    end
+
+    if m == nil or class == nil then
+        return nil
+    end
+    return m[tostring(class)]
+end
+
+-- The result features of the output fields, by their name
+output.features = {
+    predictedValue = function(r, f)
+        return r.value
+    end,
+    predictedDisplayValue = function(r, f)
+        if r.displayValue ~= nil then
+            return r.displayValue
+        end
+        if r.value ~= nil and f.display ~= nil and f.display[r.value] ~= nil then
+            return f.display[r.value]
+        end
+        return r.value
+    end,
+    probability = function(r, f)
+        return output.Lookup(r.probabilities, r, f)
+    end,
+    confidence = function(r, f)
+        return output.Lookup(r.confidences, r, f)
+    end,
+    affinity = function(r, f)
+        if f.value == nil then
+            return r.affinity
+        end
+        return output.Lookup(r.affinities, r, f)
+    end,
+    entityId = function(r, f)
+        if r.neighbors ~= nil then
+            return r.neighbors[f.rank or 1]
+        end
+        return r.entityId
+    end,
+    standardError = function(r, f)
+        return r.standardError
+    end,
+    reasonCode = function(r, f)
+        if r.reasonCodes == nil then
+            return nil
+        end
+        return r.reasonCodes[f.rank or 1]
+    end,
+    transformedValue = function(r, f, v)
+        return f.expr(v)
+    end,
+    decision = function(r, f, v)
+        return f.expr(v)
+    end,
+    ruleValue = function(r, f)
+        if r.ruleValue == nil then
+            return nil
+        end
+        return r.ruleValue(f)
+    end,
+}
+
+return output
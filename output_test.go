@@ -0,0 +1,71 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/kelindar/pmml2lua/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutput(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/tree2.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	td := []struct {
+		input  map[string]interface{}
+		expect map[string]interface{}
+	}{
+		{
+			input: map[string]interface{}{"temperature": 65, "humidity": 75, "outlook": "sunny"},
+			expect: map[string]interface{}{
+				"PredictedClass":    "will play",
+				"DisplayClass":      "Will Play",
+				"ProbabilityToPlay": 0.9,
+				"Confidence":        0.9,
+				"Node":              "3",
+				"RoundedPercent":    90.0,
+				"Decision":          "go",
+			},
+		},
+		{
+			input: map[string]interface{}{"temperature": 65, "humidity": 75, "outlook": "rain"},
+			expect: map[string]interface{}{
+				"PredictedClass":    "may play",
+				"DisplayClass":      "May Play",
+				"ProbabilityToPlay": 0.4,
+				"Confidence":        0.56,
+				"Node":              "5",
+				"RoundedPercent":    40.0,
+				"Decision":          "stay",
+			},
+		},
+	}
+
+	for _, tt := range td {
+		r, err := runScript(s, tt.input)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expect, r.Outputs, tt.input)
+		assert.Nil(t, r.Output("Percent"))
+	}
+}
+
+func TestOutputField_Error(t *testing.T) {
+	tests := []struct {
+		field schema.OutputField
+		err   string
+	}{
+		{field: schema.OutputField{Name: "x", Feature: "unknown"}, err: "output feature unknown is not supported"},
+		{field: schema.OutputField{Name: "x", Feature: "transformedValue"}, err: "output field x requires an expression"},
+		{field: schema.OutputField{Name: "x", Feature: "predictedValue", SegmentID: "2"}, err: "output field x refers to segment 2, which is not supported"},
+	}
+
+	for _, tc := range tests {
+		_, err := NewScope().OutputField(tc.field, "", NewScope()).Compile()
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
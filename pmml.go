@@ -0,0 +1,97 @@
+package pmml2lua
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// Convert parses a PMML document and generates the LUA script for it.
+func Convert(document []byte) ([]byte, error) {
+	var v schema.PMML
+	if err := xml.Unmarshal(document, &v); err != nil {
+		return nil, err
+	}
+
+	return NewScope().PMML(v).Compile()
+}
+
+// PMML generates the LUA code for the document. Every model of the document is generated
+// as a separate function and the main function returns the JSON-encoded result of the first
+// one, which can be decoded with ResultOf.
+func (s *Scope) PMML(v schema.PMML) *Scope {
+	s.Require("tree").Require("json")
+	if len(v.Models) == 0 {
+		return s.With(NewStatement().Error("document does not contain any model"))
+	}
+
+	if v.DataDictionary != nil {
+		s.DataDictionary(*v.DataDictionary)
+	}
+	if v.TransformationDictionary != nil {
+		s.TransformationDictionary(*v.TransformationDictionary)
+	}
+
+	names := make([]string, 0, len(v.Models))
+	for i, m := range v.Models {
+		if m.Name() == "" {
+			m = m.Named(fmt.Sprintf("model%d", i+1))
+		}
+
+		names = append(names, m.Name())
+		s.Model(m, s)
+	}
+
+	s.Function("main", "v").With(
+		NewStatement().Return().Call("json.encode", names[0]+"(v)"),
+	)
+	return s
+}
+
+// Model generates the LUA code for the element. The derived fields of its local transformations
+// are declared in a local scope, so that they are only visible to the model and its segments.
+func (s *Scope) Model(v schema.Model, global *Scope) *Scope {
+	if t := v.LocalTransformations(); t != nil {
+		global = global.Local(t.DerivedFields)
+	}
+
+	switch {
+	case v.TreeModel != nil:
+		return s.DecisionTree(*v.TreeModel, global)
+	case v.RegressionModel != nil:
+		return s.RegressionModel(*v.RegressionModel, global)
+	case v.GeneralRegressionModel != nil:
+		return s.GeneralRegressionModel(*v.GeneralRegressionModel, global)
+	case v.MiningModel != nil:
+		return s.MiningModel(*v.MiningModel, global)
+	case v.Scorecard != nil:
+		return s.Scorecard(*v.Scorecard, global)
+	case v.RuleSetModel != nil:
+		return s.RuleSetModel(*v.RuleSetModel, global)
+	case v.NeuralNetwork != nil:
+		return s.NeuralNetwork(*v.NeuralNetwork, global)
+	case v.NaiveBayesModel != nil:
+		return s.NaiveBayesModel(*v.NaiveBayesModel, global)
+	case v.SupportVectorMachineModel != nil:
+		return s.SupportVectorMachineModel(*v.SupportVectorMachineModel, global)
+	case v.ClusteringModel != nil:
+		return s.ClusteringModel(*v.ClusteringModel, global)
+	case v.NearestNeighborModel != nil:
+		return s.NearestNeighborModel(*v.NearestNeighborModel, global)
+	case v.AnomalyDetectionModel != nil:
+		return s.AnomalyDetectionModel(*v.AnomalyDetectionModel, global)
+	case v.TimeSeriesModel != nil:
+		return s.TimeSeriesModel(*v.TimeSeriesModel, global)
+	case v.AssociationModel != nil:
+		return s.AssociationModel(*v.AssociationModel, global)
+	case v.BaselineModel != nil:
+		return s.BaselineModel(*v.BaselineModel, global)
+	case v.GaussianProcessModel != nil:
+		return s.GaussianProcessModel(*v.GaussianProcessModel, global)
+	case v.TextModel != nil:
+		return s.TextModel(*v.TextModel, global)
+	default:
+		return s.With(NewStatement().Error("model type is not supported"))
+	}
+}
@@ -0,0 +1,38 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	input, err := ioutil.ReadFile("fixtures/tree1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(input)
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), `local tree = require("tree")`)
+	assert.Contains(t, string(code), "function golfing(v)")
+	assert.Contains(t, string(code), "function main(v)\n\treturn json.encode(golfing(v))\nend")
+}
+
+func TestConvert_Unnamed(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<TreeModel functionName="classification">
+			<Node score="yes"><True/></Node>
+		</TreeModel>
+	</PMML>`))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "function model1(v)")
+	assert.Contains(t, string(code), "return json.encode(model1(v))")
+}
+
+func TestConvert_Error(t *testing.T) {
+	_, err := Convert([]byte(`<TreeModel functionName="classification"/>`))
+	assert.Error(t, err)
+
+	_, err = Convert([]byte(`<PMML version="4.4"><Header/></PMML>`))
+	assert.Error(t, err)
+}
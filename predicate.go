@@ -12,7 +12,7 @@ import (
 func (s *Statement) Predicate(v *schema.Predicate, global *Scope) *Statement {
 	switch {
 	case v.SimplePredicate != nil:
-		return s.SimplePredicate(*v.SimplePredicate)
+		return s.SimplePredicate(*v.SimplePredicate, global)
 	case v.CompoundPredicate != nil:
 		return s.CompoundPredicate(*v.CompoundPredicate, global)
 	case v.SimpleSetPredicate != nil:
@@ -73,17 +73,22 @@ func (s *Statement) BinaryOperator(v string) *Statement {
 	return s.Append(operator)
 }
 
-// SimplePredicate generates the LUA code for the element.
-func (s *Statement) SimplePredicate(v schema.SimplePredicate) *Statement {
+// SimplePredicate generates the LUA code for the element. The value is written with the
+// data type of the field, if the field is declared in the data dictionary. The comparison
+// is guarded so that it is unknown (nil) only if the field is missing, and a boolean field
+// which is false is still compared.
+func (s *Statement) SimplePredicate(v schema.SimplePredicate, global *Scope) *Statement {
 	if v.Operator == "isMissing" || v.Operator == "isNotMissing" {
 		return s.Field(v.Field).BinaryOperator(v.Operator)
 	}
 
-	return s.Field(v.Field).
-		Append(" and ").
+	field, _ := global.DataField(v.Field)
+	return s.Append("(").
+		Field(v.Field).
+		Append(" ~= nil or nil) and ").
 		Field(v.Field).
 		BinaryOperator(v.Operator).
-		Value(v.Value)
+		Literal(v.Value, field.DataType)
 }
 
 // ----------------------------------------------------------------------------
@@ -94,12 +99,30 @@ func (s *Statement) SimpleSetPredicate(v schema.SimpleSetPredicate, global *Scop
 		return s.Error("array must not be nil")
 	}
 
-	values, err := v.Array.CSV()
+	field, ok := global.DataField(v.Field)
+	if !ok {
+		values, err := v.Array.CSV()
+		if err != nil {
+			return s.Error(err.Error())
+		}
+
+		return s.Append("tree.%s(", strings.Title(v.Operator)).
+			Field(v.Field).
+			Append(", {%s; n=%d})", values, v.Array.Length)
+	}
+
+	// Write the values with the data type of the field
+	values, err := v.Array.Tokens()
 	if err != nil {
 		return s.Error(err.Error())
 	}
 
-	return s.Append("tree.%s(", strings.Title(v.Operator)).
-		Field(v.Field).
-		Append(", {%s; n=%d})", values, v.Array.Length)
+	s.Append("tree.%s(", strings.Title(v.Operator)).Field(v.Field).Append(", {")
+	for i, value := range values {
+		s.Literal(schema.Value(value), field.DataType)
+		if i+1 < len(values) {
+			s.Append(",")
+		}
+	}
+	return s.Append("; n=%d})", len(values))
 }
@@ -2,6 +2,7 @@ package pmml2lua
 
 import (
 	"context"
+	"encoding/xml"
 	"testing"
 
 	"github.com/kelindar/lua"
@@ -83,7 +84,7 @@ func TestSurrogate(t *testing.T) {
 	)
 
 	assert.Contains(t, code(),
-		`tree.Surrogate({tree.And({v.temperature and v.temperature < 90, v.temperature and v.temperature > 50; n=2}), v.humidity and v.humidity >= 80, false; n=3})`,
+		`tree.Surrogate({tree.And({(v.temperature ~= nil or nil) and v.temperature < 90, (v.temperature ~= nil or nil) and v.temperature > 50; n=2}), (v.humidity ~= nil or nil) and v.humidity >= 80, false; n=3})`,
 	)
 
 	s := makeScript(code())
@@ -114,7 +115,7 @@ func TestCompoundPredicate(t *testing.T) {
 	)
 
 	assert.Contains(t, code(),
-		`tree.Or({tree.And({v.temperature and v.temperature < 90, v.temperature and v.temperature > 50; n=2}), v.humidity and v.humidity >= 80, tree.IsNotIn(v.humidity, {1,2,3,4,5; n=5}); n=3})`,
+		`tree.Or({tree.And({(v.temperature ~= nil or nil) and v.temperature < 90, (v.temperature ~= nil or nil) and v.temperature > 50; n=2}), (v.humidity ~= nil or nil) and v.humidity >= 80, tree.IsNotIn(v.humidity, {1,2,3,4,5; n=5}); n=3})`,
 	)
 
 	s := makeScript(code())
@@ -134,61 +135,61 @@ func TestSimplePredicate(t *testing.T) {
 	}{
 		{
 			xml:    `<SimplePredicate field="wallet" operator="lessThan" value="0.08086312118570185"/>`,
-			lua:    `v.wallet and v.wallet < 0.08086312118570185`,
+			lua:    `(v.wallet ~= nil or nil) and v.wallet < 0.08086312118570185`,
 			input:  map[string]float64{},
 			expect: nil,
 		},
 		{
 			xml:    `<SimplePredicate field="wallet" operator="lessThan" value="0.08086312118570185"/>`,
-			lua:    `v.wallet and v.wallet < 0.08086312118570185`,
+			lua:    `(v.wallet ~= nil or nil) and v.wallet < 0.08086312118570185`,
 			input:  map[string]float64{"wallet": 0.05},
 			expect: true,
 		},
 		{
 			xml:    `<SimplePredicate field="wallet" operator="lessThan" value="0.08086312118570185"/>`,
-			lua:    `v.wallet and v.wallet < 0.08086312118570185`,
+			lua:    `(v.wallet ~= nil or nil) and v.wallet < 0.08086312118570185`,
 			input:  map[string]float64{"wallet": 0.09},
 			expect: false,
 		},
 		{
 			xml:    `<SimplePredicate field="wallet" operator="lessOrEqual" value="0.08"/>`,
-			lua:    `v.wallet and v.wallet <= 0.08`,
+			lua:    `(v.wallet ~= nil or nil) and v.wallet <= 0.08`,
 			input:  map[string]float64{"wallet": 0.08},
 			expect: true,
 		},
 		{
 			xml:    `<SimplePredicate field="name" operator="equal" value="Roman"/>`,
-			lua:    `v.name and v.name == 'Roman'`,
+			lua:    `(v.name ~= nil or nil) and v.name == 'Roman'`,
 			input:  map[string]string{"name": "Roman"},
 			expect: true,
 		},
 		{
 			xml:    `<SimplePredicate field="name" operator="notEqual" value="Wenbo"/>`,
-			lua:    `v.name and v.name ~= 'Wenbo'`,
+			lua:    `(v.name ~= nil or nil) and v.name ~= 'Wenbo'`,
 			input:  map[string]string{"name": "Roman"},
 			expect: true,
 		},
 		{
 			xml:    `<SimplePredicate field="name" operator="notEqual" value="Roman"/>`,
-			lua:    `v.name and v.name ~= 'Roman'`,
+			lua:    `(v.name ~= nil or nil) and v.name ~= 'Roman'`,
 			input:  map[string]string{"name": "Roman"},
 			expect: false,
 		},
 		{
 			xml:    `<SimplePredicate field="age" operator="greaterThan" value="30"/>`,
-			lua:    `v.age and v.age > 30`,
+			lua:    `(v.age ~= nil or nil) and v.age > 30`,
 			input:  map[string]float64{"age": 30},
 			expect: false,
 		},
 		{
 			xml:    `<SimplePredicate field="age" operator="greaterThan" value="30"/>`,
-			lua:    `v.age and v.age > 30`,
+			lua:    `(v.age ~= nil or nil) and v.age > 30`,
 			input:  map[string]float64{"age": 31},
 			expect: true,
 		},
 		{
 			xml:    `<SimplePredicate field="age" operator="greaterOrEqual" value="30"/>`,
-			lua:    `v.age and v.age >= 30`,
+			lua:    `(v.age ~= nil or nil) and v.age >= 30`,
 			input:  map[string]float64{"age": 30},
 			expect: true,
 		},
@@ -204,6 +205,24 @@ func TestSimplePredicate(t *testing.T) {
 			input:  map[string]int{"age": 12},
 			expect: true,
 		},
+		{
+			xml:    `<SimplePredicate field="petal length" operator="greaterThan" value="2.5"/>`,
+			lua:    `(v['petal length'] ~= nil or nil) and v['petal length'] > 2.5`,
+			input:  map[string]float64{"petal length": 4.5},
+			expect: true,
+		},
+		{
+			xml:    `<SimplePredicate field="Sepal.Length" operator="lessThan" value="5"/>`,
+			lua:    `(v['Sepal.Length'] ~= nil or nil) and v['Sepal.Length'] < 5`,
+			input:  map[string]float64{"Sepal.Length": 5.1},
+			expect: false,
+		},
+		{
+			xml:    `<SimplePredicate field="end" operator="isMissing" />`,
+			lua:    `v['end'] == nil `,
+			input:  map[string]int{},
+			expect: true,
+		},
 	}
 
 	for _, tt := range td {
@@ -211,9 +230,9 @@ func TestSimplePredicate(t *testing.T) {
 
 			// Generate the script
 			var out schema.Predicate
-			body, _, code := scopeFor(tt.xml, &out)
+			body, global, code := scopeFor(tt.xml, &out)
 			body.With(
-				NewStatement().Return().SimplePredicate(*out.SimplePredicate),
+				NewStatement().Return().SimplePredicate(*out.SimplePredicate, global),
 			)
 
 			// Code must contain the statement
@@ -235,3 +254,122 @@ func TestSimplePredicate(t *testing.T) {
 		})
 	}
 }
+
+func TestTypedPredicate(t *testing.T) {
+	dictionary := schema.DataDictionary{
+		DataFields: []schema.DataField{
+			{Name: "code", Optype: "categorical", DataType: "string"},
+			{Name: "age", Optype: "continuous", DataType: "integer"},
+			{Name: "income", Optype: "continuous", DataType: "double"},
+			{Name: "member", Optype: "categorical", DataType: "boolean"},
+		},
+	}
+
+	td := []struct {
+		xml    string      // The XML document to parse
+		lua    string      // The output LUA code
+		input  interface{} // The input data
+		expect interface{} // The expected result
+	}{
+		{
+			xml:    `<SimplePredicate field="code" operator="equal" value="1"/>`,
+			lua:    `(v.code ~= nil or nil) and v.code == '1'`,
+			input:  map[string]string{"code": "1"},
+			expect: "true",
+		},
+		{
+			xml:    `<SimplePredicate field="code" operator="equal" value="O'Brien"/>`,
+			lua:    `(v.code ~= nil or nil) and v.code == 'O\'Brien'`,
+			input:  map[string]string{"code": "O'Brien"},
+			expect: "true",
+		},
+		{
+			xml:    `<SimplePredicate field="age" operator="greaterThan" value="30.0"/>`,
+			lua:    `(v.age ~= nil or nil) and v.age > 30`,
+			input:  map[string]int{"age": 31},
+			expect: "true",
+		},
+		{
+			xml:    `<SimplePredicate field="income" operator="lessOrEqual" value="1e3"/>`,
+			lua:    `(v.income ~= nil or nil) and v.income <= 1000`,
+			input:  map[string]float64{"income": 999.5},
+			expect: "true",
+		},
+		{
+			xml:    `<SimplePredicate field="member" operator="equal" value="true"/>`,
+			lua:    `(v.member ~= nil or nil) and v.member == true`,
+			input:  map[string]bool{"member": true},
+			expect: "true",
+		},
+		{
+			xml:    `<SimplePredicate field="member" operator="equal" value="false"/>`,
+			lua:    `(v.member ~= nil or nil) and v.member == false`,
+			input:  map[string]bool{"member": false},
+			expect: "true",
+		},
+		{
+			xml:    `<SimplePredicate field="member" operator="equal" value="false"/>`,
+			lua:    `(v.member ~= nil or nil) and v.member == false`,
+			input:  map[string]bool{"member": true},
+			expect: "false",
+		},
+		{
+			xml:    `<SimplePredicate field="member" operator="notEqual" value="true"/>`,
+			lua:    `(v.member ~= nil or nil) and v.member ~= true`,
+			input:  map[string]bool{"member": false},
+			expect: "true",
+		},
+		{
+			xml:    `<SimpleSetPredicate field="code" booleanOperator="isIn"><Array n="3" type="int">1 2 3</Array></SimpleSetPredicate>`,
+			lua:    `tree.IsIn(v.code, {'1','2','3'; n=3})`,
+			input:  map[string]string{"code": "2"},
+			expect: "true",
+		},
+		{
+			xml:    `<SimpleSetPredicate field="age" booleanOperator="isNotIn"><Array n="2" type="string">"10" "20"</Array></SimpleSetPredicate>`,
+			lua:    `tree.IsNotIn(v.age, {10,20; n=2})`,
+			input:  map[string]int{"age": 20},
+			expect: "false",
+		},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.xml, func(t *testing.T) {
+			var out schema.Predicate
+			body, global, code := scopeFor(tt.xml, &out)
+			global.DataDictionary(dictionary)
+			body.With(
+				NewStatement().Return().Predicate(&out, global),
+			)
+
+			assert.Contains(t, code(), tt.lua)
+
+			s := makeScript(code())
+			v, err := s.Run(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, v.String())
+		})
+	}
+}
+
+func TestTypedPredicate_Error(t *testing.T) {
+	global := NewScope().DataDictionary(schema.DataDictionary{
+		DataFields: []schema.DataField{
+			{Name: "age", Optype: "continuous", DataType: "integer"},
+			{Name: "income", Optype: "continuous", DataType: "double"},
+		},
+	})
+
+	for _, input := range []string{
+		`<SimplePredicate field="age" operator="equal" value="abc"/>`,
+		`<SimplePredicate field="age" operator="equal" value="1.5"/>`,
+		`<SimplePredicate field="income" operator="equal" value="abc"/>`,
+		`<SimpleSetPredicate field="income" booleanOperator="isIn"><Array n="2" type="string">1 x</Array></SimpleSetPredicate>`,
+	} {
+		var out schema.Predicate
+		assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+
+		_, err := NewStatement().Predicate(&out, global).Compile()
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,97 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// RegressionModel generates the LUA code for the element.
+func (s *Scope) RegressionModel(v schema.RegressionModel, global *Scope) *Scope {
+	if len(v.RegressionTables) == 0 {
+		return s.With(NewStatement().Error("regression model %s has no regression table", v.ModelName))
+	}
+
+	target := v.MiningSchema.Target()
+	if target == "" {
+		target = v.TargetFieldName
+	}
+
+	options := NewStatement().Append("{functionName=").String(v.FunctionName)
+	if v.NormalizationMethod != "" {
+		options.Append(", normalization=").String(v.NormalizationMethod)
+	}
+	if field, ok := global.DataField(target); ok && field.Optype == "ordinal" {
+		options.Append(", ordinal=true")
+	}
+
+	tables := NewScope()
+	for _, t := range v.RegressionTables {
+		tables.With(NewStatement().RegressionTable(t, global).Append(","))
+	}
+
+	global.Require("regression")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or regression.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			tables,
+			Append("})"),
+		).
+		Output(v.Output, target, "model."+v.ModelName+".eval(v)", global)
+}
+
+// RegressionTable generates the LUA code for the element.
+func (s *Statement) RegressionTable(v schema.RegressionTable, global *Scope) *Statement {
+	s.Append("{")
+	if v.TargetCategory != "" {
+		s.Append("category=").String(v.TargetCategory).Append(", ")
+	}
+	s.Append("intercept=%s", formatFloat(v.Intercept))
+
+	if len(v.NumericPredictors) > 0 {
+		s.Append(", numeric={")
+		for i, p := range v.NumericPredictors {
+			s.Append("{").String(p.Name).Append(", %d, %s}", p.Exponent, formatFloat(p.Coefficient))
+			if i+1 < len(v.NumericPredictors) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	if len(v.CategoricalPredictors) > 0 {
+		s.Append(", categorical={")
+		for i, p := range v.CategoricalPredictors {
+			field, _ := global.DataField(p.Name)
+			s.Append("{").String(p.Name).Append(", ").
+				Literal(p.Value, field.DataType).
+				Append(", %s}", formatFloat(p.Coefficient))
+			if i+1 < len(v.CategoricalPredictors) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	if len(v.PredictorTerms) > 0 {
+		s.Append(", terms={")
+		for i, p := range v.PredictorTerms {
+			s.Append("{%s, {", formatFloat(p.Coefficient))
+			for j, ref := range p.FieldRefs {
+				s.String(ref.Field)
+				if j+1 < len(p.FieldRefs) {
+					s.Append(", ")
+				}
+			}
+			s.Append("}}")
+			if i+1 < len(v.PredictorTerms) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	return s.Append("}")
+}
@@ -0,0 +1,337 @@
+local expression = require("expression")
+local regression = {}
+
+-- NewModel creates a regression model with its options and its regression tables. Each table
+-- contains its target category, its intercept and its numeric, categorical and term predictors.
+function regression.NewModel(options, tables)
+    local m = options
+    m.normalization = m.normalization or 'none'
+    m.tables = tables
+
+    -- Function which evaluates the regression tables and returns the result table
+    m.eval = function(v)
+        if m.functionName == 'classification' then
+            return regression.Classify(m, v)
+        end
+        return regression.Predict(m, v)
+    end
+    return m
+end
+
+-- Evaluate returns the value of the regression table for the record, or nil if one of the
+-- predictors refers to a missing value.
+function regression.Evaluate(t, v)
+    local y = t.intercept or 0
+    local numeric, categorical, terms = t.numeric or {}, t.categorical or {}, t.terms or {}
+    for i=1, #numeric do
+        local p = numeric[i]
+        local x = v[p[1]]
+        if Unknown(x) then
+            return nil
+        end
+        y = y + p[3] * x ^ p[2]
+    end
+
+    for i=1, #categorical do
+        local p = categorical[i]
+        local x = v[p[1]]
+        if Unknown(x) then
+            return nil
+        elseif x == p[2] then
+            y = y + p[3]
+        end
+    end
+
+    for i=1, #terms do
+        local product, fields = terms[i][1], terms[i][2]
+        for j=1, #fields do
+            local x = v[fields[j]]
+            if Unknown(x) then
+                return nil
+            end
+            product = product * x
+        end
+        y = y + product
+    end
+    return y
+end
+
+-- Predict returns the result of a regression model, which is the value of its first regression
+-- table transformed by the inverse link function of the normalization method.
+function regression.Predict(m, v)
+    local y = regression.Evaluate(m.tables[1], v)
+    if y == nil then
+        return nil
+    end
+    return {value = regression.Inverse(m.normalization)(y)}
+end
+
+-- Classify returns the result of a classification model, which is the category with the highest
+-- probability along with the probabilities of every category.
+function regression.Classify(m, v)
+    local n, y = #m.tables, {}
+    for i=1, n do
+        y[i] = regression.Evaluate(m.tables[i], v)
+        if y[i] == nil then
+            return nil
+        end
+    end
+
+    local p = regression.Normalize(m, y)
+    local r = {probabilities = {}}
+    local best = nil
+    for i=1, n do
+        local category = m.tables[i].category
+        r.probabilities[tostring(category)] = p[i]
+        if best == nil or p[i] > p[best] then
+            best = i
+        end
+    end
+
+    r.value = m.tables[best].category
+    return r
+end
+
+-- Normalize converts the values of the regression tables into the probabilities of the
+-- categories, according to the normalization method of the model.
+function regression.Normalize(m, y)
+    local n, p = #y, {}
+    if m.normalization == 'softmax' then
+        local max, sum = math.max(unpack(y)), 0
+        for i=1, n do
+            p[i] = math.exp(y[i] - max)
+            sum = sum + p[i]
+        end
+        for i=1, n do
+            p[i] = p[i] / sum
+        end
+        return p
+    end
+
+    if m.normalization == 'simplemax' then
+        local sum = 0
+        for i=1, n do
+            sum = sum + y[i]
+        end
+        for i=1, n do
+            p[i] = y[i] / sum
+        end
+        return p
+    end
+
+    -- The other methods give the cumulative probabilities of the ordered categories, or the
+    -- probabilities of all of the categories but the last one
+    local inverse, sum = regression.Inverse(m.normalization), 0
+    for i=1, n-1 do
+        local f = inverse(y[i])
+        if m.ordinal then
+            p[i] = f - sum
+            sum = f
+        else
+            p[i] = f
+            sum = sum + f
+        end
+    end
+
+    p[n] = 1 - sum
+    return p
+end
+
+-- Inverse returns the inverse of the link function of the normalization method.
+function regression.Inverse(method)
+    local fn = regression.inverses[method]
+    if fn == nil then
+        error("normalization method '" .. tostring(method) .. "' is not supported")
+    end
+    return fn
+end
+
+-- The inverse link functions, by the name of the normalization method
+regression.inverses = {
+    none = function(y) return y end,
+    simplemax = function(y) return y end,
+    softmax = function(y) return 1 / (1 + math.exp(-y)) end,
+    logit = function(y) return 1 / (1 + math.exp(-y)) end,
+    probit = function(y) return expression.NormalCDF(y) end,
+    cloglog = function(y) return 1 - math.exp(-math.exp(y)) end,
+    loglog = function(y) return math.exp(-math.exp(-y)) end,
+    cauchit = function(y) return 0.5 + math.atan(y) / math.pi end,
+    exp = function(y) return math.exp(y) end,
+}
+
+-- ----------------------------------------------------------------------------
+
+-- NewGeneral creates a general regression model with its options, the cells of its predictor
+-- to parameter matrix grouped by parameter and the cells of its parameter matrix.
+function regression.NewGeneral(options, cells, betas)
+    local m = options
+    m.cells = cells
+    m.betas = betas
+
+    -- Function which evaluates the model and returns the result table
+    m.eval = function(v)
+        return regression.General(m, v)
+    end
+    return m
+end
+
+-- General returns the result of a general regression model, depending on its type.
+function regression.General(m, v)
+    local offset, trials = m.offset or 0, m.trials or 1
+    if m.offsetField ~= nil then
+        offset = v[m.offsetField]
+    end
+    if m.trialsField ~= nil then
+        trials = v[m.trialsField]
+    end
+    if Unknown(offset) or Unknown(trials) then
+        return nil
+    end
+
+    if m.modelType == 'multinomialLogistic' then
+        return regression.Multinomial(m, v, offset)
+    elseif m.modelType == 'ordinalMultinomial' then
+        return regression.Ordinal(m, v, offset)
+    end
+
+    local eta = regression.Eta(m, v, nil)
+    if eta == nil then
+        return nil
+    end
+
+    if m.modelType == 'generalizedLinear' then
+        return {value = regression.Link(m)(eta + offset, m) * trials}
+    end
+    return {value = eta + offset}
+end
+
+-- Multinomial returns the probabilities of the categories of a multinomial logistic model.
+function regression.Multinomial(m, v, offset)
+    local y = {}
+    for i=1, #m.categories do
+        local eta = regression.Eta(m, v, m.categories[i])
+        if eta == nil then
+            return nil
+        end
+        y[i] = eta + offset
+    end
+    return regression.Categories(m.categories, regression.Normalize({normalization = 'softmax'}, y))
+end
+
+-- Ordinal returns the probabilities of the categories of an ordinal multinomial model, which are
+-- the differences of the cumulative probabilities of the ordered categories.
+function regression.Ordinal(m, v, offset)
+    local n, p, sum = #m.categories, {}, 0
+    local inverse = regression.Link(m)
+    for i=1, n-1 do
+        local eta = regression.Eta(m, v, m.categories[i])
+        if eta == nil then
+            return nil
+        end
+
+        local f = inverse(eta + offset, m)
+        p[i] = f - sum
+        sum = f
+    end
+
+    p[n] = 1 - sum
+    return regression.Categories(m.categories, p)
+end
+
+-- Categories creates the result table out of the probabilities of the categories.
+function regression.Categories(categories, p)
+    local r = {probabilities = {}}
+    local best = nil
+    for i=1, #categories do
+        r.probabilities[tostring(categories[i])] = p[i]
+        if best == nil or p[i] > p[best] then
+            best = i
+        end
+    end
+
+    if best ~= nil then
+        r.value = categories[best]
+    end
+    return r
+end
+
+-- Eta returns the linear predictor of the category, or nil if one of the predictors refers to
+-- a missing value. The parameters which do not have a category apply to all of the categories.
+function regression.Eta(m, v, category)
+    local eta = 0
+    for i=1, #m.betas do
+        local b = m.betas[i]
+        if b.category == nil or b.category == category then
+            local x = regression.Parameter(m, v, b.parameter, category)
+            if x == nil then
+                return nil
+            end
+            eta = eta + b.beta * x
+        end
+    end
+    return eta
+end
+
+-- Parameter returns the value of the parameter for the record, which is the product of its
+-- cells. A factor cell is 1 if the field has the value of the cell and 0 otherwise, and a
+-- covariate cell is the value of the field raised to the exponent of the cell.
+function regression.Parameter(m, v, name, category)
+    local x, cells = 1, m.cells[name] or {}
+    for i=1, #cells do
+        local c = cells[i]
+        if c.category == nil or c.category == category then
+            local value = v[c.field]
+            if Unknown(value) then
+                return nil
+            end
+
+            if c.value ~= nil then
+                if value ~= c.value then
+                    x = 0
+                end
+            else
+                x = x * value ^ (c.exponent or 1)
+            end
+        end
+    end
+    return x
+end
+
+-- Link returns the inverse of the link function of a general regression model.
+function regression.Link(m)
+    local fn = regression.links[m.link or 'identity']
+    if fn == nil then
+        error("link function '" .. tostring(m.link) .. "' is not supported")
+    end
+    return fn
+end
+
+-- The inverse link functions of the general regression models, by the name of the link function
+regression.links = {
+    identity = function(y) return y end,
+    log = function(y) return math.exp(y) end,
+    logit = regression.inverses.logit,
+    probit = regression.inverses.probit,
+    cloglog = regression.inverses.cloglog,
+    loglog = regression.inverses.loglog,
+    cauchit = regression.inverses.cauchit,
+    power = function(y, m)
+        local d = m.linkParameter or 0
+        if d == 0 then
+            return math.exp(y)
+        end
+        return y ^ (1 / d)
+    end,
+    oddspower = function(y, m)
+        local d = m.linkParameter or 0
+        if d == 0 then
+            return 1 / (1 + math.exp(-y))
+        end
+        return 1 / (1 + (1 + d * y) ^ (-1 / d))
+    end,
+    negbin = function(y, m)
+        return 1 / ((m.distParameter or 1) * (math.exp(-y) - 1))
+    end,
+}
+
+return regression
@@ -0,0 +1,162 @@
+package pmml2lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegressionModel(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/regression1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), `local regression = require("regression")`)
+
+	s := makeScript(string(code))
+	r, err := runScript(s, map[string]interface{}{"age": 30, "salary": 1000, "car_location": "street"})
+	assert.NoError(t, err)
+	assert.InDelta(t, 659.4, r.Value, 1e-9)
+	assert.InDelta(t, 659.4, r.Output("Claims"), 1e-9)
+
+	r, err = runScript(s, map[string]interface{}{"age": 30, "car_location": "street"})
+	assert.NoError(t, err)
+	assert.Nil(t, valueOf(r))
+}
+
+func TestRegressionModel_Normalization(t *testing.T) {
+	const y = 0.8
+	td := map[string]float64{
+		"none":    y,
+		"softmax": 1 / (1 + math.Exp(-y)),
+		"logit":   1 / (1 + math.Exp(-y)),
+		"probit":  0.5 * math.Erfc(-y/math.Sqrt2),
+		"cloglog": 1 - math.Exp(-math.Exp(y)),
+		"loglog":  math.Exp(-math.Exp(-y)),
+		"cauchit": 0.5 + math.Atan(y)/math.Pi,
+		"exp":     math.Exp(y),
+	}
+
+	for method, expect := range td {
+		t.Run(method, func(t *testing.T) {
+			code, err := Convert([]byte(fmt.Sprintf(`<PMML version="4.4">
+				<RegressionModel functionName="regression" normalizationMethod="%s">
+					<MiningSchema><MiningField name="x"/></MiningSchema>
+					<RegressionTable intercept="0.5"><NumericPredictor name="x" coefficient="1"/></RegressionTable>
+				</RegressionModel>
+			</PMML>`, method)))
+			assert.NoError(t, err)
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 0.3})
+			assert.NoError(t, err)
+			assert.InDelta(t, expect, r.Value, 1e-9)
+		})
+	}
+}
+
+func TestRegressionModel_Classification(t *testing.T) {
+	sigmoid := func(y float64) float64 { return 1 / (1 + math.Exp(-y)) }
+	softmax := math.Exp(2) + math.Exp(1) + 1
+	td := []struct {
+		method  string
+		optype  string
+		tables  string
+		expect  map[string]float64
+		predict string
+	}{
+		{
+			method: "softmax",
+			tables: threeTables,
+			expect: map[string]float64{
+				"yes":   math.Exp(2) / softmax,
+				"maybe": math.Exp(1) / softmax,
+				"no":    1 / softmax,
+			},
+			predict: "yes",
+		},
+		{
+			method:  "simplemax",
+			tables:  threeTables,
+			expect:  map[string]float64{"yes": 2.0 / 3, "maybe": 1.0 / 3, "no": 0},
+			predict: "yes",
+		},
+		{
+			method:  "logit",
+			tables:  twoTables,
+			expect:  map[string]float64{"yes": sigmoid(-0.5), "no": 1 - sigmoid(-0.5)},
+			predict: "no",
+		},
+		{
+			method:  "probit",
+			tables:  twoTables,
+			expect:  map[string]float64{"yes": 0.5 * math.Erfc(0.5/math.Sqrt2), "no": 1 - 0.5*math.Erfc(0.5/math.Sqrt2)},
+			predict: "no",
+		},
+		{
+			method:  "none",
+			tables:  twoTables,
+			expect:  map[string]float64{"yes": -0.5, "no": 1.5},
+			predict: "no",
+		},
+		{
+			method:  "logit",
+			optype:  "ordinal",
+			tables:  threeTables,
+			expect:  map[string]float64{"yes": sigmoid(2), "maybe": sigmoid(1) - sigmoid(2), "no": 1 - sigmoid(1)},
+			predict: "yes",
+		},
+	}
+
+	for _, tc := range td {
+		t.Run(tc.method+tc.optype, func(t *testing.T) {
+			optype := tc.optype
+			if optype == "" {
+				optype = "categorical"
+			}
+
+			code, err := Convert([]byte(fmt.Sprintf(`<PMML version="4.4">
+				<DataDictionary>
+					<DataField name="x" optype="continuous" dataType="double"/>
+					<DataField name="y" optype="%s" dataType="string"/>
+				</DataDictionary>
+				<RegressionModel functionName="classification" normalizationMethod="%s">
+					<MiningSchema><MiningField name="x"/><MiningField name="y" usageType="target"/></MiningSchema>
+					<Output><OutputField name="P" feature="probability" value="no"/></Output>
+					%s
+				</RegressionModel>
+			</PMML>`, optype, tc.method, tc.tables)))
+			assert.NoError(t, err)
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 0.5})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.predict, r.Value)
+			assert.Len(t, r.Probabilities, len(tc.expect))
+			for class, p := range tc.expect {
+				assert.InDelta(t, p, r.Probability(class), 1e-9, class)
+			}
+			assert.InDelta(t, tc.expect["no"], r.Output("P"), 1e-9)
+		})
+	}
+}
+
+func TestRegressionModel_Error(t *testing.T) {
+	_, err := Convert([]byte(`<PMML version="4.4">
+		<RegressionModel modelName="x" functionName="regression">
+			<MiningSchema><MiningField name="y" usageType="predicted"/></MiningSchema>
+		</RegressionModel>
+	</PMML>`))
+	assert.ErrorContains(t, err, "regression model x has no regression table")
+}
+
+const twoTables = `
+	<RegressionTable intercept="-1" targetCategory="yes"><NumericPredictor name="x" coefficient="1"/></RegressionTable>
+	<RegressionTable intercept="0" targetCategory="no"/>`
+
+const threeTables = `
+	<RegressionTable intercept="1" targetCategory="yes"><NumericPredictor name="x" coefficient="2"/></RegressionTable>
+	<RegressionTable intercept="0.5" targetCategory="maybe"><NumericPredictor name="x" coefficient="1"/></RegressionTable>
+	<RegressionTable intercept="0" targetCategory="no"/>`
@@ -0,0 +1,68 @@
+package pmml2lua
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kelindar/lua"
+)
+
+// Result represents the result of a model, as returned by the generated script.
+type Result struct {
+	Value         interface{}            `json:"value"`                   // The predicted value
+	DisplayValue  string                 `json:"displayValue,omitempty"`  // The display value of the prediction (e.g. cluster name)
+	EntityID      string                 `json:"entityId,omitempty"`      // The identifier of the winning entity (e.g. node)
+	Probabilities map[string]float64     `json:"probabilities,omitempty"` // The probability of each class
+	Confidences   map[string]float64     `json:"confidences,omitempty"`   // The confidence of each class
+	RecordCounts  map[string]float64     `json:"recordCounts,omitempty"`  // The number of training records of each class
+	Affinities    map[string]float64     `json:"affinities,omitempty"`    // The affinity of each entity (e.g. cluster)
+	ReasonCodes   []string               `json:"reasonCodes,omitempty"`   // The reason codes, ranked by their importance
+	Neighbors     []string               `json:"neighbors,omitempty"`     // The identifiers of the nearest neighbors, nearest first
+	Rules         []string               `json:"rules,omitempty"`         // The identifiers of the rules which fired, by their confidence
+	Forecasts     []float64              `json:"forecasts,omitempty"`     // The forecasts of each step until the horizon
+	Outlier       bool                   `json:"outlier,omitempty"`       // Whether the record is an anomaly
+	StandardError float64                `json:"standardError,omitempty"` // The standard deviation of the predicted value
+	Outputs       map[string]interface{} `json:"outputs,omitempty"`       // The output fields, by their name
+	Segments      []*Result              `json:"segments,omitempty"`      // The results of the segments, if all are selected
+}
+
+// ResultOf decodes the value returned by the main function of the generated script. If the
+// model did not give any prediction, a nil result is returned.
+func ResultOf(v lua.Value) (*Result, error) {
+	switch v := v.(type) {
+	case lua.Nil:
+		return nil, nil
+	case lua.String:
+		if v == "[]" { // An empty table is encoded as an empty array
+			return nil, nil
+		}
+
+		var result *Result
+		if err := json.Unmarshal([]byte(v), &result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unable to decode a result of type %T", v)
+	}
+}
+
+// Probability returns the probability of the class.
+func (r *Result) Probability(class string) float64 {
+	return r.Probabilities[class]
+}
+
+// Confidence returns the confidence of the class.
+func (r *Result) Confidence(class string) float64 {
+	return r.Confidences[class]
+}
+
+// Affinity returns the affinity of the entity.
+func (r *Result) Affinity(entity string) float64 {
+	return r.Affinities[entity]
+}
+
+// Output returns the value of the output field, or nil if the field has no value.
+func (r *Result) Output(name string) interface{} {
+	return r.Outputs[name]
+}
@@ -0,0 +1,62 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/kelindar/lua"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultOf(t *testing.T) {
+	r, err := ResultOf(lua.String(`{"value":"yes","entityId":"1","probabilities":{"yes":0.75,"no":0.25}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "yes", r.Value)
+	assert.Equal(t, "1", r.EntityID)
+	assert.Equal(t, 0.75, r.Probability("yes"))
+	assert.Equal(t, 0.0, r.Confidence("yes"))
+
+	r, err = ResultOf(lua.String(`{"value":"2","displayValue":"large","affinities":{"1":3.5,"2":1.25}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "large", r.DisplayValue)
+	assert.Equal(t, 1.25, r.Affinity("2"))
+
+	r, err = ResultOf(lua.Nil{})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	r, err = ResultOf(lua.String(`[]`))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	_, err = ResultOf(lua.Number(1))
+	assert.Error(t, err)
+}
+
+func TestResult_Golfing(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/tree1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{
+		"temperature": 65,
+		"humidity":    75,
+		"outlook":     "sunny",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+	assert.Equal(t, "will play", r.Value)
+	assert.NotEmpty(t, r.EntityID)
+	assert.NotEmpty(t, r.RecordCounts)
+	assert.InDelta(t, 1.0, sum(r.Probabilities), 1e-9)
+}
+
+// sum returns the sum of the values of the map
+func sum(m map[string]float64) (total float64) {
+	for _, v := range m {
+		total += v
+	}
+	return
+}
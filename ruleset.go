@@ -0,0 +1,117 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The criteria which can be used to select the rules of a rule set.
+var criteria = map[string]bool{
+	"firstHit":    true,
+	"weightedSum": true,
+	"weightedMax": true,
+}
+
+// RuleSetModel generates the LUA code for the element. The first rule selection method of the
+// rule set is the one which is applied.
+func (s *Scope) RuleSetModel(v schema.RuleSetModel, global *Scope) *Scope {
+	options := NewStatement().Append("{method=")
+	switch {
+	case len(v.RuleSet.Methods) == 0:
+		options.Error("rule set %s has no rule selection method", v.ModelName)
+	case !criteria[v.RuleSet.Methods[0].Criterion]:
+		options.Error("rule selection method %s is not supported", v.RuleSet.Methods[0].Criterion)
+	default:
+		options.String(v.RuleSet.Methods[0].Criterion)
+	}
+
+	// The score which is predicted when none of the rules fires
+	if v.RuleSet.DefaultScore != "" {
+		options.Append(", default=").Literal(schema.Value(v.RuleSet.DefaultScore), scoreType(v.FunctionName, v.MiningSchema.Target(), global))
+		options.Append(", defaultConfidence=%s", formatFloat(v.RuleSet.DefaultConfidence))
+	}
+
+	rules := NewScope()
+	for _, r := range v.RuleSet.Rules {
+		rules.Rule(r, v, global)
+	}
+
+	global.Require("ruleset")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or ruleset.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			rules,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// Rule generates the LUA code for a simple or a compound rule. The rules of a compound rule are
+// nested within it, since they are only considered if its predicate is true.
+func (s *Scope) Rule(v schema.Rule, model schema.RuleSetModel, global *Scope) *Scope {
+	switch {
+	case v.SimpleRule != nil:
+		return s.SimpleRule(*v.SimpleRule, model, global)
+	case v.CompoundRule != nil:
+		return s.CompoundRule(*v.CompoundRule, model, global)
+	default:
+		return s.With(NewStatement().Error("rule type is not supported"))
+	}
+}
+
+// SimpleRule generates the LUA code for the element.
+func (s *Scope) SimpleRule(v schema.SimpleRule, model schema.RuleSetModel, global *Scope) *Scope {
+	if v.Predicate == nil {
+		return s.With(NewStatement().Error("rule %s has no predicate", v.ID))
+	}
+
+	rule := NewStatement().Append("{")
+	if v.ID != "" {
+		rule.Append("id=").String(v.ID).Append(", ")
+	}
+	rule.Append("score=").Literal(schema.Value(v.Score), scoreType(model.FunctionName, model.MiningSchema.Target(), global))
+	rule.Append(", confidence=%s, weight=%s", formatFloat(v.Confidence), formatFloat(v.Weight))
+	if len(v.Distributions) > 0 {
+		rule.Append(", dist={")
+		for i, d := range v.Distributions {
+			rule.ScoreDistribution(d)
+			if i+1 < len(v.Distributions) {
+				rule.Append(", ")
+			}
+		}
+		rule.Append("}")
+	}
+
+	return s.With(
+		rule.Append(", test=function(v)"),
+		NewScope().With(
+			NewStatement().Return().Predicate(v.Predicate, global),
+		),
+		Append("end},"),
+	)
+}
+
+// CompoundRule generates the LUA code for the element.
+func (s *Scope) CompoundRule(v schema.CompoundRule, model schema.RuleSetModel, global *Scope) *Scope {
+	if v.Predicate == nil {
+		return s.With(NewStatement().Error("compound rule has no predicate"))
+	}
+
+	rules := NewScope()
+	for _, r := range v.Rules {
+		rules.Rule(r, model, global)
+	}
+
+	return s.With(
+		Append("{test=function(v)"),
+		NewScope().With(
+			NewStatement().Return().Predicate(v.Predicate, global),
+		),
+		Append("end, rules={"),
+		rules,
+		Append("}},"),
+	)
+}
@@ -0,0 +1,119 @@
+local ruleset = {}
+
+-- NewModel creates a rule set with its options and its rules. A simple rule contains its id, its
+-- score, its confidence, its weight and the function which tests its predicate, while a compound
+-- rule contains the function which tests its predicate and its nested rules.
+function ruleset.NewModel(options, rules)
+    local m = options
+    m.rules = rules
+
+    -- Function which selects the rules and returns the result table
+    m.eval = function(v)
+        return ruleset.Select(m, v)
+    end
+    return m
+end
+
+-- Select returns the result of the rule set according to its rule selection method, or the
+-- default score if none of the rules fires.
+function ruleset.Select(m, v)
+    local fired = ruleset.Fire(m.rules, v, {}, m.method == 'firstHit')
+    if #fired == 0 then
+        return ruleset.Default(m)
+    end
+
+    if m.method == 'weightedSum' then
+        return ruleset.WeightedSum(fired)
+    end
+
+    -- The first rule which fires wins, unless another one has a higher weight
+    local best = fired[1]
+    if m.method == 'weightedMax' then
+        for i=2, #fired do
+            if fired[i].weight > best.weight then
+                best = fired[i]
+            end
+        end
+    end
+    return ruleset.Result(best)
+end
+
+-- Fire appends the simple rules whose predicates are true to the fired rules, in the order of
+-- the document. The nested rules of a compound rule are only tested if its predicate is true and,
+-- if only the first hit is needed, the rules are no longer tested once one of them fires.
+function ruleset.Fire(rules, v, fired, first)
+    for i=1, #rules do
+        local r = rules[i]
+        if r.test(v) == true then
+            if r.rules ~= nil then
+                ruleset.Fire(r.rules, v, fired, first)
+            else
+                table.insert(fired, r)
+            end
+
+            if first and #fired > 0 then
+                return fired
+            end
+        end
+    end
+    return fired
+end
+
+-- Result creates the result table out of a simple rule.
+function ruleset.Result(rule)
+    local r = {value = rule.score, entityId = rule.id, confidences = {}}
+    r.confidences[tostring(rule.score)] = rule.confidence
+    if rule.dist ~= nil and #rule.dist > 0 then
+        r.recordCounts = {}
+        for i=1, #rule.dist do
+            local d = rule.dist[i]
+            r.recordCounts[d.value] = d.count
+            if d.probability ~= nil then
+                r.probabilities = r.probabilities or {}
+                r.probabilities[d.value] = d.probability
+            end
+        end
+    end
+    return r
+end
+
+-- Default returns the result of the rule set when none of the rules fires, or nil if the rule
+-- set has no default score.
+function ruleset.Default(m)
+    if m.default == nil then
+        return nil
+    end
+
+    local r = {value = m.default, confidences = {}}
+    r.confidences[tostring(m.default)] = m.defaultConfidence or 0
+    return r
+end
+
+-- WeightedSum returns the score with the highest sum of the weights of the fired rules. The
+-- confidence of each score is the sum of its weights divided by the number of fired rules.
+function ruleset.WeightedSum(fired)
+    local sums, scores = {}, {}
+    for i=1, #fired do
+        local score = fired[i].score
+        if sums[score] == nil then
+            sums[score] = 0
+            table.insert(scores, score)
+        end
+        sums[score] = sums[score] + fired[i].weight
+    end
+
+    local r = {confidences = {}}
+    local best = nil
+    for i=1, #scores do
+        local score = scores[i]
+        r.confidences[tostring(score)] = sums[score] / #fired
+        if best == nil or sums[score] > sums[best] then
+            best = score
+        end
+    end
+
+    r.value = best
+    return r
+end
+
+return ruleset
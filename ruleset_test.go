@@ -0,0 +1,147 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/kelindar/pmml2lua/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSetModel(t *testing.T) {
+	type expect struct {
+		value      string
+		rule       string
+		confidence float64
+	}
+
+	td := []struct {
+		input  map[string]interface{}
+		expect map[string]expect
+	}{
+		{
+			input: map[string]interface{}{"BP": "HIGH", "K": 0.05, "Age": 40, "Na": 0.4},
+			expect: map[string]expect{
+				"firstHit":    {value: "drugB", rule: "RULE1", confidence: 0.9},
+				"weightedMax": {value: "drugB", rule: "RULE1", confidence: 0.9},
+				"weightedSum": {value: "drugB", confidence: 1.3 / 2},
+			},
+		},
+		{
+			input: map[string]interface{}{"BP": "LOW", "K": 0.05, "Age": 70, "Na": 0.4},
+			expect: map[string]expect{
+				"firstHit":    {value: "drugB", rule: "RULE4", confidence: 0.4},
+				"weightedMax": {value: "drugC", rule: "RULE5", confidence: 0.8},
+				"weightedSum": {value: "drugC", confidence: 0.8 / 2},
+			},
+		},
+		{
+			input: map[string]interface{}{"BP": "LOW", "K": 0.01, "Age": 30, "Na": 0.9},
+			expect: map[string]expect{
+				"firstHit":    {value: "drugA", rule: "RULE2", confidence: 0.6},
+				"weightedMax": {value: "drugA", rule: "RULE2", confidence: 0.6},
+				"weightedSum": {value: "drugA", confidence: 0.6},
+			},
+		},
+		{
+			input: map[string]interface{}{"BP": "NORMAL", "K": 0.01, "Age": 30, "Na": 0.9},
+			expect: map[string]expect{
+				"firstHit":    {value: "drugY", confidence: 0.1},
+				"weightedMax": {value: "drugY", confidence: 0.1},
+				"weightedSum": {value: "drugY", confidence: 0.1},
+			},
+		},
+	}
+
+	b, err := ioutil.ReadFile("fixtures/ruleset1.xml")
+	assert.NoError(t, err)
+
+	for method := range td[0].expect {
+		t.Run(method, func(t *testing.T) {
+			doc := strings.Replace(string(b), `criterion="firstHit"`, `criterion="`+method+`"`, 1)
+			code, err := Convert([]byte(doc))
+			assert.NoError(t, err)
+			assert.Contains(t, string(code), "ruleset.NewModel({method='"+method+"', default='drugY', defaultConfidence=0.1}")
+
+			s := makeScript(string(code))
+			for _, tt := range td {
+				r, err := runScript(s, tt.input)
+				assert.NoError(t, err)
+
+				expect := tt.expect[method]
+				assert.Equal(t, expect.value, r.Value, tt.input)
+				assert.Equal(t, expect.rule, r.EntityID, tt.input)
+				assert.InDelta(t, expect.confidence, r.Confidence(expect.value), 1e-9, tt.input)
+				assert.Equal(t, expect.value, r.Output("Drug"), tt.input)
+				assert.InDelta(t, expect.confidence, r.Output("Confidence"), 1e-9, tt.input)
+			}
+		})
+	}
+}
+
+func TestRuleSetModel_Distribution(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/ruleset1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"BP": "HIGH", "K": 0.05, "Age": 40})
+	assert.NoError(t, err)
+	assert.Equal(t, "RULE1", r.Output("Rule"))
+	assert.Equal(t, 76.0, r.RecordCounts["drugB"])
+	assert.Nil(t, r.Probabilities)
+}
+
+func TestRuleSetModel_NoDefault(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<RuleSetModel modelName="rules" functionName="regression">
+			<MiningSchema><MiningField name="x"/><MiningField name="y" usageType="predicted"/></MiningSchema>
+			<RuleSet>
+				<RuleSelectionMethod criterion="weightedSum"/>
+				<SimpleRule score="10"><SimplePredicate field="x" operator="greaterThan" value="0"/></SimpleRule>
+				<SimpleRule score="20" weight="2"><SimplePredicate field="x" operator="greaterThan" value="5"/></SimpleRule>
+			</RuleSet>
+		</RuleSetModel>
+	</PMML>`))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "{score=10, confidence=1, weight=1, test=function(v)")
+
+	s := makeScript(string(code))
+	r, err := runScript(s, map[string]interface{}{"x": 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, r.Value)
+	assert.InDelta(t, 1.0, r.Confidence("20"), 1e-9)
+	assert.InDelta(t, 0.5, r.Confidence("10"), 1e-9)
+
+	r, err = runScript(s, map[string]interface{}{"x": -1})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestRuleSetModel_Error(t *testing.T) {
+	tests := []struct {
+		ruleset string
+		err     string
+	}{
+		{ruleset: `<SimpleRule id="1" score="a"><True/></SimpleRule>`, err: "rule set x has no rule selection method"},
+		{ruleset: `<RuleSelectionMethod criterion="lastHit"/>`, err: "rule selection method lastHit is not supported"},
+		{ruleset: `<RuleSelectionMethod criterion="firstHit"/><SimpleRule id="1" score="a"/>`, err: "rule 1 has no predicate"},
+		{ruleset: `<RuleSelectionMethod criterion="firstHit"/><CompoundRule><SimpleRule id="1" score="a"><True/></SimpleRule></CompoundRule>`, err: "compound rule has no predicate"},
+	}
+
+	for _, tc := range tests {
+		_, err := Convert([]byte(`<PMML version="4.4">
+			<RuleSetModel modelName="x" functionName="classification"><MiningSchema/><RuleSet>` + tc.ruleset + `</RuleSet></RuleSetModel>
+		</PMML>`))
+		assert.ErrorContains(t, err, tc.err)
+	}
+
+	// The rules are either simple or compound
+	_, err := NewScope().RuleSetModel(schema.RuleSetModel{RuleSet: schema.RuleSet{
+		Methods: []schema.RuleSelectionMethod{{Criterion: "firstHit"}},
+		Rules:   []schema.Rule{{}},
+	}}, NewScope()).Compile()
+	assert.ErrorContains(t, err, "rule type is not supported")
+}
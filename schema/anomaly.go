@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// AnomalyDetectionModel ...
+type AnomalyDetectionModel struct {
+	ModelName            string
+	FunctionName         string
+	AlgorithmName        string
+	AlgorithmType        string
+	SampleDataSize       int64
+	MiningSchema         MiningSchema
+	Output               *Output
+	LocalTransformations *LocalTransformations
+	Model                Model
+}
+
+// UnmarshalXML ...
+func (m *AnomalyDetectionModel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "modelName":
+			m.ModelName = attr.Value
+		case "functionName":
+			m.FunctionName = attr.Value
+		case "algorithmName":
+			m.AlgorithmName = attr.Value
+		case "algorithmType":
+			m.AlgorithmType = attr.Value
+		case "sampleDataSize":
+			if m.SampleDataSize, err = strconv.ParseInt(attr.Value, 10, 64); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		switch el.Name.Local {
+		case "MiningSchema":
+			return d.DecodeElement(&m.MiningSchema, &el)
+		case "Output":
+			m.Output = new(Output)
+			return d.DecodeElement(m.Output, &el)
+		case "LocalTransformations":
+			m.LocalTransformations = new(LocalTransformations)
+			return d.DecodeElement(m.LocalTransformations, &el)
+		case "ParameterList", "ModelStats", "ModelExplanation",
+			"ModelVerification", "MeanClusterDistances":
+			return d.Skip()
+		default:
+			return m.Model.UnmarshalXML(d, el)
+		}
+	})
+}
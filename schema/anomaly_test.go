@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomalyDetectionModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/anomaly1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].AnomalyDetectionModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "forest", out.Models[0].Name())
+	assert.Equal(t, "iforest", model.AlgorithmType)
+	assert.Equal(t, int64(8), model.SampleDataSize)
+	assert.Len(t, model.MiningSchema.MiningFields, 2)
+	assert.Len(t, model.Output.OutputFields, 1)
+
+	forest := model.Model.MiningModel
+	assert.NotNil(t, forest)
+	assert.Len(t, forest.Segmentation.Segments, 2)
+	assert.Equal(t, int64(6), forest.Segmentation.Segments[0].Model.TreeModel.Node.Nodes[0].RecordCount)
+
+	named := out.Models[0].Named("isolation")
+	assert.Equal(t, "isolation", named.Name())
+	assert.Equal(t, "forest", out.Models[0].Name())
+}
+
+func TestAnomalyDetectionModel_Version(t *testing.T) {
+	var out PMML
+	assert.Error(t, xml.Unmarshal([]byte(`<PMML version="4.3">
+		<AnomalyDetectionModel algorithmType="iforest" sampleDataSize="8"/>
+	</PMML>`), &out))
+
+	assert.Error(t, xml.Unmarshal([]byte(`<PMML version="4.4">
+		<AnomalyDetectionModel algorithmType="iforest" sampleDataSize="many"/>
+	</PMML>`), &out))
+}
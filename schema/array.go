@@ -21,7 +21,7 @@ type Array struct {
 
 // Ints converts the values to the integer slice.
 func (a Array) Ints() ([]int, error) {
-	arr := strings.Split(string(a.Values), " ")
+	arr := strings.Fields(a.Values)
 	out := make([]int, 0, len(arr))
 	for _, s := range arr {
 		v, err := strconv.ParseInt(s, 10, 64)
@@ -35,7 +35,7 @@ func (a Array) Ints() ([]int, error) {
 
 // Floats converts the values to the float64 slice.
 func (a Array) Floats() ([]float64, error) {
-	arr := strings.Split(string(a.Values), " ")
+	arr := strings.Fields(a.Values)
 	out := make([]float64, 0, len(arr))
 	for _, s := range arr {
 		v, err := strconv.ParseFloat(s, 64)
@@ -69,6 +69,16 @@ func (a Array) Strings() ([]string, error) {
 	return v, nil
 }
 
+// Tokens returns the values of the array as they appear in the document, without the quotes
+// of the string values.
+func (a Array) Tokens() ([]string, error) {
+	if a.Type == "string" {
+		return a.Strings()
+	}
+
+	return strings.Fields(a.Values), nil
+}
+
 // CSV returns the comma separated values of the array
 func (a Array) CSV() (string, error) {
 	var arr interface{}
@@ -96,3 +106,48 @@ func (a Array) CSV() (string, error) {
 
 	return regexp.MustCompile(`^\[(.*)\]$`).ReplaceAllString(string(b), `$1`), nil
 }
+
+// SparseArray ...
+type SparseArray struct {
+	Length       int     `xml:"n,attr,omitempty"`
+	DefaultValue float64 `xml:"defaultValue,attr,omitempty"`
+	Indices      string  `xml:"Indices"`
+	Entries      string  `xml:"REAL-Entries"`
+}
+
+// Floats converts the sparse array to the dense float64 slice of its length, or of the specified
+// size if the array does not declare its length. The indices start at 1 and the values which are
+// not listed are the default value.
+func (a SparseArray) Floats(size int) ([]float64, error) {
+	indices, entries := strings.Fields(a.Indices), strings.Fields(a.Entries)
+	if len(indices) != len(entries) {
+		return nil, fmt.Errorf("sparse array has %d indices but %d entries", len(indices), len(entries))
+	}
+
+	length := a.Length
+	if length == 0 {
+		length = size
+	}
+
+	out := make([]float64, length)
+	for i := range out {
+		out[i] = a.DefaultValue
+	}
+
+	for i, s := range indices {
+		idx, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if idx < 1 || idx > length {
+			return nil, fmt.Errorf("sparse array index %d is out of range", idx)
+		}
+
+		v, err := strconv.ParseFloat(entries[i], 64)
+		if err != nil {
+			return nil, err
+		}
+		out[idx-1] = v
+	}
+	return out, nil
+}
@@ -72,3 +72,23 @@ func TestArray_Strings(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, `"ab","a b","with \"quotes\" "`, string(j))
 }
+
+func TestArray_Tokens(t *testing.T) {
+	td := []struct {
+		xml    string
+		expect []string
+	}{
+		{xml: `<Array n="3" type="int">1  -22 3</Array>`, expect: []string{"1", "-22", "3"}},
+		{xml: `<Array n="2" type="real">1.5 -2e3</Array>`, expect: []string{"1.5", "-2e3"}},
+		{xml: `<Array n="2" type="string">1 "a b"</Array>`, expect: []string{"1", "a b"}},
+	}
+
+	for _, tt := range td {
+		var out Array
+		assert.NoError(t, xml.Unmarshal([]byte(tt.xml), &out))
+
+		v, err := out.Tokens()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expect, v)
+	}
+}
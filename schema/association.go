@@ -0,0 +1,62 @@
+package schema
+
+// AssociationModel ...
+type AssociationModel struct {
+	ModelName             string                `xml:"modelName,attr,omitempty"`
+	FunctionName          string                `xml:"functionName,attr"`
+	AlgorithmName         string                `xml:"algorithmName,attr,omitempty"`
+	NumberOfTransactions  int                   `xml:"numberOfTransactions,attr"`
+	MaxNumberOfItemsPerTA int                   `xml:"maxNumberOfItemsPerTA,attr,omitempty"`
+	AvgNumberOfItemsPerTA float64               `xml:"avgNumberOfItemsPerTA,attr,omitempty"`
+	MinimumSupport        float64               `xml:"minimumSupport,attr"`
+	MinimumConfidence     float64               `xml:"minimumConfidence,attr"`
+	LengthLimit           int                   `xml:"lengthLimit,attr,omitempty"`
+	NumberOfItems         int                   `xml:"numberOfItems,attr"`
+	NumberOfItemsets      int                   `xml:"numberOfItemsets,attr"`
+	NumberOfRules         int                   `xml:"numberOfRules,attr"`
+	Extension             []Extension           `xml:"Extension"`
+	MiningSchema          MiningSchema          `xml:"MiningSchema"`
+	Output                *Output               `xml:"Output"`
+	LocalTransformations  *LocalTransformations `xml:"LocalTransformations"`
+	Items                 []Item                `xml:"Item"`
+	Itemsets              []Itemset             `xml:"Itemset"`
+	AssociationRules      []AssociationRule     `xml:"AssociationRule"`
+}
+
+// Item ...
+type Item struct {
+	ID          string      `xml:"id,attr"`
+	Value       string      `xml:"value,attr"`
+	Field       string      `xml:"field,attr,omitempty"`
+	Category    string      `xml:"category,attr,omitempty"`
+	MappedValue string      `xml:"mappedValue,attr,omitempty"`
+	Weight      float64     `xml:"weight,attr,omitempty"`
+	Extension   []Extension `xml:"Extension"`
+}
+
+// Itemset ...
+type Itemset struct {
+	ID            string      `xml:"id,attr"`
+	Support       float64     `xml:"support,attr,omitempty"`
+	NumberOfItems int         `xml:"numberOfItems,attr,omitempty"`
+	Extension     []Extension `xml:"Extension"`
+	ItemRefs      []ItemRef   `xml:"ItemRef"`
+}
+
+// ItemRef ...
+type ItemRef struct {
+	ItemRef string `xml:"itemRef,attr"`
+}
+
+// AssociationRule ...
+type AssociationRule struct {
+	ID         string      `xml:"id,attr,omitempty"`
+	Antecedent string      `xml:"antecedent,attr"`
+	Consequent string      `xml:"consequent,attr"`
+	Support    float64     `xml:"support,attr"`
+	Confidence float64     `xml:"confidence,attr"`
+	Lift       *float64    `xml:"lift,attr,omitempty"`
+	Leverage   *float64    `xml:"leverage,attr,omitempty"`
+	Affinity   *float64    `xml:"affinity,attr,omitempty"`
+	Extension  []Extension `xml:"Extension"`
+}
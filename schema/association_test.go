@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssociationModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/association1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].AssociationModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "groceries", out.Models[0].Name())
+	assert.Equal(t, 10, model.NumberOfTransactions)
+	assert.Equal(t, 0.1, model.MinimumSupport)
+	assert.Equal(t, 0.2, model.MinimumConfidence)
+
+	assert.Len(t, model.Items, 5)
+	assert.Equal(t, Item{ID: "3", Value: "Water"}, model.Items[2])
+	assert.Len(t, model.Itemsets, 7)
+	assert.Equal(t, []ItemRef{{ItemRef: "1"}, {ItemRef: "3"}}, model.Itemsets[3].ItemRefs)
+
+	assert.Len(t, model.AssociationRules, 5)
+	rule := model.AssociationRules[0]
+	assert.Equal(t, "1", rule.ID)
+	assert.Equal(t, 1.0, rule.Confidence)
+	assert.Equal(t, 1.25, *rule.Lift)
+	assert.Equal(t, 0.08, *rule.Leverage)
+	assert.Nil(t, rule.Affinity)
+	assert.Empty(t, model.AssociationRules[4].ID)
+
+	field := model.Output.OutputFields[5]
+	assert.Equal(t, "ruleId", field.RuleFeature)
+	assert.Equal(t, "lift", field.RankBasis)
+	assert.Equal(t, 3, field.Rank)
+	assert.True(t, field.IsMultiValued)
+
+	named := out.Models[0].Named("basket")
+	assert.Equal(t, "basket", named.Name())
+}
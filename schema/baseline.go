@@ -0,0 +1,46 @@
+package schema
+
+// BaselineModel ...
+type BaselineModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	TestDistributions    TestDistributions     `xml:"TestDistributions"`
+}
+
+// TestDistributions ...
+type TestDistributions struct {
+	Field               string                  `xml:"field,attr"`
+	TestStatistic       string                  `xml:"testStatistic,attr"`
+	ResetValue          float64                 `xml:"resetValue,attr,omitempty"`
+	WindowSize          int                     `xml:"windowSize,attr,omitempty"`
+	WeightField         string                  `xml:"weightField,attr,omitempty"`
+	NormalizationScheme string                  `xml:"normalizationScheme,attr,omitempty"`
+	Extension           []Extension             `xml:"Extension"`
+	Baseline            ContinuousDistribution  `xml:"Baseline"`
+	Alternate           *ContinuousDistribution `xml:"Alternate"`
+}
+
+// ContinuousDistribution ...
+type ContinuousDistribution struct {
+	Gaussian *GaussianDistribution `xml:"GaussianDistribution"`
+	Poisson  *PoissonDistribution  `xml:"PoissonDistribution"`
+	Uniform  *UniformDistribution  `xml:"UniformDistribution"`
+	Any      *AnyDistribution      `xml:"AnyDistribution"`
+}
+
+// UniformDistribution ...
+type UniformDistribution struct {
+	Lower float64 `xml:"lower,attr"`
+	Upper float64 `xml:"upper,attr"`
+}
+
+// AnyDistribution ...
+type AnyDistribution struct {
+	Mean     float64 `xml:"mean,attr"`
+	Variance float64 `xml:"variance,attr"`
+}
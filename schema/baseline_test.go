@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/baseline2.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].BaselineModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "cusum", out.Models[0].Name())
+	assert.Equal(t, "errors", model.TestDistributions.Field)
+	assert.Equal(t, "CUSUM", model.TestDistributions.TestStatistic)
+	assert.Equal(t, 2.0, model.TestDistributions.Baseline.Poisson.Mean)
+	assert.Equal(t, 5.0, model.TestDistributions.Alternate.Poisson.Mean)
+	assert.Nil(t, model.TestDistributions.Baseline.Gaussian)
+
+	named := out.Models[0].Named("drift")
+	assert.Equal(t, "drift", named.Name())
+	assert.Equal(t, "cusum", out.Models[0].Name())
+}
+
+func TestContinuousDistribution(t *testing.T) {
+	var out TestDistributions
+	assert.NoError(t, xml.Unmarshal([]byte(`<TestDistributions field="x" testStatistic="zValue">
+		<Baseline><UniformDistribution lower="1" upper="3"/></Baseline>
+		<Alternate><AnyDistribution mean="2" variance="0.5"/></Alternate>
+	</TestDistributions>`), &out))
+
+	assert.Equal(t, &UniformDistribution{Lower: 1, Upper: 3}, out.Baseline.Uniform)
+	assert.Equal(t, &AnyDistribution{Mean: 2, Variance: 0.5}, out.Alternate.Any)
+}
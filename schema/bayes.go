@@ -0,0 +1,63 @@
+package schema
+
+// NaiveBayesModel ...
+type NaiveBayesModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	Threshold            float64               `xml:"threshold,attr"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	Inputs               []BayesInput          `xml:"BayesInputs>BayesInput"`
+	BayesOutput          BayesOutput           `xml:"BayesOutput"`
+}
+
+// BayesInput ...
+type BayesInput struct {
+	FieldName        string            `xml:"fieldName,attr"`
+	Extension        []Extension       `xml:"Extension"`
+	DerivedField     *DerivedField     `xml:"DerivedField"`
+	PairCounts       []PairCounts      `xml:"PairCounts"`
+	TargetValueStats []TargetValueStat `xml:"TargetValueStats>TargetValueStat"`
+}
+
+// PairCounts ...
+type PairCounts struct {
+	Value             Value              `xml:"value,attr"`
+	Extension         []Extension        `xml:"Extension"`
+	TargetValueCounts []TargetValueCount `xml:"TargetValueCounts>TargetValueCount"`
+}
+
+// TargetValueCount ...
+type TargetValueCount struct {
+	Value Value   `xml:"value,attr"`
+	Count float64 `xml:"count,attr"`
+}
+
+// TargetValueStat ...
+type TargetValueStat struct {
+	Value     Value                 `xml:"value,attr"`
+	Extension []Extension           `xml:"Extension"`
+	Gaussian  *GaussianDistribution `xml:"GaussianDistribution"`
+	Poisson   *PoissonDistribution  `xml:"PoissonDistribution"`
+}
+
+// GaussianDistribution ...
+type GaussianDistribution struct {
+	Mean     float64 `xml:"mean,attr"`
+	Variance float64 `xml:"variance,attr"`
+}
+
+// PoissonDistribution ...
+type PoissonDistribution struct {
+	Mean float64 `xml:"mean,attr"`
+}
+
+// BayesOutput ...
+type BayesOutput struct {
+	FieldName         string             `xml:"fieldName,attr"`
+	Extension         []Extension        `xml:"Extension"`
+	TargetValueCounts []TargetValueCount `xml:"TargetValueCounts>TargetValueCount"`
+}
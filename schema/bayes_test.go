@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaiveBayesModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/bayes1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].NaiveBayesModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "risk", out.Models[0].Name())
+	assert.Equal(t, 0.001, model.Threshold)
+	assert.Len(t, model.Inputs, 4)
+
+	gender := model.Inputs[0]
+	assert.Nil(t, gender.DerivedField)
+	assert.Equal(t, Value("female"), gender.PairCounts[1].Value)
+	assert.Equal(t, TargetValueCount{Value: "high", Count: 10}, gender.PairCounts[1].TargetValueCounts[1])
+
+	age := model.Inputs[1]
+	assert.Len(t, age.DerivedField.Expression.Discretize.Bins, 2)
+
+	income := model.Inputs[2]
+	assert.Equal(t, &GaussianDistribution{Mean: 3000, Variance: 4e6}, income.TargetValueStats[1].Gaussian)
+	assert.Equal(t, &PoissonDistribution{Mean: 2}, model.Inputs[3].TargetValueStats[1].Poisson)
+
+	assert.Equal(t, "risk", model.BayesOutput.FieldName)
+	assert.Len(t, model.BayesOutput.TargetValueCounts, 2)
+
+	named := out.Models[0].Named("bayes")
+	assert.Equal(t, "bayes", named.Name())
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// ClusteringModel ...
+type ClusteringModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	ModelClass           string                `xml:"modelClass,attr"`
+	NumberOfClusters     int                   `xml:"numberOfClusters,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	ComparisonMeasure    ComparisonMeasure     `xml:"ComparisonMeasure"`
+	Fields               []ClusteringField     `xml:"ClusteringField"`
+	MissingValueWeights  *Array                `xml:"MissingValueWeights>Array"`
+	Clusters             []Cluster             `xml:"Cluster"`
+}
+
+// ComparisonMeasure ...
+type ComparisonMeasure struct {
+	Kind            string
+	CompareFunction string
+	Measure         Measure
+}
+
+// UnmarshalXML ...
+func (c *ComparisonMeasure) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	c.CompareFunction = "absDiff"
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "kind":
+			c.Kind = attr.Value
+		case "compareFunction":
+			c.CompareFunction = attr.Value
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		if err := d.DecodeElement(&c.Measure, &el); err != nil {
+			return err
+		}
+		c.Measure.Name = el.Name.Local
+		return nil
+	})
+}
+
+// Measure represents one of the distance or similarity measures of a comparison measure, along
+// with the parameters of the minkowski and binary similarity measures.
+type Measure struct {
+	Name string  `xml:"-"`
+	P    float64 `xml:"p-parameter,attr,omitempty"`
+	C00  float64 `xml:"c00-parameter,attr,omitempty"`
+	C01  float64 `xml:"c01-parameter,attr,omitempty"`
+	C10  float64 `xml:"c10-parameter,attr,omitempty"`
+	C11  float64 `xml:"c11-parameter,attr,omitempty"`
+	D00  float64 `xml:"d00-parameter,attr,omitempty"`
+	D01  float64 `xml:"d01-parameter,attr,omitempty"`
+	D10  float64 `xml:"d10-parameter,attr,omitempty"`
+	D11  float64 `xml:"d11-parameter,attr,omitempty"`
+}
+
+// ClusteringField ...
+type ClusteringField struct {
+	Field           string      `xml:"field,attr"`
+	IsCenterField   bool        `xml:"isCenterField,attr"`
+	FieldWeight     float64     `xml:"fieldWeight,attr"`
+	CompareFunction string      `xml:"compareFunction,attr,omitempty"`
+	SimilarityScale *float64    `xml:"similarityScale,attr,omitempty"`
+	Extension       []Extension `xml:"Extension"`
+}
+
+// UnmarshalXML ...
+func (f *ClusteringField) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias ClusteringField
+	v := alias{IsCenterField: true, FieldWeight: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*f = ClusteringField(v)
+	return nil
+}
+
+// Cluster ...
+type Cluster struct {
+	ID        string      `xml:"id,attr,omitempty"`
+	Name      string      `xml:"name,attr,omitempty"`
+	Size      int         `xml:"size,attr,omitempty"`
+	Extension []Extension `xml:"Extension"`
+	Array     *Array      `xml:"Array"`
+}
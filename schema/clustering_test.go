@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusteringModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/clustering1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].ClusteringModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "people", out.Models[0].Name())
+	assert.Equal(t, "centerBased", model.ModelClass)
+	assert.Equal(t, ComparisonMeasure{Kind: "distance", CompareFunction: "absDiff", Measure: Measure{Name: "euclidean"}}, model.ComparisonMeasure)
+
+	assert.Len(t, model.Fields, 3)
+	assert.Equal(t, ClusteringField{Field: "height", IsCenterField: true, FieldWeight: 2}, model.Fields[0])
+	assert.Equal(t, ClusteringField{Field: "age", IsCenterField: false, FieldWeight: 1}, model.Fields[2])
+	assert.Equal(t, "2 1", model.MissingValueWeights.Values)
+
+	assert.Len(t, model.Clusters, 3)
+	assert.Equal(t, "Small people", model.Clusters[0].Name)
+	assert.Equal(t, 25, model.Clusters[2].Size)
+	assert.Equal(t, "1.9 95", model.Clusters[2].Array.Values)
+
+	named := out.Models[0].Named("clusters")
+	assert.Equal(t, "clusters", named.Name())
+}
+
+func TestComparisonMeasure(t *testing.T) {
+	var out ComparisonMeasure
+	assert.NoError(t, xml.Unmarshal([]byte(`<ComparisonMeasure kind="similarity" compareFunction="equal">
+		<binarySimilarity c00-parameter="1" c01-parameter="2" c10-parameter="3" c11-parameter="4" d00-parameter="5" d01-parameter="6" d10-parameter="7" d11-parameter="8"/>
+	</ComparisonMeasure>`), &out))
+	assert.Equal(t, ComparisonMeasure{Kind: "similarity", CompareFunction: "equal", Measure: Measure{
+		Name: "binarySimilarity", C00: 1, C01: 2, C10: 3, C11: 4, D00: 5, D01: 6, D10: 7, D11: 8,
+	}}, out)
+
+	assert.NoError(t, xml.Unmarshal([]byte(`<ComparisonMeasure kind="distance"><minkowski p-parameter="3"/></ComparisonMeasure>`), &out))
+	assert.Equal(t, 3.0, out.Measure.P)
+}
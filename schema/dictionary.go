@@ -0,0 +1,33 @@
+package schema
+
+// DataDictionary ...
+type DataDictionary struct {
+	NumberOfFields int         `xml:"numberOfFields,attr,omitempty"`
+	Extension      []Extension `xml:"Extension"`
+	DataFields     []DataField `xml:"DataField"`
+}
+
+// DataField ...
+type DataField struct {
+	Name        string       `xml:"name,attr"`
+	DisplayName string       `xml:"displayName,attr,omitempty"`
+	Optype      string       `xml:"optype,attr"`
+	DataType    string       `xml:"dataType,attr"`
+	Extension   []Extension  `xml:"Extension"`
+	Intervals   []Interval   `xml:"Interval"`
+	Values      []FieldValue `xml:"Value"`
+}
+
+// FieldValue ...
+type FieldValue struct {
+	Value        Value  `xml:"value,attr"`
+	DisplayValue string `xml:"displayValue,attr,omitempty"`
+	Property     string `xml:"property,attr,omitempty"`
+}
+
+// Interval ...
+type Interval struct {
+	Closure     string   `xml:"closure,attr"`
+	LeftMargin  *float64 `xml:"leftMargin,attr,omitempty"`
+	RightMargin *float64 `xml:"rightMargin,attr,omitempty"`
+}
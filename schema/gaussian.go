@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// GaussianProcessModel ...
+type GaussianProcessModel struct {
+	ModelName                    string                        `xml:"modelName,attr,omitempty"`
+	FunctionName                 string                        `xml:"functionName,attr"`
+	AlgorithmName                string                        `xml:"algorithmName,attr,omitempty"`
+	Optimizer                    string                        `xml:"optimizer,attr,omitempty"`
+	Extension                    []Extension                   `xml:"Extension"`
+	MiningSchema                 MiningSchema                  `xml:"MiningSchema"`
+	Output                       *Output                       `xml:"Output"`
+	LocalTransformations         *LocalTransformations         `xml:"LocalTransformations"`
+	RadialBasisKernel            *RadialBasisKernel            `xml:"RadialBasisKernel"`
+	ARDSquaredExponentialKernel  *ExponentialKernel            `xml:"ARDSquaredExponentialKernel"`
+	AbsoluteExponentialKernel    *ExponentialKernel            `xml:"AbsoluteExponentialKernel"`
+	GeneralizedExponentialKernel *GeneralizedExponentialKernel `xml:"GeneralizedExponentialKernel"`
+	TrainingInstances            TrainingInstances             `xml:"TrainingInstances"`
+}
+
+// RadialBasisKernel ...
+type RadialBasisKernel struct {
+	Description   string  `xml:"description,attr,omitempty"`
+	Gamma         float64 `xml:"gamma,attr"`
+	NoiseVariance float64 `xml:"noiseVariance,attr"`
+	Lambda        float64 `xml:"lambda,attr"`
+}
+
+// UnmarshalXML ...
+func (k *RadialBasisKernel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias RadialBasisKernel
+	v := alias{Gamma: 1, NoiseVariance: 1, Lambda: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = RadialBasisKernel(v)
+	return nil
+}
+
+// ExponentialKernel represents either an ARD squared exponential or an absolute exponential
+// kernel, which have a length scale per input.
+type ExponentialKernel struct {
+	Description   string  `xml:"description,attr,omitempty"`
+	Gamma         float64 `xml:"gamma,attr"`
+	NoiseVariance float64 `xml:"noiseVariance,attr"`
+	Lambda        Array   `xml:"Lambda>Array"`
+}
+
+// UnmarshalXML ...
+func (k *ExponentialKernel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias ExponentialKernel
+	v := alias{Gamma: 1, NoiseVariance: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = ExponentialKernel(v)
+	return nil
+}
+
+// GeneralizedExponentialKernel ...
+type GeneralizedExponentialKernel struct {
+	Description   string  `xml:"description,attr,omitempty"`
+	Gamma         float64 `xml:"gamma,attr"`
+	NoiseVariance float64 `xml:"noiseVariance,attr"`
+	Degree        float64 `xml:"degree,attr"`
+	Lambda        Array   `xml:"Lambda>Array"`
+}
+
+// UnmarshalXML ...
+func (k *GeneralizedExponentialKernel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias GeneralizedExponentialKernel
+	v := alias{Gamma: 1, NoiseVariance: 1, Degree: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = GeneralizedExponentialKernel(v)
+	return nil
+}
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGaussianProcessModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/gaussian1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].GaussianProcessModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "process", out.Models[0].Name())
+	assert.Nil(t, model.RadialBasisKernel)
+	assert.Equal(t, 2.0, model.ARDSquaredExponentialKernel.Gamma)
+	assert.Equal(t, 0.1, model.ARDSquaredExponentialKernel.NoiseVariance)
+	assert.Equal(t, "c2", model.TrainingInstances.Column("x2"))
+	assert.Len(t, model.TrainingInstances.InlineTable.Rows, 4)
+
+	lambda, err := model.ARDSquaredExponentialKernel.Lambda.Floats()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, lambda)
+
+	named := out.Models[0].Named("kriging")
+	assert.Equal(t, "kriging", named.Name())
+	assert.Equal(t, "process", out.Models[0].Name())
+}
+
+func TestGaussianProcessModel_Defaults(t *testing.T) {
+	var out GaussianProcessModel
+	assert.NoError(t, xml.Unmarshal([]byte(`<GaussianProcessModel functionName="regression">
+		<RadialBasisKernel/>
+	</GaussianProcessModel>`), &out))
+	assert.Equal(t, &RadialBasisKernel{Gamma: 1, NoiseVariance: 1, Lambda: 1}, out.RadialBasisKernel)
+
+	assert.NoError(t, xml.Unmarshal([]byte(`<GaussianProcessModel functionName="regression">
+		<GeneralizedExponentialKernel/>
+	</GaussianProcessModel>`), &out))
+	assert.Equal(t, 1.0, out.GeneralizedExponentialKernel.Gamma)
+	assert.Equal(t, 1.0, out.GeneralizedExponentialKernel.NoiseVariance)
+	assert.Equal(t, 1.0, out.GeneralizedExponentialKernel.Degree)
+}
+
+func TestGaussianProcessModel_Version(t *testing.T) {
+	var out PMML
+	assert.Error(t, xml.Unmarshal([]byte(`<PMML version="4.2">
+		<GaussianProcessModel functionName="regression"/>
+	</PMML>`), &out))
+}
@@ -0,0 +1,62 @@
+package schema
+
+// GeneralRegressionModel ...
+type GeneralRegressionModel struct {
+	ModelName               string                `xml:"modelName,attr,omitempty"`
+	FunctionName            string                `xml:"functionName,attr"`
+	AlgorithmName           string                `xml:"algorithmName,attr,omitempty"`
+	ModelType               string                `xml:"modelType,attr"`
+	TargetVariableName      string                `xml:"targetVariableName,attr,omitempty"`
+	TargetReferenceCategory string                `xml:"targetReferenceCategory,attr,omitempty"`
+	CumulativeLink          string                `xml:"cumulativeLink,attr,omitempty"`
+	LinkFunction            string                `xml:"linkFunction,attr,omitempty"`
+	LinkParameter           float64               `xml:"linkParameter,attr,omitempty"`
+	TrialsVariable          string                `xml:"trialsVariable,attr,omitempty"`
+	TrialsValue             float64               `xml:"trialsValue,attr,omitempty"`
+	Distribution            string                `xml:"distribution,attr,omitempty"`
+	DistParameter           float64               `xml:"distParameter,attr,omitempty"`
+	OffsetVariable          string                `xml:"offsetVariable,attr,omitempty"`
+	OffsetValue             float64               `xml:"offsetValue,attr,omitempty"`
+	Extension               []Extension           `xml:"Extension"`
+	MiningSchema            MiningSchema          `xml:"MiningSchema"`
+	Output                  *Output               `xml:"Output"`
+	LocalTransformations    *LocalTransformations `xml:"LocalTransformations"`
+	ParameterList           []Parameter           `xml:"ParameterList>Parameter"`
+	FactorList              []GLMPredictor        `xml:"FactorList>Predictor"`
+	CovariateList           []GLMPredictor        `xml:"CovariateList>Predictor"`
+	PPMatrix                []PPCell              `xml:"PPMatrix>PPCell"`
+	ParamMatrix             []PCell               `xml:"ParamMatrix>PCell"`
+}
+
+// Parameter ...
+type Parameter struct {
+	Name           string      `xml:"name,attr"`
+	Label          string      `xml:"label,attr,omitempty"`
+	ReferencePoint float64     `xml:"referencePoint,attr,omitempty"`
+	Extension      []Extension `xml:"Extension"`
+}
+
+// GLMPredictor ...
+type GLMPredictor struct {
+	Name               string      `xml:"name,attr"`
+	ContrastMatrixType string      `xml:"contrastMatrixType,attr,omitempty"`
+	Extension          []Extension `xml:"Extension"`
+}
+
+// PPCell ...
+type PPCell struct {
+	Value          Value       `xml:"value,attr"`
+	PredictorName  string      `xml:"predictorName,attr"`
+	ParameterName  string      `xml:"parameterName,attr"`
+	TargetCategory string      `xml:"targetCategory,attr,omitempty"`
+	Extension      []Extension `xml:"Extension"`
+}
+
+// PCell ...
+type PCell struct {
+	TargetCategory string      `xml:"targetCategory,attr,omitempty"`
+	ParameterName  string      `xml:"parameterName,attr"`
+	Beta           float64     `xml:"beta,attr"`
+	DF             int         `xml:"df,attr,omitempty"`
+	Extension      []Extension `xml:"Extension"`
+}
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneralRegressionModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/general1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].GeneralRegressionModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "frequency", out.Models[0].Name())
+	assert.Equal(t, "generalizedLinear", model.ModelType)
+	assert.Equal(t, "log", model.LinkFunction)
+	assert.Equal(t, 0.1, model.OffsetValue)
+	assert.Len(t, model.ParameterList, 4)
+	assert.Equal(t, "Intercept", model.ParameterList[0].Label)
+	assert.Equal(t, []GLMPredictor{{Name: "car"}}, model.FactorList)
+	assert.Equal(t, []GLMPredictor{{Name: "age"}}, model.CovariateList)
+	assert.Equal(t, PPCell{Value: "large", PredictorName: "car", ParameterName: "p2"}, model.PPMatrix[1])
+	assert.Equal(t, PCell{ParameterName: "p3", Beta: -0.0004, DF: 1}, model.ParamMatrix[3])
+
+	named := out.Models[0].Named("glm")
+	assert.Equal(t, "glm", named.Name())
+	assert.Equal(t, "frequency", out.Models[0].Name())
+}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// NearestNeighborModel ...
+type NearestNeighborModel struct {
+	ModelName                string                `xml:"modelName,attr,omitempty"`
+	FunctionName             string                `xml:"functionName,attr"`
+	AlgorithmName            string                `xml:"algorithmName,attr,omitempty"`
+	NumberOfNeighbors        int                   `xml:"numberOfNeighbors,attr"`
+	ContinuousScoringMethod  string                `xml:"continuousScoringMethod,attr,omitempty"`
+	CategoricalScoringMethod string                `xml:"categoricalScoringMethod,attr,omitempty"`
+	InstanceIDVariable       string                `xml:"instanceIdVariable,attr,omitempty"`
+	Threshold                float64               `xml:"threshold,attr,omitempty"`
+	Extension                []Extension           `xml:"Extension"`
+	MiningSchema             MiningSchema          `xml:"MiningSchema"`
+	Output                   *Output               `xml:"Output"`
+	LocalTransformations     *LocalTransformations `xml:"LocalTransformations"`
+	TrainingInstances        TrainingInstances     `xml:"TrainingInstances"`
+	ComparisonMeasure        ComparisonMeasure     `xml:"ComparisonMeasure"`
+	Inputs                   []KNNInput            `xml:"KNNInputs>KNNInput"`
+}
+
+// UnmarshalXML ...
+func (m *NearestNeighborModel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias NearestNeighborModel
+	v := alias{ContinuousScoringMethod: "average", CategoricalScoringMethod: "majorityVote", Threshold: 0.001}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*m = NearestNeighborModel(v)
+	return nil
+}
+
+// TrainingInstances ...
+type TrainingInstances struct {
+	RecordCount   int             `xml:"recordCount,attr,omitempty"`
+	FieldCount    int             `xml:"fieldCount,attr,omitempty"`
+	IsTransformed bool            `xml:"isTransformed,attr,omitempty"`
+	Extension     []Extension     `xml:"Extension"`
+	Fields        []InstanceField `xml:"InstanceFields>InstanceField"`
+	InlineTable   *InlineTable    `xml:"InlineTable"`
+}
+
+// Column returns the column of the table which contains the values of the field.
+func (t TrainingInstances) Column(field string) string {
+	for _, f := range t.Fields {
+		if f.Field == field && f.Column != "" {
+			return f.Column
+		}
+	}
+	return field
+}
+
+// InstanceField ...
+type InstanceField struct {
+	Field  string `xml:"field,attr"`
+	Column string `xml:"column,attr,omitempty"`
+}
+
+// KNNInput ...
+type KNNInput struct {
+	Field           string      `xml:"field,attr"`
+	FieldWeight     float64     `xml:"fieldWeight,attr"`
+	CompareFunction string      `xml:"compareFunction,attr,omitempty"`
+	Extension       []Extension `xml:"Extension"`
+}
+
+// UnmarshalXML ...
+func (k *KNNInput) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias KNNInput
+	v := alias{FieldWeight: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = KNNInput(v)
+	return nil
+}
@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNearestNeighborModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/knn1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].NearestNeighborModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "iris", out.Models[0].Name())
+	assert.Equal(t, 3, model.NumberOfNeighbors)
+	assert.Equal(t, "id", model.InstanceIDVariable)
+	assert.Equal(t, "average", model.ContinuousScoringMethod)
+	assert.Equal(t, "majorityVote", model.CategoricalScoringMethod)
+	assert.Equal(t, 0.001, model.Threshold)
+	assert.Equal(t, ComparisonMeasure{Kind: "distance", CompareFunction: "absDiff", Measure: Measure{Name: "euclidean"}}, model.ComparisonMeasure)
+
+	assert.Equal(t, []KNNInput{{Field: "length", FieldWeight: 1}, {Field: "width", FieldWeight: 2}}, model.Inputs)
+	assert.Len(t, model.TrainingInstances.Fields, 4)
+	assert.Equal(t, "data:width", model.TrainingInstances.Column("width"))
+	assert.Equal(t, "other", model.TrainingInstances.Column("other"))
+
+	rows := model.TrainingInstances.InlineTable.Rows
+	assert.Len(t, rows, 7)
+	assert.Equal(t, Row{"id": "s1", "length": "1.4", "width": "0.2", "species": "setosa"}, rows[0])
+
+	named := out.Models[0].Named("neighbors")
+	assert.Equal(t, "neighbors", named.Name())
+}
+
+func TestRow(t *testing.T) {
+	var out InlineTable
+	assert.NoError(t, xml.Unmarshal([]byte(`<InlineTable>
+		<row><a> 1 </a><b>x</b></row>
+		<row><a>2</a></row>
+	</InlineTable>`), &out))
+	assert.Len(t, out.Rows, 2)
+
+	v, ok := out.Rows[0].Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+
+	v, ok = out.Rows[0].Get("data:b")
+	assert.True(t, ok)
+	assert.Equal(t, "x", v)
+
+	_, ok = out.Rows[1].Get("b")
+	assert.False(t, ok)
+}
@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Matrix ...
+type Matrix struct {
+	Kind           string    `xml:"kind,attr"`
+	Rows           int       `xml:"nbRows,attr,omitempty"`
+	Cols           int       `xml:"nbCols,attr,omitempty"`
+	DiagDefault    *float64  `xml:"diagDefault,attr"`
+	OffDiagDefault *float64  `xml:"offDiagDefault,attr"`
+	Arrays         []Array   `xml:"Array"`
+	Cells          []MatCell `xml:"MatCell"`
+}
+
+// UnmarshalXML ...
+func (m *Matrix) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Matrix
+	v := alias{Kind: "any"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*m = Matrix(v)
+	return nil
+}
+
+// MatCell ...
+type MatCell struct {
+	Row   int    `xml:"row,attr"`
+	Col   int    `xml:"col,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Floats converts the matrix to the dense slice of its rows. The diagonal matrices list their
+// diagonal in a single array and the symmetric matrices list their lower triangle, while the
+// cells which are not listed are the default values of the matrix.
+func (m Matrix) Floats() ([][]float64, error) {
+	rows, cols := m.Rows, m.Cols
+	switch {
+	case rows == 0 && len(m.Arrays) > 0 && m.Kind == "diagonal":
+		rows = m.Arrays[0].Length
+	case rows == 0:
+		rows = len(m.Arrays)
+	}
+
+	if cols == 0 {
+		switch m.Kind {
+		case "diagonal", "symmetric":
+			cols = rows
+		default:
+			for _, a := range m.Arrays {
+				if n := len(strings.Fields(a.Values)); n > cols {
+					cols = n
+				}
+			}
+		}
+	}
+
+	out := make([][]float64, rows)
+	for i := range out {
+		out[i] = make([]float64, cols)
+		for j := range out[i] {
+			switch {
+			case i == j && m.DiagDefault != nil:
+				out[i][j] = *m.DiagDefault
+			case i != j && m.OffDiagDefault != nil:
+				out[i][j] = *m.OffDiagDefault
+			}
+		}
+	}
+
+	for i, a := range m.Arrays {
+		values, err := a.Floats()
+		if err != nil {
+			return nil, err
+		}
+		if m.Kind != "diagonal" && i >= rows {
+			return nil, fmt.Errorf("matrix has more than %d rows", rows)
+		}
+
+		switch m.Kind {
+		case "diagonal":
+			if i > 0 || len(values) > rows || len(values) > cols {
+				return nil, fmt.Errorf("diagonal matrix has too many values")
+			}
+			for j, x := range values {
+				out[j][j] = x
+			}
+		case "symmetric":
+			if len(values) != i+1 {
+				return nil, fmt.Errorf("row %d of the symmetric matrix has %d values", i+1, len(values))
+			}
+			for j, x := range values {
+				out[i][j], out[j][i] = x, x
+			}
+		default:
+			if len(values) != cols {
+				return nil, fmt.Errorf("row %d of the matrix has %d values instead of %d", i+1, len(values), cols)
+			}
+			copy(out[i], values)
+		}
+	}
+
+	for _, c := range m.Cells {
+		if c.Row < 1 || c.Row > rows || c.Col < 1 || c.Col > cols {
+			return nil, fmt.Errorf("matrix cell (%d, %d) is out of range", c.Row, c.Col)
+		}
+
+		x, err := strconv.ParseFloat(strings.TrimSpace(c.Value), 64)
+		if err != nil {
+			return nil, err
+		}
+
+		out[c.Row-1][c.Col-1] = x
+		if m.Kind == "symmetric" {
+			out[c.Col-1][c.Row-1] = x
+		}
+	}
+	return out, nil
+}
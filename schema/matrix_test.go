@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix_Floats(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect [][]float64
+	}{
+		{
+			input:  `<Matrix><Array n="2" type="real">1 2</Array><Array n="2" type="real">3 4</Array></Matrix>`,
+			expect: [][]float64{{1, 2}, {3, 4}},
+		},
+		{
+			input:  `<Matrix kind="diagonal"><Array n="3" type="real">1 2 3</Array></Matrix>`,
+			expect: [][]float64{{1, 0, 0}, {0, 2, 0}, {0, 0, 3}},
+		},
+		{
+			input:  `<Matrix kind="symmetric"><Array n="1" type="real">1</Array><Array n="2" type="real">2 3</Array></Matrix>`,
+			expect: [][]float64{{1, 2}, {2, 3}},
+		},
+		{
+			input:  `<Matrix nbRows="2" nbCols="3" diagDefault="1" offDiagDefault="0.5"><MatCell row="2" col="3">7</MatCell></Matrix>`,
+			expect: [][]float64{{1, 0.5, 0.5}, {0.5, 1, 7}},
+		},
+	}
+
+	for _, tc := range tests {
+		var m Matrix
+		assert.NoError(t, xml.Unmarshal([]byte(tc.input), &m))
+
+		out, err := m.Floats()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expect, out, tc.input)
+	}
+
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{input: `<Matrix><Array n="2" type="real">1 2</Array><Array n="1" type="real">3</Array></Matrix>`, err: "row 2 of the matrix has 1 values instead of 2"},
+		{input: `<Matrix kind="symmetric"><Array n="2" type="real">1 2</Array></Matrix>`, err: "row 1 of the symmetric matrix has 2 values"},
+		{input: `<Matrix nbRows="1" nbCols="1"><MatCell row="2" col="1">1</MatCell></Matrix>`, err: "matrix cell (2, 1) is out of range"},
+		{input: `<Matrix nbRows="1" nbCols="1"><MatCell row="1" col="1">x</MatCell></Matrix>`, err: "invalid syntax"},
+		{input: `<Matrix nbRows="1" nbCols="2"><Array n="2" type="real">1 2</Array><Array n="2" type="real">3 4</Array></Matrix>`, err: "matrix has more than 1 rows"},
+	} {
+		var m Matrix
+		assert.NoError(t, xml.Unmarshal([]byte(tc.input), &m))
+
+		_, err := m.Floats()
+		assert.ErrorContains(t, err, tc.err, tc.input)
+	}
+}
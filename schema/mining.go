@@ -0,0 +1,45 @@
+package schema
+
+// MiningSchema ...
+type MiningSchema struct {
+	Extension    []Extension   `xml:"Extension"`
+	MiningFields []MiningField `xml:"MiningField"`
+}
+
+// MiningField ...
+type MiningField struct {
+	Name                    string      `xml:"name,attr"`
+	UsageType               string      `xml:"usageType,attr,omitempty"`
+	Optype                  string      `xml:"optype,attr,omitempty"`
+	Importance              float64     `xml:"importance,attr,omitempty"`
+	Outliers                string      `xml:"outliers,attr,omitempty"`
+	LowValue                *float64    `xml:"lowValue,attr,omitempty"`
+	HighValue               *float64    `xml:"highValue,attr,omitempty"`
+	MissingValueReplacement Value       `xml:"missingValueReplacement,attr,omitempty"`
+	MissingValueTreatment   string      `xml:"missingValueTreatment,attr,omitempty"`
+	InvalidValueTreatment   string      `xml:"invalidValueTreatment,attr,omitempty"`
+	InvalidValueReplacement Value       `xml:"invalidValueReplacement,attr,omitempty"`
+	Extension               []Extension `xml:"Extension"`
+}
+
+// IsInput returns whether the field is an input of the model, as opposed to the target
+// or the weight fields which are only used during training.
+func (f MiningField) IsInput() bool {
+	switch f.UsageType {
+	case "predicted", "target", "frequencyWeight", "analysisWeight":
+		return false
+	default:
+		return true
+	}
+}
+
+// Target returns the name of the first target field of the model, or an empty string if the
+// model does not declare any target field.
+func (s MiningSchema) Target() string {
+	for _, f := range s.MiningFields {
+		if f.UsageType == "predicted" || f.UsageType == "target" {
+			return f.Name
+		}
+	}
+	return ""
+}
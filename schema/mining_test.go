@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiningSchema(t *testing.T) {
+	input := `<MiningSchema>
+		<MiningField name="age" outliers="asExtremeValues" lowValue="18" highValue="99.5" missingValueReplacement="40" missingValueTreatment="asMean"/>
+		<MiningField name="color" invalidValueTreatment="asValue" invalidValueReplacement="red"/>
+		<MiningField name="class" usageType="predicted"/>
+	</MiningSchema>`
+
+	low, high := 18.0, 99.5
+	var out MiningSchema
+	assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+	assert.Equal(t, []MiningField{
+		{
+			Name:                    "age",
+			Outliers:                "asExtremeValues",
+			LowValue:                &low,
+			HighValue:               &high,
+			MissingValueReplacement: "40",
+			MissingValueTreatment:   "asMean",
+		},
+		{
+			Name:                    "color",
+			InvalidValueTreatment:   "asValue",
+			InvalidValueReplacement: "red",
+		},
+		{
+			Name:      "class",
+			UsageType: "predicted",
+		},
+	}, out.MiningFields)
+
+	assert.True(t, out.MiningFields[0].IsInput())
+	assert.False(t, out.MiningFields[2].IsInput())
+	assert.Equal(t, "class", out.Target())
+	assert.Equal(t, "", MiningSchema{}.Target())
+}
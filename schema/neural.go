@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// NeuralNetwork ...
+type NeuralNetwork struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	ActivationFunction   string                `xml:"activationFunction,attr"`
+	NormalizationMethod  string                `xml:"normalizationMethod,attr,omitempty"`
+	Threshold            float64               `xml:"threshold,attr,omitempty"`
+	Width                *float64              `xml:"width,attr,omitempty"`
+	Altitude             float64               `xml:"altitude,attr,omitempty"`
+	NumberOfLayers       int                   `xml:"numberOfLayers,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	Inputs               []NeuralInput         `xml:"NeuralInputs>NeuralInput"`
+	Layers               []NeuralLayer         `xml:"NeuralLayer"`
+	Outputs              []NeuralOutput        `xml:"NeuralOutputs>NeuralOutput"`
+}
+
+// UnmarshalXML ...
+func (n *NeuralNetwork) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias NeuralNetwork
+	v := alias{NormalizationMethod: "none", Altitude: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*n = NeuralNetwork(v)
+	return nil
+}
+
+// NeuralInput ...
+type NeuralInput struct {
+	ID           string       `xml:"id,attr"`
+	Extension    []Extension  `xml:"Extension"`
+	DerivedField DerivedField `xml:"DerivedField"`
+}
+
+// NeuralLayer ...
+type NeuralLayer struct {
+	NumberOfNeurons     int         `xml:"numberOfNeurons,attr,omitempty"`
+	ActivationFunction  string      `xml:"activationFunction,attr,omitempty"`
+	NormalizationMethod string      `xml:"normalizationMethod,attr,omitempty"`
+	Threshold           *float64    `xml:"threshold,attr,omitempty"`
+	Width               *float64    `xml:"width,attr,omitempty"`
+	Altitude            *float64    `xml:"altitude,attr,omitempty"`
+	Extension           []Extension `xml:"Extension"`
+	Neurons             []Neuron    `xml:"Neuron"`
+}
+
+// Neuron ...
+type Neuron struct {
+	ID          string      `xml:"id,attr"`
+	Bias        float64     `xml:"bias,attr,omitempty"`
+	Width       *float64    `xml:"width,attr,omitempty"`
+	Altitude    *float64    `xml:"altitude,attr,omitempty"`
+	Extension   []Extension `xml:"Extension"`
+	Connections []Con       `xml:"Con"`
+}
+
+// Con ...
+type Con struct {
+	From   string  `xml:"from,attr"`
+	Weight float64 `xml:"weight,attr"`
+}
+
+// NeuralOutput ...
+type NeuralOutput struct {
+	OutputNeuron string       `xml:"outputNeuron,attr"`
+	Extension    []Extension  `xml:"Extension"`
+	DerivedField DerivedField `xml:"DerivedField"`
+}
@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeuralNetwork(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/neural1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].NeuralNetwork
+	assert.NotNil(t, model)
+	assert.Equal(t, "approval", out.Models[0].Name())
+	assert.Equal(t, "logistic", model.ActivationFunction)
+	assert.Equal(t, "none", model.NormalizationMethod)
+	assert.Equal(t, 1.0, model.Altitude)
+	assert.Nil(t, model.Width)
+
+	assert.Len(t, model.Inputs, 2)
+	assert.NotNil(t, model.Inputs[0].DerivedField.Expression.NormContinuous)
+	assert.NotNil(t, model.Inputs[1].DerivedField.Expression.NormDiscrete)
+
+	assert.Len(t, model.Layers, 2)
+	assert.Equal(t, "", model.Layers[0].ActivationFunction)
+	assert.Equal(t, "softmax", model.Layers[1].NormalizationMethod)
+	assert.Equal(t, -1.0, model.Layers[0].Neurons[0].Bias)
+	assert.Equal(t, Con{From: "1", Weight: 0.5}, model.Layers[0].Neurons[0].Connections[1])
+
+	assert.Len(t, model.Outputs, 2)
+	assert.Equal(t, "5", model.Outputs[1].OutputNeuron)
+	assert.Equal(t, Value("no"), model.Outputs[1].DerivedField.Expression.NormDiscrete.Value)
+
+	named := out.Models[0].Named("net")
+	assert.Equal(t, "net", named.Name())
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// Output ...
+type Output struct {
+	Extension    []Extension   `xml:"Extension"`
+	OutputFields []OutputField `xml:"OutputField"`
+}
+
+// OutputField ...
+type OutputField struct {
+	Name          string
+	DisplayName   string
+	Optype        string
+	DataType      string
+	TargetField   string
+	Feature       string
+	Value         Value
+	RuleFeature   string
+	Algorithm     string
+	Rank          int
+	RankBasis     string
+	RankOrder     string
+	IsMultiValued bool
+	SegmentID     string
+	IsFinalResult bool
+	Decisions     *Decisions
+	Expression    *Expression
+}
+
+// UnmarshalXML ...
+func (f *OutputField) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	f.Feature = "predictedValue"
+	f.Rank = 1
+	f.IsFinalResult = true
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			f.Name = attr.Value
+		case "displayName":
+			f.DisplayName = attr.Value
+		case "optype":
+			f.Optype = attr.Value
+		case "dataType":
+			f.DataType = attr.Value
+		case "targetField":
+			f.TargetField = attr.Value
+		case "feature":
+			f.Feature = attr.Value
+		case "value":
+			f.Value = Value(attr.Value)
+		case "ruleFeature":
+			f.RuleFeature = attr.Value
+		case "algorithm":
+			f.Algorithm = attr.Value
+		case "rankBasis":
+			f.RankBasis = attr.Value
+		case "rankOrder":
+			f.RankOrder = attr.Value
+		case "segmentId":
+			f.SegmentID = attr.Value
+		case "rank":
+			if f.Rank, err = strconv.Atoi(attr.Value); err != nil {
+				return err
+			}
+		case "isMultiValued":
+			if f.IsMultiValued, err = strconv.ParseBool(attr.Value); err != nil {
+				return err
+			}
+		case "isFinalResult":
+			if f.IsFinalResult, err = strconv.ParseBool(attr.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		if el.Name.Local == "Decisions" {
+			f.Decisions = new(Decisions)
+			return d.DecodeElement(f.Decisions, &el)
+		}
+
+		f.Expression = new(Expression)
+		return f.Expression.UnmarshalXML(d, el)
+	})
+}
+
+// Decisions ...
+type Decisions struct {
+	BusinessProblem string      `xml:"businessProblem,attr,omitempty"`
+	Description     string      `xml:"description,attr,omitempty"`
+	Extension       []Extension `xml:"Extension"`
+	Decisions       []Decision  `xml:"Decision"`
+}
+
+// Decision ...
+type Decision struct {
+	Value        string      `xml:"value,attr"`
+	DisplayValue string      `xml:"displayValue,attr,omitempty"`
+	Description  string      `xml:"description,attr,omitempty"`
+	Extension    []Extension `xml:"Extension"`
+}
@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutput(t *testing.T) {
+	var out Output
+	assert.NoError(t, xml.Unmarshal([]byte(`<Output>
+		<OutputField name="p" feature="probability" value="yes" rank="2" isFinalResult="false"/>
+		<OutputField name="d" feature="decision">
+			<Extension name="x" value="y"/>
+			<Decisions businessProblem="b">
+				<Decision value="go" displayValue="Go"/>
+			</Decisions>
+			<FieldRef field="p"/>
+		</OutputField>
+		<OutputField name="v"/>
+	</Output>`), &out))
+
+	assert.Len(t, out.OutputFields, 3)
+	assert.Equal(t, "probability", out.OutputFields[0].Feature)
+	assert.Equal(t, Value("yes"), out.OutputFields[0].Value)
+	assert.Equal(t, 2, out.OutputFields[0].Rank)
+	assert.False(t, out.OutputFields[0].IsFinalResult)
+
+	assert.Equal(t, "b", out.OutputFields[1].Decisions.BusinessProblem)
+	assert.Equal(t, "Go", out.OutputFields[1].Decisions.Decisions[0].DisplayValue)
+	assert.Equal(t, "p", out.OutputFields[1].Expression.FieldRef.Field)
+
+	assert.Equal(t, "predictedValue", out.OutputFields[2].Feature)
+	assert.Equal(t, 1, out.OutputFields[2].Rank)
+	assert.True(t, out.OutputFields[2].IsFinalResult)
+}
+
+func TestOutputField_Invalid(t *testing.T) {
+	var out Output
+	assert.Error(t, xml.Unmarshal([]byte(`<Output><OutputField name="x" rank="a"/></Output>`), &out))
+}
@@ -0,0 +1,373 @@
+package schema
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// PMML represents the root element of a PMML document.
+type PMML struct {
+	Version                  string
+	Header                   *Header
+	DataDictionary           *DataDictionary
+	TransformationDictionary *TransformationDictionary
+	Models                   []Model
+}
+
+// UnmarshalXML ...
+func (p *PMML) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if start.Name.Local != "PMML" {
+		return fmt.Errorf("expected a PMML root element, got %s", start.Name.Local)
+	}
+
+	version, err := versionOf(start)
+	if err != nil {
+		return err
+	}
+
+	p.Version = version
+	return decodeChildren(d, func(el xml.StartElement) error {
+		switch el.Name.Local {
+		case "Header":
+			p.Header = new(Header)
+			return d.DecodeElement(p.Header, &el)
+		case "DataDictionary":
+			p.DataDictionary = new(DataDictionary)
+			return d.DecodeElement(p.DataDictionary, &el)
+		case "TransformationDictionary":
+			p.TransformationDictionary = new(TransformationDictionary)
+			return d.DecodeElement(p.TransformationDictionary, &el)
+		case "MiningBuildTask":
+			return d.Skip()
+		default:
+			if err := supports(p.Version, el.Name.Local); err != nil {
+				return err
+			}
+
+			var model Model
+			if err := model.UnmarshalXML(d, el); err != nil {
+				return err
+			}
+			if err := model.supports(p.Version); err != nil {
+				return err
+			}
+			p.Models = append(p.Models, model)
+			return nil
+		}
+	})
+}
+
+// ----------------------------------------------------------------------------
+
+// Model represents one of the model elements of the document.
+type Model struct {
+	TreeModel                 *DecisionTree
+	RegressionModel           *RegressionModel
+	GeneralRegressionModel    *GeneralRegressionModel
+	MiningModel               *MiningModel
+	Scorecard                 *Scorecard
+	RuleSetModel              *RuleSetModel
+	NeuralNetwork             *NeuralNetwork
+	NaiveBayesModel           *NaiveBayesModel
+	SupportVectorMachineModel *SupportVectorMachineModel
+	ClusteringModel           *ClusteringModel
+	NearestNeighborModel      *NearestNeighborModel
+	AnomalyDetectionModel     *AnomalyDetectionModel
+	TimeSeriesModel           *TimeSeriesModel
+	AssociationModel          *AssociationModel
+	BaselineModel             *BaselineModel
+	GaussianProcessModel      *GaussianProcessModel
+	TextModel                 *TextModel
+	element                   string // The name of the model element, used to check its version
+}
+
+// UnmarshalXML ...
+func (m *Model) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	m.element = start.Name.Local
+	switch start.Name.Local {
+	case "TreeModel":
+		m.TreeModel = new(DecisionTree)
+		return d.DecodeElement(m.TreeModel, &start)
+	case "RegressionModel":
+		m.RegressionModel = new(RegressionModel)
+		return d.DecodeElement(m.RegressionModel, &start)
+	case "GeneralRegressionModel":
+		m.GeneralRegressionModel = new(GeneralRegressionModel)
+		return d.DecodeElement(m.GeneralRegressionModel, &start)
+	case "MiningModel":
+		m.MiningModel = new(MiningModel)
+		return d.DecodeElement(m.MiningModel, &start)
+	case "Scorecard":
+		m.Scorecard = new(Scorecard)
+		return d.DecodeElement(m.Scorecard, &start)
+	case "RuleSetModel":
+		m.RuleSetModel = new(RuleSetModel)
+		return d.DecodeElement(m.RuleSetModel, &start)
+	case "NeuralNetwork":
+		m.NeuralNetwork = new(NeuralNetwork)
+		return d.DecodeElement(m.NeuralNetwork, &start)
+	case "NaiveBayesModel":
+		m.NaiveBayesModel = new(NaiveBayesModel)
+		return d.DecodeElement(m.NaiveBayesModel, &start)
+	case "SupportVectorMachineModel":
+		m.SupportVectorMachineModel = new(SupportVectorMachineModel)
+		return d.DecodeElement(m.SupportVectorMachineModel, &start)
+	case "ClusteringModel":
+		m.ClusteringModel = new(ClusteringModel)
+		return d.DecodeElement(m.ClusteringModel, &start)
+	case "NearestNeighborModel":
+		m.NearestNeighborModel = new(NearestNeighborModel)
+		return d.DecodeElement(m.NearestNeighborModel, &start)
+	case "AnomalyDetectionModel":
+		m.AnomalyDetectionModel = new(AnomalyDetectionModel)
+		return d.DecodeElement(m.AnomalyDetectionModel, &start)
+	case "TimeSeriesModel":
+		m.TimeSeriesModel = new(TimeSeriesModel)
+		return d.DecodeElement(m.TimeSeriesModel, &start)
+	case "AssociationModel":
+		m.AssociationModel = new(AssociationModel)
+		return d.DecodeElement(m.AssociationModel, &start)
+	case "BaselineModel":
+		m.BaselineModel = new(BaselineModel)
+		return d.DecodeElement(m.BaselineModel, &start)
+	case "GaussianProcessModel":
+		m.GaussianProcessModel = new(GaussianProcessModel)
+		return d.DecodeElement(m.GaussianProcessModel, &start)
+	case "TextModel":
+		m.TextModel = new(TextModel)
+		return d.DecodeElement(m.TextModel, &start)
+	default:
+		return fmt.Errorf("unsupported model type %s", start.Name.Local)
+	}
+}
+
+// Name returns the name of the model.
+func (m Model) Name() string {
+	switch {
+	case m.TreeModel != nil:
+		return m.TreeModel.ModelName
+	case m.RegressionModel != nil:
+		return m.RegressionModel.ModelName
+	case m.GeneralRegressionModel != nil:
+		return m.GeneralRegressionModel.ModelName
+	case m.MiningModel != nil:
+		return m.MiningModel.ModelName
+	case m.Scorecard != nil:
+		return m.Scorecard.ModelName
+	case m.RuleSetModel != nil:
+		return m.RuleSetModel.ModelName
+	case m.NeuralNetwork != nil:
+		return m.NeuralNetwork.ModelName
+	case m.NaiveBayesModel != nil:
+		return m.NaiveBayesModel.ModelName
+	case m.SupportVectorMachineModel != nil:
+		return m.SupportVectorMachineModel.ModelName
+	case m.ClusteringModel != nil:
+		return m.ClusteringModel.ModelName
+	case m.NearestNeighborModel != nil:
+		return m.NearestNeighborModel.ModelName
+	case m.AnomalyDetectionModel != nil:
+		return m.AnomalyDetectionModel.ModelName
+	case m.TimeSeriesModel != nil:
+		return m.TimeSeriesModel.ModelName
+	case m.AssociationModel != nil:
+		return m.AssociationModel.ModelName
+	case m.BaselineModel != nil:
+		return m.BaselineModel.ModelName
+	case m.GaussianProcessModel != nil:
+		return m.GaussianProcessModel.ModelName
+	case m.TextModel != nil:
+		return m.TextModel.ModelName
+	default:
+		return ""
+	}
+}
+
+// supports checks whether the model and every model nested in it, such as the models of the
+// segments, exist in the specified version of the standard.
+func (m Model) supports(version string) error {
+	if err := supports(version, m.element); err != nil {
+		return err
+	}
+
+	switch {
+	case m.MiningModel != nil && m.MiningModel.Segmentation != nil:
+		for _, segment := range m.MiningModel.Segmentation.Segments {
+			if err := segment.Model.supports(version); err != nil {
+				return err
+			}
+		}
+	case m.AnomalyDetectionModel != nil:
+		return m.AnomalyDetectionModel.Model.supports(version)
+	}
+	return nil
+}
+
+// LocalTransformations returns the local transformations of the model, if any.
+func (m Model) LocalTransformations() *LocalTransformations {
+	switch {
+	case m.TreeModel != nil:
+		return m.TreeModel.LocalTransformations
+	case m.RegressionModel != nil:
+		return m.RegressionModel.LocalTransformations
+	case m.GeneralRegressionModel != nil:
+		return m.GeneralRegressionModel.LocalTransformations
+	case m.MiningModel != nil:
+		return m.MiningModel.LocalTransformations
+	case m.Scorecard != nil:
+		return m.Scorecard.LocalTransformations
+	case m.RuleSetModel != nil:
+		return m.RuleSetModel.LocalTransformations
+	case m.NeuralNetwork != nil:
+		return m.NeuralNetwork.LocalTransformations
+	case m.NaiveBayesModel != nil:
+		return m.NaiveBayesModel.LocalTransformations
+	case m.SupportVectorMachineModel != nil:
+		return m.SupportVectorMachineModel.LocalTransformations
+	case m.ClusteringModel != nil:
+		return m.ClusteringModel.LocalTransformations
+	case m.NearestNeighborModel != nil:
+		return m.NearestNeighborModel.LocalTransformations
+	case m.AnomalyDetectionModel != nil:
+		return m.AnomalyDetectionModel.LocalTransformations
+	case m.TimeSeriesModel != nil:
+		return m.TimeSeriesModel.LocalTransformations
+	case m.AssociationModel != nil:
+		return m.AssociationModel.LocalTransformations
+	case m.BaselineModel != nil:
+		return m.BaselineModel.LocalTransformations
+	case m.GaussianProcessModel != nil:
+		return m.GaussianProcessModel.LocalTransformations
+	case m.TextModel != nil:
+		return m.TextModel.LocalTransformations
+	default:
+		return nil
+	}
+}
+
+// Named returns a copy of the model with the specified name.
+func (m Model) Named(name string) Model {
+	switch {
+	case m.TreeModel != nil:
+		v := *m.TreeModel
+		v.ModelName = name
+		m.TreeModel = &v
+	case m.RegressionModel != nil:
+		v := *m.RegressionModel
+		v.ModelName = name
+		m.RegressionModel = &v
+	case m.GeneralRegressionModel != nil:
+		v := *m.GeneralRegressionModel
+		v.ModelName = name
+		m.GeneralRegressionModel = &v
+	case m.MiningModel != nil:
+		v := *m.MiningModel
+		v.ModelName = name
+		m.MiningModel = &v
+	case m.Scorecard != nil:
+		v := *m.Scorecard
+		v.ModelName = name
+		m.Scorecard = &v
+	case m.RuleSetModel != nil:
+		v := *m.RuleSetModel
+		v.ModelName = name
+		m.RuleSetModel = &v
+	case m.NeuralNetwork != nil:
+		v := *m.NeuralNetwork
+		v.ModelName = name
+		m.NeuralNetwork = &v
+	case m.NaiveBayesModel != nil:
+		v := *m.NaiveBayesModel
+		v.ModelName = name
+		m.NaiveBayesModel = &v
+	case m.SupportVectorMachineModel != nil:
+		v := *m.SupportVectorMachineModel
+		v.ModelName = name
+		m.SupportVectorMachineModel = &v
+	case m.ClusteringModel != nil:
+		v := *m.ClusteringModel
+		v.ModelName = name
+		m.ClusteringModel = &v
+	case m.NearestNeighborModel != nil:
+		v := *m.NearestNeighborModel
+		v.ModelName = name
+		m.NearestNeighborModel = &v
+	case m.AnomalyDetectionModel != nil:
+		v := *m.AnomalyDetectionModel
+		v.ModelName = name
+		m.AnomalyDetectionModel = &v
+	case m.TimeSeriesModel != nil:
+		v := *m.TimeSeriesModel
+		v.ModelName = name
+		m.TimeSeriesModel = &v
+	case m.AssociationModel != nil:
+		v := *m.AssociationModel
+		v.ModelName = name
+		m.AssociationModel = &v
+	case m.BaselineModel != nil:
+		v := *m.BaselineModel
+		v.ModelName = name
+		m.BaselineModel = &v
+	case m.GaussianProcessModel != nil:
+		v := *m.GaussianProcessModel
+		v.ModelName = name
+		m.GaussianProcessModel = &v
+	case m.TextModel != nil:
+		v := *m.TextModel
+		v.ModelName = name
+		m.TextModel = &v
+	}
+	return m
+}
+
+// ----------------------------------------------------------------------------
+
+// Header ...
+type Header struct {
+	Copyright    string       `xml:"copyright,attr,omitempty"`
+	Description  string       `xml:"description,attr,omitempty"`
+	ModelVersion string       `xml:"modelVersion,attr,omitempty"`
+	Extension    []Extension  `xml:"Extension"`
+	Application  *Application `xml:"Application"`
+	Annotation   []string     `xml:"Annotation"`
+	Timestamp    string       `xml:"Timestamp,omitempty"`
+}
+
+// Application ...
+type Application struct {
+	Name    string `xml:"name,attr"`
+	Version string `xml:"version,attr,omitempty"`
+}
+
+// ----------------------------------------------------------------------------
+
+// decodeChildren iterates through the child elements of the current element, skipping
+// the extensions and the elements of foreign namespaces, until the end element is reached.
+func decodeChildren(d *xml.Decoder, fn func(xml.StartElement) error) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch el := t.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			if el.Name.Local == "Extension" || !isNamespace(el.Name.Space) {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := fn(el); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPMML(t *testing.T) {
+	input, err := ioutil.ReadFile("../fixtures/tree1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(input, &out))
+	assert.Equal(t, "4.4", out.Version)
+	assert.Equal(t, "www.dmg.org", out.Header.Copyright)
+	assert.Equal(t, "pmml2lua", out.Header.Application.Name)
+	assert.Len(t, out.DataDictionary.DataFields, 4)
+	assert.Equal(t, DataField{
+		Name:     "outlook",
+		Optype:   "categorical",
+		DataType: "string",
+		Values: []FieldValue{
+			{Value: "sunny"}, {Value: "overcast"}, {Value: "rain"},
+		},
+	}, out.DataDictionary.DataFields[2])
+
+	assert.Len(t, out.Models, 1)
+	assert.Equal(t, "golfing", out.Models[0].Name())
+	assert.Equal(t, "weightedConfidence", out.Models[0].TreeModel.MissingValueStrategy)
+	assert.Len(t, out.Models[0].TreeModel.Node.Nodes, 2)
+}
+
+func TestPMML_Unsupported(t *testing.T) {
+	input := `<PMML version="4.4"><Header/><UnknownModel/></PMML>`
+
+	var out PMML
+	assert.Error(t, xml.Unmarshal([]byte(input), &out))
+}
+
+func TestModel_Named(t *testing.T) {
+	input := `<PMML version="4.4"><TreeModel functionName="classification"><Node><True/></Node></TreeModel></PMML>`
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+	assert.Equal(t, "", out.Models[0].Name())
+
+	named := out.Models[0].Named("tree")
+	assert.Equal(t, "tree", named.Name())
+	assert.Equal(t, "", out.Models[0].Name())
+}
+
+func TestTransformationDictionary(t *testing.T) {
+	input := `<TransformationDictionary>
+		<DefineFunction name="AMPM" optype="categorical" dataType="string">
+			<ParameterField name="TimeVal" optype="continuous" dataType="integer"/>
+			<Discretize field="TimeVal">
+				<DiscretizeBin binValue="AM">
+					<Interval closure="closedClosed" leftMargin="0" rightMargin="43199"/>
+				</DiscretizeBin>
+				<DiscretizeBin binValue="PM">
+					<Interval closure="closedOpen" leftMargin="43200" rightMargin="86400"/>
+				</DiscretizeBin>
+			</Discretize>
+		</DefineFunction>
+		<DerivedField name="ratio" optype="continuous" dataType="double">
+			<Extension name="ignored"/>
+			<Apply function="/">
+				<FieldRef field="a"/>
+				<Constant dataType="double">2.5</Constant>
+			</Apply>
+		</DerivedField>
+	</TransformationDictionary>`
+
+	var out TransformationDictionary
+	assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+	assert.Len(t, out.DefineFunctions, 1)
+	assert.Equal(t, "AMPM", out.DefineFunctions[0].Name)
+	assert.Equal(t, []ParameterField{
+		{Name: "TimeVal", Optype: "continuous", DataType: "integer"},
+	}, out.DefineFunctions[0].ParameterFields)
+
+	bins := out.DefineFunctions[0].Expression.Discretize.Bins
+	assert.Len(t, bins, 2)
+	assert.Equal(t, Value("PM"), bins[1].BinValue)
+	assert.Equal(t, "closedOpen", bins[1].Interval.Closure)
+	assert.Equal(t, 43200.0, *bins[1].Interval.LeftMargin)
+
+	assert.Equal(t, DerivedField{
+		Name:     "ratio",
+		Optype:   "continuous",
+		DataType: "double",
+		Expression: &Expression{
+			Apply: &Apply{
+				Function: "/",
+				Expressions: []Expression{
+					{FieldRef: &FieldRef{Field: "a"}},
+					{Constant: &Constant{DataType: "double", Value: "2.5"}},
+				},
+			},
+		},
+	}, out.DerivedFields[0])
+}
+
+func TestModel_LocalTransformations(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/transform1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+	assert.Len(t, out.TransformationDictionary.DerivedFields, 2)
+
+	local := out.Models[0].LocalTransformations()
+	assert.NotNil(t, local)
+	assert.Len(t, local.DerivedFields, 1)
+	assert.Equal(t, "large", local.DerivedFields[0].Name)
+	assert.Equal(t, "greaterThan", local.DerivedFields[0].Expression.Apply.Function)
+
+	var anomaly AnomalyDetectionModel
+	assert.NoError(t, xml.Unmarshal([]byte(`<AnomalyDetectionModel algorithmType="iforest" sampleDataSize="8">
+		<LocalTransformations><DerivedField name="d"><Constant>1</Constant></DerivedField></LocalTransformations>
+	</AnomalyDetectionModel>`), &anomaly))
+	assert.Equal(t, "d", anomaly.LocalTransformations.DerivedFields[0].Name)
+	assert.Nil(t, Model{}.LocalTransformations())
+}
@@ -33,7 +33,7 @@ func (p *Predicate) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		p.False = new(False)
 		return d.DecodeElement(p.False, &start)
 	default:
-		return fmt.Errorf("unsupported predicate type")
+		return fmt.Errorf("unsupported predicate type %s", start.Name.Local)
 	}
 }
 
@@ -53,26 +53,14 @@ func (p *CompoundPredicate) UnmarshalXML(d *xml.Decoder, start xml.StartElement)
 		}
 	}
 
-	var done bool
-	for !done {
-		t, err := d.Token()
-		if err != nil {
+	return decodeChildren(d, func(el xml.StartElement) error {
+		var predicate Predicate
+		if err := predicate.UnmarshalXML(d, el); err != nil {
 			return err
 		}
-
-		switch t := t.(type) {
-		case xml.StartElement:
-			var predicate Predicate
-			if err := predicate.UnmarshalXML(d, t); err != nil {
-				return err
-			}
-			p.Predicates = append(p.Predicates, predicate)
-		case xml.EndElement:
-			done = true
-		}
-	}
-
-	return nil
+		p.Predicates = append(p.Predicates, predicate)
+		return nil
+	})
 }
 
 // ----------------------------------------------------------------------------
@@ -112,4 +100,3 @@ type SimplePredicate struct {
 
 // Value ...
 type Value string
-
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// RegressionModel ...
+type RegressionModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	ModelType            string                `xml:"modelType,attr,omitempty"`
+	TargetFieldName      string                `xml:"targetFieldName,attr,omitempty"`
+	NormalizationMethod  string                `xml:"normalizationMethod,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	RegressionTables     []RegressionTable     `xml:"RegressionTable"`
+}
+
+// RegressionTable ...
+type RegressionTable struct {
+	Intercept             float64                `xml:"intercept,attr"`
+	TargetCategory        string                 `xml:"targetCategory,attr,omitempty"`
+	Extension             []Extension            `xml:"Extension"`
+	NumericPredictors     []NumericPredictor     `xml:"NumericPredictor"`
+	CategoricalPredictors []CategoricalPredictor `xml:"CategoricalPredictor"`
+	PredictorTerms        []PredictorTerm        `xml:"PredictorTerm"`
+}
+
+// NumericPredictor ...
+type NumericPredictor struct {
+	Name        string      `xml:"name,attr"`
+	Exponent    int         `xml:"exponent,attr"`
+	Coefficient float64     `xml:"coefficient,attr"`
+	Extension   []Extension `xml:"Extension"`
+}
+
+// UnmarshalXML ...
+func (p *NumericPredictor) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias NumericPredictor
+	v := alias{Exponent: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*p = NumericPredictor(v)
+	return nil
+}
+
+// CategoricalPredictor ...
+type CategoricalPredictor struct {
+	Name        string      `xml:"name,attr"`
+	Value       Value       `xml:"value,attr"`
+	Coefficient float64     `xml:"coefficient,attr"`
+	Extension   []Extension `xml:"Extension"`
+}
+
+// PredictorTerm ...
+type PredictorTerm struct {
+	Name        string      `xml:"name,attr,omitempty"`
+	Coefficient float64     `xml:"coefficient,attr"`
+	Extension   []Extension `xml:"Extension"`
+	FieldRefs   []FieldRef  `xml:"FieldRef"`
+}
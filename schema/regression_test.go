@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegressionModel(t *testing.T) {
+	input := `<PMML version="4.4">
+		<RegressionModel functionName="classification" normalizationMethod="softmax" targetFieldName="y">
+			<MiningSchema><MiningField name="x"/></MiningSchema>
+			<RegressionTable intercept="1.5" targetCategory="yes">
+				<NumericPredictor name="x" coefficient="2"/>
+				<NumericPredictor name="x" exponent="3" coefficient="0.5"/>
+				<CategoricalPredictor name="c" value="red" coefficient="-1"/>
+				<PredictorTerm coefficient="0.25"><FieldRef field="x"/><FieldRef field="z"/></PredictorTerm>
+			</RegressionTable>
+			<RegressionTable intercept="0" targetCategory="no"/>
+		</RegressionModel>
+	</PMML>`
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+
+	model := out.Models[0].RegressionModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "softmax", model.NormalizationMethod)
+	assert.Equal(t, "y", model.TargetFieldName)
+	assert.Len(t, model.RegressionTables, 2)
+
+	table := model.RegressionTables[0]
+	assert.Equal(t, 1.5, table.Intercept)
+	assert.Equal(t, "yes", table.TargetCategory)
+	assert.Equal(t, []NumericPredictor{
+		{Name: "x", Exponent: 1, Coefficient: 2},
+		{Name: "x", Exponent: 3, Coefficient: 0.5},
+	}, table.NumericPredictors)
+	assert.Equal(t, Value("red"), table.CategoricalPredictors[0].Value)
+	assert.Equal(t, "z", table.PredictorTerms[0].FieldRefs[1].Field)
+
+	named := out.Models[0].Named("linear")
+	assert.Equal(t, "linear", named.Name())
+	assert.Equal(t, "", out.Models[0].Name())
+}
@@ -0,0 +1,181 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// RuleSetModel ...
+type RuleSetModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	RuleSet              RuleSet               `xml:"RuleSet"`
+}
+
+// RuleSet ...
+type RuleSet struct {
+	RecordCount       int64
+	NbCorrect         int64
+	DefaultScore      string
+	DefaultConfidence float64
+	Methods           []RuleSelectionMethod
+	Distributions     []ScoreDistribution
+	Rules             []Rule
+}
+
+// UnmarshalXML ...
+func (r *RuleSet) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, v := range start.Attr {
+		switch v.Name.Local {
+		case "defaultScore":
+			r.DefaultScore = v.Value
+		case "defaultConfidence":
+			if r.DefaultConfidence, err = strconv.ParseFloat(v.Value, 64); err != nil {
+				return err
+			}
+		case "recordCount":
+			if r.RecordCount, err = strconv.ParseInt(v.Value, 10, 64); err != nil {
+				return err
+			}
+		case "nbCorrect":
+			if r.NbCorrect, err = strconv.ParseInt(v.Value, 10, 64); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		switch el.Name.Local {
+		case "RuleSelectionMethod":
+			var method RuleSelectionMethod
+			if err := d.DecodeElement(&method, &el); err != nil {
+				return err
+			}
+			r.Methods = append(r.Methods, method)
+			return nil
+
+		case "ScoreDistribution":
+			var dist ScoreDistribution
+			if err := d.DecodeElement(&dist, &el); err != nil {
+				return err
+			}
+			r.Distributions = append(r.Distributions, dist)
+			return nil
+
+		default:
+			return decodeRule(d, el, &r.Rules)
+		}
+	})
+}
+
+// RuleSelectionMethod ...
+type RuleSelectionMethod struct {
+	Criterion string `xml:"criterion,attr"`
+}
+
+// Rule represents either a simple or a compound rule.
+type Rule struct {
+	SimpleRule   *SimpleRule
+	CompoundRule *CompoundRule
+}
+
+// SimpleRule ...
+type SimpleRule struct {
+	ID            string
+	Score         string
+	RecordCount   int64
+	NbCorrect     int64
+	Confidence    float64
+	Weight        float64
+	Predicate     *Predicate
+	Distributions []ScoreDistribution
+}
+
+// UnmarshalXML ...
+func (r *SimpleRule) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	r.Confidence, r.Weight = 1, 1
+	for _, v := range start.Attr {
+		switch v.Name.Local {
+		case "id":
+			r.ID = v.Value
+		case "score":
+			r.Score = v.Value
+		case "recordCount":
+			if r.RecordCount, err = strconv.ParseInt(v.Value, 10, 64); err != nil {
+				return err
+			}
+		case "nbCorrect":
+			if r.NbCorrect, err = strconv.ParseInt(v.Value, 10, 64); err != nil {
+				return err
+			}
+		case "confidence":
+			if r.Confidence, err = strconv.ParseFloat(v.Value, 64); err != nil {
+				return err
+			}
+		case "weight":
+			if r.Weight, err = strconv.ParseFloat(v.Value, 64); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		if el.Name.Local == "ScoreDistribution" {
+			var dist ScoreDistribution
+			if err := d.DecodeElement(&dist, &el); err != nil {
+				return err
+			}
+			r.Distributions = append(r.Distributions, dist)
+			return nil
+		}
+
+		r.Predicate = new(Predicate)
+		return d.DecodeElement(r.Predicate, &el)
+	})
+}
+
+// CompoundRule ...
+type CompoundRule struct {
+	Predicate *Predicate
+	Rules     []Rule
+}
+
+// UnmarshalXML ...
+func (r *CompoundRule) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decodeChildren(d, func(el xml.StartElement) error {
+		switch el.Name.Local {
+		case "SimpleRule", "CompoundRule":
+			return decodeRule(d, el, &r.Rules)
+		default:
+			r.Predicate = new(Predicate)
+			return d.DecodeElement(r.Predicate, &el)
+		}
+	})
+}
+
+// decodeRule decodes a simple or a compound rule and appends it to the rules.
+func decodeRule(d *xml.Decoder, el xml.StartElement, rules *[]Rule) error {
+	var rule Rule
+	switch el.Name.Local {
+	case "SimpleRule":
+		rule.SimpleRule = new(SimpleRule)
+		if err := d.DecodeElement(rule.SimpleRule, &el); err != nil {
+			return err
+		}
+	case "CompoundRule":
+		rule.CompoundRule = new(CompoundRule)
+		if err := d.DecodeElement(rule.CompoundRule, &el); err != nil {
+			return err
+		}
+	default:
+		return d.Skip()
+	}
+
+	*rules = append(*rules, rule)
+	return nil
+}
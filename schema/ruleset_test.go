@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSetModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/ruleset1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].RuleSetModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "drugs", out.Models[0].Name())
+	assert.Equal(t, "drugY", model.RuleSet.DefaultScore)
+	assert.Equal(t, 0.1, model.RuleSet.DefaultConfidence)
+	assert.Equal(t, int64(1000), model.RuleSet.RecordCount)
+	assert.Equal(t, int64(149), model.RuleSet.NbCorrect)
+	assert.Len(t, model.RuleSet.Methods, 3)
+	assert.Len(t, model.RuleSet.Rules, 4)
+
+	rule := model.RuleSet.Rules[0].SimpleRule
+	assert.Equal(t, "RULE1", rule.ID)
+	assert.Equal(t, 0.9, rule.Weight)
+	assert.Len(t, rule.Distributions, 4)
+	assert.NotNil(t, rule.Predicate.CompoundPredicate)
+
+	compound := model.RuleSet.Rules[1].CompoundRule
+	assert.NotNil(t, compound.Predicate.SimplePredicate)
+	assert.Len(t, compound.Rules, 2)
+	assert.Equal(t, "RULE3", compound.Rules[1].CompoundRule.Rules[0].SimpleRule.ID)
+
+	named := out.Models[0].Named("rules")
+	assert.Equal(t, "rules", named.Name())
+}
+
+func TestSimpleRule_Defaults(t *testing.T) {
+	var out SimpleRule
+	assert.NoError(t, xml.Unmarshal([]byte(`<SimpleRule score="x"><True/></SimpleRule>`), &out))
+	assert.Equal(t, 1.0, out.Confidence)
+	assert.Equal(t, 1.0, out.Weight)
+
+	assert.Error(t, xml.Unmarshal([]byte(`<SimpleRule weight="x"><True/></SimpleRule>`), new(SimpleRule)))
+	assert.Error(t, xml.Unmarshal([]byte(`<RuleSet defaultConfidence="x"/>`), new(RuleSet)))
+}
@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// Scorecard ...
+type Scorecard struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	InitialScore         float64               `xml:"initialScore,attr,omitempty"`
+	UseReasonCodes       bool                  `xml:"useReasonCodes,attr"`
+	ReasonCodeAlgorithm  string                `xml:"reasonCodeAlgorithm,attr,omitempty"`
+	BaselineScore        *float64              `xml:"baselineScore,attr,omitempty"`
+	BaselineMethod       string                `xml:"baselineMethod,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	Characteristics      []Characteristic      `xml:"Characteristics>Characteristic"`
+}
+
+// UnmarshalXML ...
+func (s *Scorecard) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Scorecard
+	v := alias{UseReasonCodes: true, ReasonCodeAlgorithm: "pointsBelow"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*s = Scorecard(v)
+	return nil
+}
+
+// Characteristic ...
+type Characteristic struct {
+	Name          string      `xml:"name,attr,omitempty"`
+	ReasonCode    string      `xml:"reasonCode,attr,omitempty"`
+	BaselineScore *float64    `xml:"baselineScore,attr,omitempty"`
+	Extension     []Extension `xml:"Extension"`
+	Attributes    []Attribute `xml:"Attribute"`
+}
+
+// Attribute ...
+type Attribute struct {
+	ReasonCode          string
+	PartialScore        *float64
+	Predicate           *Predicate
+	ComplexPartialScore *Expression
+}
+
+// UnmarshalXML ...
+func (a *Attribute) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "reasonCode":
+			a.ReasonCode = attr.Value
+		case "partialScore":
+			score, err := strconv.ParseFloat(attr.Value, 64)
+			if err != nil {
+				return err
+			}
+			a.PartialScore = &score
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		if el.Name.Local != "ComplexPartialScore" {
+			a.Predicate = new(Predicate)
+			return d.DecodeElement(a.Predicate, &el)
+		}
+
+		return decodeChildren(d, func(el xml.StartElement) error {
+			a.ComplexPartialScore = new(Expression)
+			return a.ComplexPartialScore.UnmarshalXML(d, el)
+		})
+	})
+}
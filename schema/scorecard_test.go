@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScorecard(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/scorecard1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].Scorecard
+	assert.NotNil(t, model)
+	assert.Equal(t, "credit", out.Models[0].Name())
+	assert.True(t, model.UseReasonCodes)
+	assert.Equal(t, "pointsBelow", model.ReasonCodeAlgorithm)
+	assert.Len(t, model.Characteristics, 3)
+
+	income := model.Characteristics[2]
+	assert.Equal(t, "RC3", income.ReasonCode)
+	assert.Equal(t, 10.0, *income.BaselineScore)
+	assert.Nil(t, income.Attributes[2].PartialScore)
+	assert.Equal(t, "+", income.Attributes[2].ComplexPartialScore.Apply.Function)
+	assert.NotNil(t, income.Attributes[2].Predicate.CompoundPredicate)
+	assert.Equal(t, "RC4", income.Attributes[3].ReasonCode)
+	assert.Equal(t, 5.0, *income.Attributes[3].PartialScore)
+
+	named := out.Models[0].Named("score")
+	assert.Equal(t, "score", named.Name())
+}
+
+func TestScorecard_Defaults(t *testing.T) {
+	var out Scorecard
+	assert.NoError(t, xml.Unmarshal([]byte(`<Scorecard functionName="regression"/>`), &out))
+	assert.True(t, out.UseReasonCodes)
+	assert.Equal(t, "pointsBelow", out.ReasonCodeAlgorithm)
+
+	assert.Error(t, xml.Unmarshal([]byte(`<Attribute partialScore="x"><True/></Attribute>`), new(Attribute)))
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// MiningModel ...
+type MiningModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	Segmentation         *Segmentation         `xml:"Segmentation"`
+}
+
+// Segmentation ...
+type Segmentation struct {
+	MultipleModelMethod        string      `xml:"multipleModelMethod,attr"`
+	MissingPredictionTreatment string      `xml:"missingPredictionTreatment,attr,omitempty"`
+	MissingThreshold           *float64    `xml:"missingThreshold,attr"`
+	Extension                  []Extension `xml:"Extension"`
+	Segments                   []Segment   `xml:"Segment"`
+}
+
+// Segment ...
+type Segment struct {
+	ID             string
+	Weight         float64
+	VariableWeight *VariableWeight
+	Predicate      *Predicate
+	Model          Model
+}
+
+// VariableWeight ...
+type VariableWeight struct {
+	Field string `xml:"field,attr"`
+}
+
+// UnmarshalXML ...
+func (s *Segment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	s.Weight = 1
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			s.ID = attr.Value
+		case "weight":
+			if s.Weight, err = strconv.ParseFloat(attr.Value, 64); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		switch el.Name.Local {
+		case "SimplePredicate", "CompoundPredicate", "SimpleSetPredicate", "True", "False":
+			s.Predicate = new(Predicate)
+			return d.DecodeElement(s.Predicate, &el)
+		case "VariableWeight":
+			s.VariableWeight = new(VariableWeight)
+			return d.DecodeElement(s.VariableWeight, &el)
+		default:
+			return s.Model.UnmarshalXML(d, el)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiningModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/mining1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].MiningModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "forest", out.Models[0].Name())
+	assert.Equal(t, "average", model.Segmentation.MultipleModelMethod)
+	assert.Len(t, model.Segmentation.Segments, 3)
+
+	segment := model.Segmentation.Segments[2]
+	assert.Equal(t, "3", segment.ID)
+	assert.Equal(t, 3.0, segment.Weight)
+	assert.Equal(t, "x", segment.Predicate.SimplePredicate.Field)
+	assert.NotNil(t, segment.Model.TreeModel)
+	assert.Equal(t, 1.0, model.Segmentation.Segments[0].Weight)
+
+	named := out.Models[0].Named("ensemble")
+	assert.Equal(t, "ensemble", named.Name())
+	assert.Equal(t, "forest", out.Models[0].Name())
+}
+
+func TestSegment_VariableWeight(t *testing.T) {
+	var out Segment
+	assert.NoError(t, xml.Unmarshal([]byte(`<Segment id="1"><True/><VariableWeight field="w"/></Segment>`), &out))
+	assert.NotNil(t, out.VariableWeight)
+	assert.Equal(t, "w", out.VariableWeight.Field)
+	assert.Equal(t, 1.0, out.Weight)
+}
+
+func TestSegment_Invalid(t *testing.T) {
+	var out Segment
+	assert.Error(t, xml.Unmarshal([]byte(`<Segment weight="x"><True/></Segment>`), &out))
+	assert.Error(t, xml.Unmarshal([]byte(`<Segment><True/><UnknownModel/></Segment>`), &out))
+}
+
+func TestSegmentation_MissingThreshold(t *testing.T) {
+	var out Segmentation
+	assert.NoError(t, xml.Unmarshal([]byte(`<Segmentation multipleModelMethod="average"/>`), &out))
+	assert.Nil(t, out.MissingThreshold)
+
+	assert.NoError(t, xml.Unmarshal([]byte(`<Segmentation multipleModelMethod="average" missingThreshold="0"/>`), &out))
+	assert.NotNil(t, out.MissingThreshold)
+	assert.Equal(t, 0.0, *out.MissingThreshold)
+}
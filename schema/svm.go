@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// SupportVectorMachineModel ...
+type SupportVectorMachineModel struct {
+	ModelName                     string                 `xml:"modelName,attr,omitempty"`
+	FunctionName                  string                 `xml:"functionName,attr"`
+	AlgorithmName                 string                 `xml:"algorithmName,attr,omitempty"`
+	Threshold                     float64                `xml:"threshold,attr,omitempty"`
+	SvmRepresentation             string                 `xml:"svmRepresentation,attr,omitempty"`
+	ClassificationMethod          string                 `xml:"classificationMethod,attr,omitempty"`
+	MaxWins                       bool                   `xml:"maxWins,attr,omitempty"`
+	AlternateBinaryTargetCategory Value                  `xml:"alternateBinaryTargetCategory,attr,omitempty"`
+	Extension                     []Extension            `xml:"Extension"`
+	MiningSchema                  MiningSchema           `xml:"MiningSchema"`
+	Output                        *Output                `xml:"Output"`
+	LocalTransformations          *LocalTransformations  `xml:"LocalTransformations"`
+	LinearKernel                  *LinearKernelType      `xml:"LinearKernelType"`
+	PolynomialKernel              *PolynomialKernelType  `xml:"PolynomialKernelType"`
+	RadialBasisKernel             *RadialBasisKernelType `xml:"RadialBasisKernelType"`
+	SigmoidKernel                 *SigmoidKernelType     `xml:"SigmoidKernelType"`
+	VectorDictionary              VectorDictionary       `xml:"VectorDictionary"`
+	Machines                      []SupportVectorMachine `xml:"SupportVectorMachine"`
+}
+
+// UnmarshalXML ...
+func (m *SupportVectorMachineModel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias SupportVectorMachineModel
+	v := alias{SvmRepresentation: "SupportVectors", ClassificationMethod: "OneAgainstAll"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*m = SupportVectorMachineModel(v)
+	return nil
+}
+
+// LinearKernelType ...
+type LinearKernelType struct {
+	Description string `xml:"description,attr,omitempty"`
+}
+
+// PolynomialKernelType ...
+type PolynomialKernelType struct {
+	Description string  `xml:"description,attr,omitempty"`
+	Gamma       float64 `xml:"gamma,attr"`
+	Coef0       float64 `xml:"coef0,attr"`
+	Degree      float64 `xml:"degree,attr"`
+}
+
+// UnmarshalXML ...
+func (k *PolynomialKernelType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias PolynomialKernelType
+	v := alias{Gamma: 1, Coef0: 1, Degree: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = PolynomialKernelType(v)
+	return nil
+}
+
+// RadialBasisKernelType ...
+type RadialBasisKernelType struct {
+	Description string  `xml:"description,attr,omitempty"`
+	Gamma       float64 `xml:"gamma,attr"`
+}
+
+// UnmarshalXML ...
+func (k *RadialBasisKernelType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias RadialBasisKernelType
+	v := alias{Gamma: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = RadialBasisKernelType(v)
+	return nil
+}
+
+// SigmoidKernelType ...
+type SigmoidKernelType struct {
+	Description string  `xml:"description,attr,omitempty"`
+	Gamma       float64 `xml:"gamma,attr"`
+	Coef0       float64 `xml:"coef0,attr"`
+}
+
+// UnmarshalXML ...
+func (k *SigmoidKernelType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias SigmoidKernelType
+	v := alias{Gamma: 1, Coef0: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*k = SigmoidKernelType(v)
+	return nil
+}
+
+// VectorDictionary ...
+type VectorDictionary struct {
+	NumberOfVectors int              `xml:"numberOfVectors,attr,omitempty"`
+	Extension       []Extension      `xml:"Extension"`
+	Fields          []FieldRef       `xml:"VectorFields>FieldRef"`
+	Instances       []VectorInstance `xml:"VectorInstance"`
+}
+
+// VectorInstance ...
+type VectorInstance struct {
+	ID          string       `xml:"id,attr"`
+	Extension   []Extension  `xml:"Extension"`
+	Array       *Array       `xml:"Array"`
+	SparseArray *SparseArray `xml:"REAL-SparseArray"`
+}
+
+// Floats returns the values of the vector, whether it is dense or sparse. The size is the number
+// of vector fields, which is the length of the sparse vectors which do not declare it.
+func (v VectorInstance) Floats(size int) ([]float64, error) {
+	if v.SparseArray != nil {
+		return v.SparseArray.Floats(size)
+	}
+	if v.Array != nil {
+		return v.Array.Floats()
+	}
+	return nil, nil
+}
+
+// SupportVectorMachine ...
+type SupportVectorMachine struct {
+	TargetCategory          Value           `xml:"targetCategory,attr,omitempty"`
+	AlternateTargetCategory Value           `xml:"alternateTargetCategory,attr,omitempty"`
+	Threshold               *float64        `xml:"threshold,attr,omitempty"`
+	Extension               []Extension     `xml:"Extension"`
+	SupportVectors          []SupportVector `xml:"SupportVectors>SupportVector"`
+	Coefficients            Coefficients    `xml:"Coefficients"`
+}
+
+// SupportVector ...
+type SupportVector struct {
+	VectorID string `xml:"vectorId,attr"`
+}
+
+// Coefficients ...
+type Coefficients struct {
+	AbsoluteValue float64       `xml:"absoluteValue,attr,omitempty"`
+	Coefficients  []Coefficient `xml:"Coefficient"`
+}
+
+// Coefficient ...
+type Coefficient struct {
+	Value float64 `xml:"value,attr"`
+}
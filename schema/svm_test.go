@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportVectorMachineModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/svm1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].SupportVectorMachineModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "fraud", out.Models[0].Name())
+	assert.Equal(t, "OneAgainstOne", model.ClassificationMethod)
+	assert.Equal(t, "SupportVectors", model.SvmRepresentation)
+	assert.Equal(t, 0.5, model.RadialBasisKernel.Gamma)
+	assert.Nil(t, model.LinearKernel)
+
+	dict := model.VectorDictionary
+	assert.Equal(t, []FieldRef{{Field: "x1"}, {Field: "x2"}}, dict.Fields)
+	for i, expect := range [][]float64{{1, 0}, {0, 1.5}, {-1, -0.5}} {
+		v, err := dict.Instances[i].Floats(len(dict.Fields))
+		assert.NoError(t, err)
+		assert.Equal(t, expect, v)
+	}
+
+	assert.Len(t, model.Machines, 3)
+	assert.Equal(t, Value("c"), model.Machines[1].AlternateTargetCategory)
+	assert.Equal(t, "3", model.Machines[1].SupportVectors[1].VectorID)
+	assert.Equal(t, -0.2, model.Machines[1].Coefficients.AbsoluteValue)
+	assert.Equal(t, 0.7, model.Machines[1].Coefficients.Coefficients[1].Value)
+
+	named := out.Models[0].Named("svm")
+	assert.Equal(t, "svm", named.Name())
+}
+
+func TestKernelType_Defaults(t *testing.T) {
+	var polynomial PolynomialKernelType
+	assert.NoError(t, xml.Unmarshal([]byte(`<PolynomialKernelType degree="3"/>`), &polynomial))
+	assert.Equal(t, PolynomialKernelType{Gamma: 1, Coef0: 1, Degree: 3}, polynomial)
+
+	var sigmoid SigmoidKernelType
+	assert.NoError(t, xml.Unmarshal([]byte(`<SigmoidKernelType coef0="0"/>`), &sigmoid))
+	assert.Equal(t, SigmoidKernelType{Gamma: 1}, sigmoid)
+
+	var rbf RadialBasisKernelType
+	assert.NoError(t, xml.Unmarshal([]byte(`<RadialBasisKernelType/>`), &rbf))
+	assert.Equal(t, 1.0, rbf.Gamma)
+}
+
+func TestSparseArray(t *testing.T) {
+	var out SparseArray
+	assert.NoError(t, xml.Unmarshal([]byte(`<REAL-SparseArray n="5" defaultValue="0.5"><Indices>1 4</Indices><REAL-Entries>2 3.5</REAL-Entries></REAL-SparseArray>`), &out))
+
+	v, err := out.Floats(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 0.5, 0.5, 3.5, 0.5}, v)
+
+	// Without its length, the array has the specified size
+	var unsized SparseArray
+	assert.NoError(t, xml.Unmarshal([]byte(`<REAL-SparseArray><Indices>1 3</Indices><REAL-Entries>2 3.5</REAL-Entries></REAL-SparseArray>`), &unsized))
+	v, err = unsized.Floats(4)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 0, 3.5, 0}, v)
+
+	_, err = unsized.Floats(2)
+	assert.ErrorContains(t, err, "sparse array index 3 is out of range")
+
+	for _, tc := range []struct {
+		array SparseArray
+		err   string
+	}{
+		{array: SparseArray{Length: 2, Indices: "1 2", Entries: "1"}, err: "sparse array has 2 indices but 1 entries"},
+		{array: SparseArray{Length: 2, Indices: "x", Entries: "1"}, err: "invalid syntax"},
+		{array: SparseArray{Length: 2, Indices: "3", Entries: "1"}, err: "sparse array index 3 is out of range"},
+		{array: SparseArray{Length: 2, Indices: "1", Entries: "x"}, err: "invalid syntax"},
+	} {
+		_, err := tc.array.Floats(0)
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// InlineTable ...
+type InlineTable struct {
+	Extension []Extension `xml:"Extension"`
+	Rows      []Row       `xml:"row"`
+}
+
+// Row represents a row of a table, with the values of its cells by the name of their column.
+type Row map[string]string
+
+// UnmarshalXML ...
+func (r *Row) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*r = make(Row)
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch el := t.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &el); err != nil {
+				return err
+			}
+
+			// The columns are often qualified with a namespace prefix which is not significant
+			(*r)[el.Name.Local] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// Get returns the value of the cell of the column. The namespace prefix of the column, if any,
+// is ignored since the cells are stored by their local name.
+func (r Row) Get(column string) (string, bool) {
+	if i := strings.LastIndex(column, ":"); i >= 0 {
+		column = column[i+1:]
+	}
+
+	value, ok := r[column]
+	return value, ok
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// TextModel ...
+type TextModel struct {
+	ModelName            string                 `xml:"modelName,attr,omitempty"`
+	FunctionName         string                 `xml:"functionName,attr"`
+	AlgorithmName        string                 `xml:"algorithmName,attr,omitempty"`
+	NumberOfTerms        int                    `xml:"numberOfTerms,attr"`
+	NumberOfDocuments    int                    `xml:"numberOfDocuments,attr"`
+	Extension            []Extension            `xml:"Extension"`
+	MiningSchema         MiningSchema           `xml:"MiningSchema"`
+	Output               *Output                `xml:"Output"`
+	LocalTransformations *LocalTransformations  `xml:"LocalTransformations"`
+	TextDictionary       TextDictionary         `xml:"TextDictionary"`
+	TextCorpus           []TextDocument         `xml:"TextCorpus>TextDocument"`
+	DocumentTermMatrix   Matrix                 `xml:"DocumentTermMatrix>Matrix"`
+	Normalization        TextModelNormalization `xml:"TextModelNormalization"`
+	Similarity           TextModelSimilarity    `xml:"TextModelSimiliarity"`
+}
+
+// UnmarshalXML ...
+func (m *TextModel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias TextModel
+	v := alias{
+		Normalization: TextModelNormalization{
+			LocalTermWeights:      "termFrequency",
+			GlobalTermWeights:     "inverseDocumentFrequency",
+			DocumentNormalization: "none",
+		},
+		Similarity: TextModelSimilarity{SimilarityType: "cosine"},
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*m = TextModel(v)
+	return nil
+}
+
+// TextDictionary ...
+type TextDictionary struct {
+	Extension []Extension `xml:"Extension"`
+	Terms     Array       `xml:"Array"`
+}
+
+// TextDocument ...
+type TextDocument struct {
+	ID     string `xml:"id,attr"`
+	Name   string `xml:"name,attr,omitempty"`
+	Length int    `xml:"length,attr,omitempty"`
+	File   string `xml:"file,attr,omitempty"`
+}
+
+// TextModelNormalization ...
+type TextModelNormalization struct {
+	Extension             []Extension `xml:"Extension"`
+	LocalTermWeights      string      `xml:"localTermWeights,attr"`
+	GlobalTermWeights     string      `xml:"globalTermWeights,attr"`
+	DocumentNormalization string      `xml:"documentNormalization,attr"`
+}
+
+// UnmarshalXML ...
+func (n *TextModelNormalization) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias TextModelNormalization
+	v := alias{LocalTermWeights: "termFrequency", GlobalTermWeights: "inverseDocumentFrequency", DocumentNormalization: "none"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*n = TextModelNormalization(v)
+	return nil
+}
+
+// TextModelSimilarity represents the element, whose name is misspelled as TextModelSimiliarity
+// by the standard.
+type TextModelSimilarity struct {
+	Extension      []Extension `xml:"Extension"`
+	SimilarityType string      `xml:"similarityType,attr"`
+}
@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextModel(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/text1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].TextModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "articles", out.Models[0].Name())
+	assert.Equal(t, 4, model.NumberOfTerms)
+	assert.Equal(t, 3, model.NumberOfDocuments)
+	assert.Len(t, model.TextCorpus, 3)
+	assert.Equal(t, TextDocument{ID: "a2", Name: "Learning Machines", Length: 80, File: "a2.txt"}, model.TextCorpus[1])
+	assert.Equal(t, "cosine", model.Normalization.DocumentNormalization)
+	assert.Equal(t, "cosine", model.Similarity.SimilarityType)
+
+	terms, err := model.TextDictionary.Terms.Strings()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"data", "model", "machine learning", "football"}, terms)
+
+	matrix, err := model.DocumentTermMatrix.Floats()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 1, 4, 0}, matrix[1])
+
+	named := out.Models[0].Named("corpus")
+	assert.Equal(t, "corpus", named.Name())
+	assert.Equal(t, "articles", out.Models[0].Name())
+}
+
+func TestTextModel_Defaults(t *testing.T) {
+	var out TextModel
+	assert.NoError(t, xml.Unmarshal([]byte(`<TextModel numberOfTerms="0" numberOfDocuments="0"/>`), &out))
+	assert.Equal(t, "termFrequency", out.Normalization.LocalTermWeights)
+	assert.Equal(t, "inverseDocumentFrequency", out.Normalization.GlobalTermWeights)
+	assert.Equal(t, "none", out.Normalization.DocumentNormalization)
+	assert.Equal(t, "cosine", out.Similarity.SimilarityType)
+
+	assert.NoError(t, xml.Unmarshal([]byte(`<TextModel numberOfTerms="0" numberOfDocuments="0">
+		<TextModelNormalization localTermWeights="binary"/>
+	</TextModel>`), &out))
+	assert.Equal(t, "binary", out.Normalization.LocalTermWeights)
+	assert.Equal(t, "inverseDocumentFrequency", out.Normalization.GlobalTermWeights)
+	assert.Equal(t, "none", out.Normalization.DocumentNormalization)
+}
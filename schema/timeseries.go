@@ -0,0 +1,212 @@
+package schema
+
+import (
+	"encoding/xml"
+)
+
+// TimeSeriesModel ...
+type TimeSeriesModel struct {
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	BestFit              string                `xml:"bestFit,attr"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	TimeSeries           []TimeSeries          `xml:"TimeSeries"`
+	ARIMA                *ARIMA                `xml:"ARIMA"`
+	ExponentialSmoothing *ExponentialSmoothing `xml:"ExponentialSmoothing"`
+}
+
+// Series returns the original time series of the model, or the first one if none of them is
+// declared as the original.
+func (m TimeSeriesModel) Series() *TimeSeries {
+	for i := range m.TimeSeries {
+		if m.TimeSeries[i].Usage == "original" {
+			return &m.TimeSeries[i]
+		}
+	}
+
+	if len(m.TimeSeries) > 0 {
+		return &m.TimeSeries[0]
+	}
+	return nil
+}
+
+// TimeSeries ...
+type TimeSeries struct {
+	Usage               string      `xml:"usage,attr"`
+	StartTime           float64     `xml:"startTime,attr,omitempty"`
+	EndTime             float64     `xml:"endTime,attr,omitempty"`
+	InterpolationMethod string      `xml:"interpolationMethod,attr,omitempty"`
+	Field               string      `xml:"field,attr,omitempty"`
+	TimeValues          []TimeValue `xml:"TimeValue"`
+}
+
+// UnmarshalXML ...
+func (t *TimeSeries) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias TimeSeries
+	v := alias{Usage: "original"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*t = TimeSeries(v)
+	return nil
+}
+
+// TimeValue ...
+type TimeValue struct {
+	Index         int     `xml:"index,attr,omitempty"`
+	Time          float64 `xml:"time,attr,omitempty"`
+	Value         float64 `xml:"value,attr"`
+	StandardError float64 `xml:"standardError,attr,omitempty"`
+}
+
+// ----------------------------------------------------------------------------
+
+// ExponentialSmoothing ...
+type ExponentialSmoothing struct {
+	RMSE           float64                `xml:"RMSE,attr,omitempty"`
+	Transformation string                 `xml:"transformation,attr,omitempty"`
+	Level          Level                  `xml:"Level"`
+	Trend          *TrendExpoSmooth       `xml:"Trend_ExpoSmooth"`
+	Seasonality    *SeasonalityExpoSmooth `xml:"Seasonality_ExpoSmooth"`
+	TimeValues     []TimeValue            `xml:"TimeValue"`
+}
+
+// UnmarshalXML ...
+func (e *ExponentialSmoothing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias ExponentialSmoothing
+	v := alias{Transformation: "none"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*e = ExponentialSmoothing(v)
+	return nil
+}
+
+// Level ...
+type Level struct {
+	Alpha         float64 `xml:"alpha,attr,omitempty"`
+	SmoothedValue float64 `xml:"smoothedValue,attr"`
+}
+
+// TrendExpoSmooth ...
+type TrendExpoSmooth struct {
+	Trend         string  `xml:"trend,attr"`
+	Gamma         float64 `xml:"gamma,attr,omitempty"`
+	Phi           float64 `xml:"phi,attr"`
+	SmoothedValue float64 `xml:"smoothedValue,attr"`
+	Array         *Array  `xml:"Array"`
+}
+
+// UnmarshalXML ...
+func (t *TrendExpoSmooth) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias TrendExpoSmooth
+	v := alias{Trend: "additive", Phi: 1}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*t = TrendExpoSmooth(v)
+	return nil
+}
+
+// SeasonalityExpoSmooth ...
+type SeasonalityExpoSmooth struct {
+	Type   string  `xml:"type,attr"`
+	Period int     `xml:"period,attr"`
+	Unit   string  `xml:"unit,attr,omitempty"`
+	Phase  int     `xml:"phase,attr,omitempty"`
+	Delta  float64 `xml:"delta,attr,omitempty"`
+	Array  *Array  `xml:"Array"`
+}
+
+// ----------------------------------------------------------------------------
+
+// ARIMA ...
+type ARIMA struct {
+	RMSE                  float64                `xml:"RMSE,attr,omitempty"`
+	Transformation        string                 `xml:"transformation,attr,omitempty"`
+	ConstantTerm          float64                `xml:"constantTerm,attr,omitempty"`
+	PredictionMethod      string                 `xml:"predictionMethod,attr,omitempty"`
+	Extension             []Extension            `xml:"Extension"`
+	NonseasonalComponent  *NonseasonalComponent  `xml:"NonseasonalComponent"`
+	SeasonalComponent     *SeasonalComponent     `xml:"SeasonalComponent"`
+	MaximumLikelihoodStat *MaximumLikelihoodStat `xml:"MaximumLikelihoodStat"`
+}
+
+// UnmarshalXML ...
+func (a *ARIMA) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias ARIMA
+	v := alias{Transformation: "none", PredictionMethod: "conditionalLeastSquares"}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*a = ARIMA(v)
+	return nil
+}
+
+// NonseasonalComponent ...
+type NonseasonalComponent struct {
+	P  int `xml:"p,attr,omitempty"`
+	D  int `xml:"d,attr,omitempty"`
+	Q  int `xml:"q,attr,omitempty"`
+	AR *AR `xml:"AR"`
+	MA *MA `xml:"MA"`
+}
+
+// SeasonalComponent ...
+type SeasonalComponent struct {
+	P      int `xml:"P,attr,omitempty"`
+	D      int `xml:"D,attr,omitempty"`
+	Q      int `xml:"Q,attr,omitempty"`
+	Period int `xml:"period,attr"`
+	AR     *AR `xml:"AR"`
+	MA     *MA `xml:"MA"`
+}
+
+// AR ...
+type AR struct {
+	Array Array `xml:"Array"`
+}
+
+// MA ...
+type MA struct {
+	Coefficients *Array `xml:"MACoefficients>Array"`
+	Residuals    *Array `xml:"Residuals>Array"`
+}
+
+// MaximumLikelihoodStat ...
+type MaximumLikelihoodStat struct {
+	Method              string               `xml:"method,attr"`
+	PeriodDeficit       int                  `xml:"periodDeficit,attr,omitempty"`
+	KalmanState         *KalmanState         `xml:"KalmanState"`
+	ThetaRecursionState *ThetaRecursionState `xml:"ThetaRecursionState"`
+}
+
+// KalmanState ...
+type KalmanState struct {
+	FinalOmega       []Array `xml:"FinalOmega>Matrix>Array"`
+	FinalStateVector Array   `xml:"FinalStateVector>Array"`
+	HVector          *Array  `xml:"HVector>Array"`
+}
+
+// ThetaRecursionState ...
+type ThetaRecursionState struct {
+	FinalNoise          Array   `xml:"FinalNoise>Array"`
+	FinalPredictedNoise Array   `xml:"FinalPredictedNoise>Array"`
+	FinalTheta          []Theta `xml:"FinalTheta>Theta"`
+	FinalNu             Array   `xml:"FinalNu>Array"`
+}
+
+// Theta ...
+type Theta struct {
+	I     int     `xml:"i,attr"`
+	J     int     `xml:"j,attr"`
+	Theta float64 `xml:"theta,attr"`
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeSeriesModel_ExponentialSmoothing(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/timeseries1.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].TimeSeriesModel
+	assert.NotNil(t, model)
+	assert.Equal(t, "capacity", out.Models[0].Name())
+	assert.Equal(t, "ExponentialSmoothing", model.BestFit)
+	assert.Len(t, model.Series().TimeValues, 4)
+
+	smoothing := model.ExponentialSmoothing
+	assert.NotNil(t, smoothing)
+	assert.Equal(t, "none", smoothing.Transformation)
+	assert.Equal(t, Level{Alpha: 0.3, SmoothedValue: 120}, smoothing.Level)
+	assert.Equal(t, TrendExpoSmooth{Trend: "damped_additive", Gamma: 0.1, Phi: 0.8, SmoothedValue: 5}, *smoothing.Trend)
+	assert.Equal(t, "multiplicative", smoothing.Seasonality.Type)
+	assert.Equal(t, 4, smoothing.Seasonality.Period)
+	assert.Equal(t, "0.9 1.1 1.2 0.8", smoothing.Seasonality.Array.Values)
+
+	named := out.Models[0].Named("forecast")
+	assert.Equal(t, "forecast", named.Name())
+}
+
+func TestTimeSeriesModel_ARIMA(t *testing.T) {
+	b, err := ioutil.ReadFile("../fixtures/timeseries2.xml")
+	assert.NoError(t, err)
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal(b, &out))
+
+	model := out.Models[0].TimeSeriesModel
+	arima := model.ARIMA
+	assert.NotNil(t, arima)
+	assert.Equal(t, 0.1, arima.ConstantTerm)
+	assert.Equal(t, "conditionalLeastSquares", arima.PredictionMethod)
+	assert.Equal(t, 1, arima.NonseasonalComponent.P)
+	assert.Equal(t, 1, arima.NonseasonalComponent.D)
+	assert.Equal(t, "0.5", arima.NonseasonalComponent.AR.Array.Values)
+	assert.Equal(t, "0.3", arima.NonseasonalComponent.MA.Coefficients.Values)
+	assert.Equal(t, 4, arima.SeasonalComponent.Period)
+	assert.Equal(t, 1, arima.SeasonalComponent.Q)
+	assert.Equal(t, "-0.4 0.5 -0.3 0.1 0.2", arima.SeasonalComponent.MA.Residuals.Values)
+}
+
+func TestTimeSeriesModel_Defaults(t *testing.T) {
+	var out TimeSeriesModel
+	assert.NoError(t, xml.Unmarshal([]byte(`<TimeSeriesModel bestFit="ARIMA">
+		<TimeSeries usage="logical"><TimeValue index="1" value="1"/></TimeSeries>
+		<TimeSeries><TimeValue index="1" value="2"/></TimeSeries>
+		<ARIMA>
+			<MaximumLikelihoodStat method="kalman">
+				<KalmanState>
+					<FinalOmega><Matrix><Array type="real">1 0</Array><Array type="real">0 1</Array></Matrix></FinalOmega>
+					<FinalStateVector><Array type="real">0.5 0.2</Array></FinalStateVector>
+					<HVector><Array type="real">1 0</Array></HVector>
+				</KalmanState>
+			</MaximumLikelihoodStat>
+		</ARIMA>
+		<ExponentialSmoothing><Level smoothedValue="1"/><Trend_ExpoSmooth smoothedValue="2"/></ExponentialSmoothing>
+	</TimeSeriesModel>`), &out))
+
+	assert.Equal(t, 2.0, out.Series().TimeValues[0].Value)
+	assert.Equal(t, "none", out.ARIMA.Transformation)
+	assert.Equal(t, "conditionalLeastSquares", out.ARIMA.PredictionMethod)
+	assert.Len(t, out.ARIMA.MaximumLikelihoodStat.KalmanState.FinalOmega, 2)
+	assert.Equal(t, "0.5 0.2", out.ARIMA.MaximumLikelihoodStat.KalmanState.FinalStateVector.Values)
+	assert.Equal(t, "1 0", out.ARIMA.MaximumLikelihoodStat.KalmanState.HVector.Values)
+	assert.Equal(t, TrendExpoSmooth{Trend: "additive", Phi: 1, SmoothedValue: 2}, *out.ExponentialSmoothing.Trend)
+	assert.Nil(t, TimeSeriesModel{}.Series())
+}
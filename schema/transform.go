@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// TransformationDictionary ...
+type TransformationDictionary struct {
+	Extension       []Extension      `xml:"Extension"`
+	DefineFunctions []DefineFunction `xml:"DefineFunction"`
+	DerivedFields   []DerivedField   `xml:"DerivedField"`
+}
+
+// LocalTransformations ...
+type LocalTransformations struct {
+	Extension     []Extension    `xml:"Extension"`
+	DerivedFields []DerivedField `xml:"DerivedField"`
+}
+
+// DefineFunction ...
+type DefineFunction struct {
+	Name            string
+	Optype          string
+	DataType        string
+	ParameterFields []ParameterField
+	Expression      *Expression
+}
+
+// UnmarshalXML ...
+func (f *DefineFunction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			f.Name = attr.Value
+		case "optype":
+			f.Optype = attr.Value
+		case "dataType":
+			f.DataType = attr.Value
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		if el.Name.Local == "ParameterField" {
+			var param ParameterField
+			if err := d.DecodeElement(&param, &el); err != nil {
+				return err
+			}
+			f.ParameterFields = append(f.ParameterFields, param)
+			return nil
+		}
+
+		f.Expression = new(Expression)
+		return f.Expression.UnmarshalXML(d, el)
+	})
+}
+
+// ParameterField ...
+type ParameterField struct {
+	Name        string `xml:"name,attr"`
+	Optype      string `xml:"optype,attr,omitempty"`
+	DataType    string `xml:"dataType,attr,omitempty"`
+	DisplayName string `xml:"displayName,attr,omitempty"`
+}
+
+// DerivedField ...
+type DerivedField struct {
+	Name        string
+	DisplayName string
+	Optype      string
+	DataType    string
+	Expression  *Expression
+}
+
+// UnmarshalXML ...
+func (f *DerivedField) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			f.Name = attr.Value
+		case "displayName":
+			f.DisplayName = attr.Value
+		case "optype":
+			f.Optype = attr.Value
+		case "dataType":
+			f.DataType = attr.Value
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		f.Expression = new(Expression)
+		return f.Expression.UnmarshalXML(d, el)
+	})
+}
+
+// ----------------------------------------------------------------------------
+
+// Expression ...
+type Expression struct {
+	Constant       *Constant
+	FieldRef       *FieldRef
+	NormContinuous *NormContinuous
+	NormDiscrete   *NormDiscrete
+	Discretize     *Discretize
+	Apply          *Apply
+}
+
+// UnmarshalXML ...
+func (e *Expression) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Local {
+	case "Constant":
+		e.Constant = new(Constant)
+		return d.DecodeElement(e.Constant, &start)
+	case "FieldRef":
+		e.FieldRef = new(FieldRef)
+		return d.DecodeElement(e.FieldRef, &start)
+	case "NormContinuous":
+		e.NormContinuous = new(NormContinuous)
+		return d.DecodeElement(e.NormContinuous, &start)
+	case "NormDiscrete":
+		e.NormDiscrete = new(NormDiscrete)
+		return d.DecodeElement(e.NormDiscrete, &start)
+	case "Discretize":
+		e.Discretize = new(Discretize)
+		return d.DecodeElement(e.Discretize, &start)
+	case "Apply":
+		e.Apply = new(Apply)
+		return d.DecodeElement(e.Apply, &start)
+	default:
+		return fmt.Errorf("unsupported expression type %s", start.Name.Local)
+	}
+}
+
+// Constant ...
+type Constant struct {
+	DataType string `xml:"dataType,attr,omitempty"`
+	Missing  bool   `xml:"missing,attr,omitempty"`
+	Value    Value  `xml:",chardata"`
+}
+
+// FieldRef ...
+type FieldRef struct {
+	Field        string `xml:"field,attr"`
+	MapMissingTo Value  `xml:"mapMissingTo,attr,omitempty"`
+}
+
+// NormContinuous ...
+type NormContinuous struct {
+	Field        string       `xml:"field,attr"`
+	MapMissingTo Value        `xml:"mapMissingTo,attr,omitempty"`
+	Outliers     string       `xml:"outliers,attr,omitempty"`
+	LinearNorms  []LinearNorm `xml:"LinearNorm"`
+}
+
+// LinearNorm ...
+type LinearNorm struct {
+	Orig float64 `xml:"orig,attr"`
+	Norm float64 `xml:"norm,attr"`
+}
+
+// NormDiscrete ...
+type NormDiscrete struct {
+	Field        string `xml:"field,attr"`
+	Value        Value  `xml:"value,attr"`
+	MapMissingTo Value  `xml:"mapMissingTo,attr,omitempty"`
+}
+
+// Discretize ...
+type Discretize struct {
+	Field        string          `xml:"field,attr"`
+	MapMissingTo Value           `xml:"mapMissingTo,attr,omitempty"`
+	DefaultValue Value           `xml:"defaultValue,attr,omitempty"`
+	DataType     string          `xml:"dataType,attr,omitempty"`
+	Bins         []DiscretizeBin `xml:"DiscretizeBin"`
+}
+
+// DiscretizeBin ...
+type DiscretizeBin struct {
+	BinValue Value    `xml:"binValue,attr"`
+	Interval Interval `xml:"Interval"`
+}
+
+// Apply ...
+type Apply struct {
+	Function              string
+	MapMissingTo          Value
+	DefaultValue          Value
+	InvalidValueTreatment string
+	Expressions           []Expression
+}
+
+// UnmarshalXML ...
+func (a *Apply) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "function":
+			a.Function = attr.Value
+		case "mapMissingTo":
+			a.MapMissingTo = Value(attr.Value)
+		case "defaultValue":
+			a.DefaultValue = Value(attr.Value)
+		case "invalidValueTreatment":
+			a.InvalidValueTreatment = attr.Value
+		}
+	}
+
+	return decodeChildren(d, func(el xml.StartElement) error {
+		var expr Expression
+		if err := expr.UnmarshalXML(d, el); err != nil {
+			return err
+		}
+		a.Expressions = append(a.Expressions, expr)
+		return nil
+	})
+}
@@ -2,26 +2,27 @@ package schema
 
 import (
 	"encoding/xml"
-	"io"
+	"fmt"
 	"strconv"
 )
 
 // DecisionTree ...
 type DecisionTree struct {
-	ModelName            string      `xml:"modelName,attr,omitempty"`
-	FunctionName         string      `xml:"functionName,attr"`
-	AlgorithmName        string      `xml:"algorithmName,attr,omitempty"`
-	MissingValueStrategy string      `xml:"missingValueStrategy,attr,omitempty"`
-	MissingValuePenalty  float64     `xml:"missingValuePenalty,attr,omitempty"`
-	NoTrueChildStrategy  string      `xml:"noTrueChildStrategy,attr,omitempty"`
-	Extension            []Extension `xml:"Extension"`
-	Node                 Node        `xml:"Node"`
+	ModelName            string                `xml:"modelName,attr,omitempty"`
+	FunctionName         string                `xml:"functionName,attr"`
+	AlgorithmName        string                `xml:"algorithmName,attr,omitempty"`
+	MissingValueStrategy string                `xml:"missingValueStrategy,attr,omitempty"`
+	MissingValuePenalty  float64               `xml:"missingValuePenalty,attr,omitempty"`
+	NoTrueChildStrategy  string                `xml:"noTrueChildStrategy,attr,omitempty"`
+	Extension            []Extension           `xml:"Extension"`
+	MiningSchema         MiningSchema          `xml:"MiningSchema"`
+	Output               *Output               `xml:"Output"`
+	LocalTransformations *LocalTransformations `xml:"LocalTransformations"`
+	Node                 Node                  `xml:"Node"`
 
 	//SplitCharacteristicAttr  interface{}           `xml:"splitCharacteristic,attr,omitempty"`
-	//Output                   *Output               `xml:"Output"`
 	//ModelStats               *ModelStats           `xml:"ModelStats"`
 	//Targets                  *Targets              `xml:"Targets"`
-	//LocalTransformations     *LocalTransformations `xml:"LocalTransformations"`
 	//ResultField              []*ResultField        `xml:"ResultField"`
 }
 
@@ -56,38 +57,35 @@ func (n *Node) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error)
 		}
 	}
 
-	var t xml.Token
-	for t, err = d.Token(); t != nil && err != io.EOF; t, err = d.Token() {
-		if err != nil {
-			return err
-		}
+	return decodeChildren(d, func(el xml.StartElement) error {
+		switch el.Name.Local {
+		case "Node":
+			var child Node
+			if err := d.DecodeElement(&child, &el); err != nil {
+				return err
+			}
+			n.Nodes = append(n.Nodes, child)
+			return nil
 
-		if el, ok := t.(xml.StartElement); ok {
-			switch el.Name.Local {
-			case "Node":
-				var child Node
-				if err := d.DecodeElement(&child, &el); err != nil {
-					return err
-				}
-				n.Nodes = append(n.Nodes, child)
+		case "ScoreDistribution":
+			var dist ScoreDistribution
+			if err := d.DecodeElement(&dist, &el); err != nil {
+				return err
+			}
+			n.Distributions = append(n.Distributions, dist)
+			return nil
 
-			case "ScoreDistribution":
-				var dist ScoreDistribution
-				if err := d.DecodeElement(&dist, &el); err != nil {
-					return err
-				}
-				n.Distributions = append(n.Distributions, dist)
+		case "Partition":
+			return d.Skip()
 
-			default:
-				n.Predicate = new(Predicate)
-				if err := d.DecodeElement(n.Predicate, &el); err != nil {
-					return err
-				}
-			}
-		}
-	}
+		case "EmbeddedModel", "Regression", "DecisionTree":
+			return fmt.Errorf("embedded models are not supported, node %s contains a %s", n.ID, el.Name.Local)
 
-	return nil
+		default:
+			n.Predicate = new(Predicate)
+			return d.DecodeElement(n.Predicate, &el)
+		}
+	})
 }
 
 // ScoreDistribution ...
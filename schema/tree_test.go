@@ -62,3 +62,16 @@ func TestNode(t *testing.T) {
 		},
 	}, out)
 }
+
+func TestNode_EmbeddedModel(t *testing.T) {
+	for _, model := range []string{"Regression", "DecisionTree"} {
+		input := `<Node id="1" score="a"><True/><` + model + ` functionName="classification"/></Node>`
+
+		var out Node
+		assert.EqualError(t, xml.Unmarshal([]byte(input), &out),
+			"embedded models are not supported, node 1 contains a "+model)
+	}
+
+	var out Node
+	assert.NoError(t, xml.Unmarshal([]byte(`<Node id="1"><True/><Partition name="p"/></Node>`), &out))
+}
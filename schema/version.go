@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The prefix of the namespaces of all of the versions of the standard, for example
+// http://www.dmg.org/PMML-4_4 for the version 4.4.
+const namespacePrefix = "http://www.dmg.org/PMML-"
+
+// The versions of the standard which can be decoded.
+var versions = map[string]bool{
+	"3.0": true, "3.1": true, "3.2": true,
+	"4.0": true, "4.1": true, "4.2": true, "4.3": true, "4.4": true,
+}
+
+// The elements which were introduced after the version 3.0 of the standard.
+var introduced = map[string]string{
+	"TimeSeriesModel":       "4.0",
+	"BaselineModel":         "4.1",
+	"NearestNeighborModel":  "4.1",
+	"Scorecard":             "4.1",
+	"BayesianNetworkModel":  "4.3",
+	"GaussianProcessModel":  "4.3",
+	"AnomalyDetectionModel": "4.4",
+}
+
+// versionOf returns the version of the standard declared by the root element, either through
+// its version attribute or, if the attribute is not present, through its namespace.
+func versionOf(start xml.StartElement) (string, error) {
+	if !isNamespace(start.Name.Space) {
+		return "", fmt.Errorf("unsupported namespace %s", start.Name.Space)
+	}
+
+	version := strings.Replace(strings.TrimPrefix(start.Name.Space, namespacePrefix), "_", ".", 1)
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "version" && attr.Name.Space == "" {
+			version = attr.Value
+		}
+	}
+
+	if version == "" {
+		return "", fmt.Errorf("PMML version is not specified")
+	}
+
+	if !versions[version] {
+		return "", fmt.Errorf("unsupported PMML version %s", version)
+	}
+	return version, nil
+}
+
+// isNamespace checks whether the namespace is one of the namespaces of the standard. Elements
+// without any namespace are accepted as well, since older documents often omit it.
+func isNamespace(space string) bool {
+	return space == "" || strings.HasPrefix(space, namespacePrefix)
+}
+
+// supports checks whether the element exists in the specified version of the standard.
+func supports(version, element string) error {
+	since, ok := introduced[element]
+	if ok && compareVersion(version, since) < 0 {
+		return fmt.Errorf("%s requires PMML %s, but the document declares version %s", element, since, version)
+	}
+	return nil
+}
+
+// compareVersion compares two versions of the standard and returns -1, 0 or 1.
+func compareVersion(a, b string) int {
+	am, an := splitVersion(a)
+	bm, bn := splitVersion(b)
+	switch {
+	case am < bm || (am == bm && an < bn):
+		return -1
+	case am == bm && an == bn:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// splitVersion splits the version into its major and minor parts.
+func splitVersion(version string) (major, minor int) {
+	parts := strings.SplitN(version, ".", 2)
+	major, _ = strconv.Atoi(parts[0])
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	return
+}
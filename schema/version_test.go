@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion(t *testing.T) {
+	td := []struct {
+		xml     string // The XML document to parse
+		version string // The expected version
+		err     bool   // Whether an error is expected
+	}{
+		{xml: `<PMML xmlns="http://www.dmg.org/PMML-4_4" version="4.4"/>`, version: "4.4"},
+		{xml: `<PMML xmlns="http://www.dmg.org/PMML-4_3" version="4.3"/>`, version: "4.3"},
+		{xml: `<PMML xmlns="http://www.dmg.org/PMML-4_2"/>`, version: "4.2"},
+		{xml: `<PMML xmlns="http://www.dmg.org/PMML-4_1" version="4.1"/>`, version: "4.1"},
+		{xml: `<PMML xmlns="http://www.dmg.org/PMML-3_2" version="3.2"/>`, version: "3.2"},
+		{xml: `<PMML version="4.0"/>`, version: "4.0"},
+		{xml: `<PMML xmlns="http://www.example.com/PMML" version="4.4"/>`, err: true},
+		{xml: `<PMML version="5.0"/>`, err: true},
+		{xml: `<PMML/>`, err: true},
+		{xml: `<TreeModel/>`, err: true},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.xml, func(t *testing.T) {
+			var out PMML
+			err := xml.Unmarshal([]byte(tt.xml), &out)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.version, out.Version)
+		})
+	}
+}
+
+func TestVersion_Introduced(t *testing.T) {
+	input := `<PMML xmlns="http://www.dmg.org/PMML-4_1" version="4.1">
+		<AnomalyDetectionModel functionName="regression" algorithmType="iforest"/>
+	</PMML>`
+
+	var out PMML
+	err := xml.Unmarshal([]byte(input), &out)
+	assert.EqualError(t, err, "AnomalyDetectionModel requires PMML 4.4, but the document declares version 4.1")
+}
+
+func TestVersion_Segments(t *testing.T) {
+	input := `<PMML xmlns="http://www.dmg.org/PMML-4_2" version="4.2">
+		<MiningModel functionName="regression">
+			<Segmentation multipleModelMethod="average">
+				<Segment><True/><TreeModel functionName="regression"><Node score="1"><True/></Node></TreeModel></Segment>
+				<Segment><True/><GaussianProcessModel functionName="regression"/></Segment>
+			</Segmentation>
+		</MiningModel>
+	</PMML>`
+
+	var out PMML
+	err := xml.Unmarshal([]byte(input), &out)
+	assert.EqualError(t, err, "GaussianProcessModel requires PMML 4.3, but the document declares version 4.2")
+
+	// The same segments are valid in a document of a later version
+	input = strings.Replace(strings.Replace(input, "4_2", "4_3", 1), `version="4.2"`, `version="4.3"`, 1)
+	assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+	assert.Len(t, out.Models[0].MiningModel.Segmentation.Segments, 2)
+}
+
+func TestVersion_ForeignNamespace(t *testing.T) {
+	input := `<PMML xmlns="http://www.dmg.org/PMML-4_2" xmlns:x="http://www.example.com/vendor" version="4.2">
+		<TreeModel modelName="golfing" functionName="classification">
+			<Node id="1" score="will play">
+				<x:Annotation>vendor-specific</x:Annotation>
+				<True/>
+				<Extension name="ignored" value="1"/>
+				<Node id="2" score="no play">
+					<CompoundPredicate booleanOperator="and">
+						<x:Hint/>
+						<SimplePredicate field="outlook" operator="equal" value="sunny"/>
+						<True/>
+					</CompoundPredicate>
+				</Node>
+			</Node>
+		</TreeModel>
+	</PMML>`
+
+	var out PMML
+	assert.NoError(t, xml.Unmarshal([]byte(input), &out))
+	assert.Equal(t, "4.2", out.Version)
+
+	root := out.Models[0].TreeModel.Node
+	assert.Equal(t, &Predicate{True: new(True)}, root.Predicate)
+	assert.Len(t, root.Nodes, 1)
+	assert.Len(t, root.Nodes[0].Predicate.CompoundPredicate.Predicates, 2)
+}
+
+func TestCompareVersion(t *testing.T) {
+	assert.Equal(t, -1, compareVersion("4.1", "4.4"))
+	assert.Equal(t, -1, compareVersion("3.2", "4.0"))
+	assert.Equal(t, 0, compareVersion("4.3", "4.3"))
+	assert.Equal(t, 1, compareVersion("4.4", "4.3"))
+}
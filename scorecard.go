@@ -0,0 +1,104 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// Scorecard generates the LUA code for the element.
+func (s *Scope) Scorecard(v schema.Scorecard, global *Scope) *Scope {
+	options := NewStatement().Append("{initial=%s", formatFloat(v.InitialScore))
+	if v.UseReasonCodes {
+		options.Append(", algorithm=").String(v.ReasonCodeAlgorithm)
+	}
+
+	characteristics := NewScope()
+	for _, c := range v.Characteristics {
+		characteristics.Characteristic(c, v, global)
+	}
+
+	global.Require("scorecard")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or scorecard.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			characteristics,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// Characteristic generates the LUA code for the element. The baseline score of the scorecard
+// is used for the characteristics which do not specify their own.
+func (s *Scope) Characteristic(v schema.Characteristic, scorecard schema.Scorecard, global *Scope) *Scope {
+	header := NewStatement().Append("{name=").String(v.Name)
+	if scorecard.UseReasonCodes {
+		baseline := v.BaselineScore
+		if baseline == nil {
+			baseline = scorecard.BaselineScore
+		}
+		if baseline == nil {
+			header.Error("characteristic %s requires a baseline score", v.Name)
+		} else {
+			header.Append(", baseline=%s", formatFloat(*baseline))
+		}
+	}
+
+	attributes := NewScope()
+	for _, a := range v.Attributes {
+		attributes.Attribute(a, v, scorecard, global)
+	}
+
+	return s.With(
+		header.Append(", attributes={"),
+		attributes,
+		Append("}},"),
+	)
+}
+
+// Attribute generates the LUA code for the element. The partial score is either a number or a
+// function which computes it, and the reason code of the characteristic is used if the attribute
+// does not specify its own.
+func (s *Scope) Attribute(v schema.Attribute, c schema.Characteristic, scorecard schema.Scorecard, global *Scope) *Scope {
+	if v.Predicate == nil {
+		return s.With(NewStatement().Error("attribute of %s has no predicate", c.Name))
+	}
+
+	attribute := NewStatement().Append("{")
+	reasonCode := v.ReasonCode
+	if reasonCode == "" {
+		reasonCode = c.ReasonCode
+	}
+	if scorecard.UseReasonCodes {
+		if reasonCode == "" {
+			attribute.Error("attribute of %s requires a reason code", c.Name)
+		}
+		attribute.Append("reasonCode=").String(reasonCode).Append(", ")
+	}
+
+	test := []Compiler{
+		NewScope().With(
+			NewStatement().Return().Predicate(v.Predicate, global),
+		),
+		Append("end},"),
+	}
+
+	switch {
+	case v.ComplexPartialScore != nil:
+		return s.With(
+			attribute.Append("score=function(v)"),
+			NewScope().With(
+				NewStatement().Return().Expression(v.ComplexPartialScore, global),
+			),
+			Append("end, test=function(v)"),
+		).With(test...)
+	case v.PartialScore != nil:
+		return s.With(
+			attribute.Append("score=%s, test=function(v)", formatFloat(*v.PartialScore)),
+		).With(test...)
+	default:
+		return s.With(attribute.Error("attribute of %s has no partial score", c.Name))
+	}
+}
@@ -0,0 +1,93 @@
+local scorecard = {}
+
+-- NewModel creates a scorecard with its options and its characteristics. Each characteristic
+-- contains its name, its baseline score and its attributes, which contain their reason code,
+-- their partial score and the function which tests their predicate.
+function scorecard.NewModel(options, characteristics)
+    local m = options
+    m.initial = m.initial or 0
+    m.characteristics = characteristics
+
+    -- Function which computes the score and returns the result table
+    m.eval = function(v)
+        return scorecard.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the final score, which is the sum of the initial score and of the partial score
+-- of the first matching attribute of each characteristic, along with the ranked reason codes. If
+-- none of the attributes of a characteristic matches, there is no score.
+function scorecard.Score(m, v)
+    local score, points, order = m.initial, {}, {}
+    for i=1, #m.characteristics do
+        local c = m.characteristics[i]
+        local a = scorecard.Match(c, v)
+        if a == nil then
+            return nil
+        end
+
+        local partial = a.score
+        if type(partial) == 'function' then
+            partial = partial(v)
+        end
+        if Unknown(partial) then
+            return nil
+        end
+        score = score + partial
+
+        -- The points of the reason code are the difference between the partial and the baseline
+        if m.algorithm ~= nil then
+            local diff = c.baseline - partial
+            if m.algorithm == 'pointsAbove' then
+                diff = -diff
+            end
+
+            if points[a.reasonCode] == nil then
+                points[a.reasonCode] = 0
+                table.insert(order, a.reasonCode)
+            end
+            points[a.reasonCode] = points[a.reasonCode] + diff
+        end
+    end
+
+    local r = {value = score}
+    if m.algorithm ~= nil then
+        r.reasonCodes = scorecard.Rank(order, points)
+    end
+    return r
+end
+
+-- Match returns the first attribute of the characteristic whose predicate is true.
+function scorecard.Match(c, v)
+    for i=1, #c.attributes do
+        if c.attributes[i].test(v) == true then
+            return c.attributes[i]
+        end
+    end
+    return nil
+end
+
+-- Rank returns the reason codes sorted by their points in descending order. The reason codes
+-- with the same points keep their order of appearance.
+function scorecard.Rank(order, points)
+    local position = {}
+    for i=1, #order do
+        position[order[i]] = i
+    end
+
+    local ranked = {}
+    for i=1, #order do
+        ranked[i] = order[i]
+    end
+
+    table.sort(ranked, function(a, b)
+        if points[a] ~= points[b] then
+            return points[a] > points[b]
+        end
+        return position[a] < position[b]
+    end)
+    return ranked
+end
+
+return scorecard
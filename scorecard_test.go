@@ -0,0 +1,125 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScorecard(t *testing.T) {
+	td := []struct {
+		input   map[string]interface{}
+		score   float64
+		reasons []string
+	}{
+		{
+			input:   map[string]interface{}{"department": "engineering", "age": 25, "income": 1500},
+			score:   71,
+			reasons: []string{"RC1", "RC2", "RC3"},
+		},
+		{
+			input:   map[string]interface{}{"department": "marketing", "age": 40, "income": 3000},
+			score:   54,
+			reasons: []string{"RC4", "RC1", "RC2"},
+		},
+		{
+			input:   map[string]interface{}{},
+			score:   -7,
+			reasons: []string{"RC1", "RC2", "RC3"},
+		},
+	}
+
+	b, err := ioutil.ReadFile("fixtures/scorecard1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	for _, tt := range td {
+		r, err := runScript(s, tt.input)
+		assert.NoError(t, err)
+		assert.InDelta(t, tt.score, r.Value, 1e-9, tt.input)
+		assert.Equal(t, tt.reasons, r.ReasonCodes, tt.input)
+		assert.InDelta(t, tt.score, r.Output("FinalScore"), 1e-9, tt.input)
+		assert.Equal(t, tt.reasons[0], r.Output("ReasonCode1"), tt.input)
+		assert.Equal(t, tt.reasons[2], r.Output("ReasonCode3"), tt.input)
+	}
+}
+
+func TestScorecard_PointsAbove(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/scorecard1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert([]byte(strings.Replace(string(b), `"pointsBelow"`, `"pointsAbove"`, 1)))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"department": "engineering", "age": 25, "income": 1500})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RC3", "RC2", "RC1"}, r.ReasonCodes)
+}
+
+func TestScorecard_NoReasonCodes(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<Scorecard functionName="regression" useReasonCodes="false" initialScore="100">
+			<MiningSchema><MiningField name="x"/></MiningSchema>
+			<Characteristics>
+				<Characteristic name="x">
+					<Attribute partialScore="-10"><SimplePredicate field="x" operator="lessThan" value="0"/></Attribute>
+					<Attribute partialScore="10"><SimplePredicate field="x" operator="greaterOrEqual" value="0"/></Attribute>
+				</Characteristic>
+			</Characteristics>
+		</Scorecard>
+	</PMML>`))
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	r, err := runScript(s, map[string]interface{}{"x": -1})
+	assert.NoError(t, err)
+	assert.Equal(t, 90.0, r.Value)
+	assert.Nil(t, r.ReasonCodes)
+
+	r, err = runScript(s, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestScorecard_Error(t *testing.T) {
+	tests := []struct {
+		options        string
+		characteristic string
+		err            string
+	}{
+		{
+			options:        `useReasonCodes="true"`,
+			characteristic: `<Attribute partialScore="1" reasonCode="RC1"><True/></Attribute>`,
+			err:            "characteristic c requires a baseline score",
+		},
+		{
+			options:        `useReasonCodes="false"`,
+			characteristic: `<Attribute partialScore="1"/>`,
+			err:            "attribute of c has no predicate",
+		},
+		{
+			options:        `useReasonCodes="false"`,
+			characteristic: `<Attribute><True/></Attribute>`,
+			err:            "attribute of c has no partial score",
+		},
+		{
+			options:        `useReasonCodes="true" baselineScore="1"`,
+			characteristic: `<Attribute partialScore="1"><True/></Attribute>`,
+			err:            "attribute of c requires a reason code",
+		},
+	}
+
+	for _, tc := range tests {
+		_, err := Convert([]byte(`<PMML version="4.4">
+			<Scorecard modelName="x" functionName="regression" ` + tc.options + `><MiningSchema/>
+				<Characteristics><Characteristic name="c">` + tc.characteristic + `</Characteristic></Characteristics>
+			</Scorecard>
+		</PMML>`))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
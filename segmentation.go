@@ -0,0 +1,78 @@
+package pmml2lua
+
+import (
+	"fmt"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// MiningModel generates the LUA code for the element. The model of each segment is generated
+// as a separate function, named after the mining model and the position of the segment.
+func (s *Scope) MiningModel(v schema.MiningModel, global *Scope) *Scope {
+	if v.Segmentation == nil {
+		return s.With(NewStatement().Error("mining model %s has no segmentation", v.ModelName))
+	}
+
+	options := NewStatement().Append("{method=").String(v.Segmentation.MultipleModelMethod).
+		Append(", functionName=").String(v.FunctionName)
+	if v.Segmentation.MissingPredictionTreatment != "" {
+		options.Append(", missing=").String(v.Segmentation.MissingPredictionTreatment)
+	}
+	if v.Segmentation.MissingThreshold != nil {
+		options.Append(", threshold=%s", formatFloat(*v.Segmentation.MissingThreshold))
+	}
+
+	// The models of the segments inherit the derived fields of the transformation dictionary
+	segments := NewScope()
+	global.nested++
+	for i, segment := range v.Segmentation.Segments {
+		name := fmt.Sprintf("%s_%d", v.ModelName, i+1)
+		if segment.Model == (schema.Model{}) {
+			global.nested--
+			return s.With(NewStatement().Error("segment %s has no model", segment.ID))
+		}
+
+		s.Model(segment.Model.Named(name), global)
+		segments.Segment(segment, name, global)
+	}
+	global.nested--
+
+	global.Require("segmentation")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or segmentation.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, {"),
+			segments,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// Segment generates the LUA code for the element, which refers to the function of its model.
+func (s *Scope) Segment(v schema.Segment, name string, global *Scope) *Scope {
+	if v.Predicate == nil {
+		return s.With(NewStatement().Error("segment %s has no predicate", v.ID))
+	}
+
+	segment := NewStatement().Append("{")
+	if v.ID != "" {
+		segment.Append("id=").String(v.ID).Append(", ")
+	}
+	if v.Weight != 1 {
+		segment.Append("weight=%s, ", formatFloat(v.Weight))
+	}
+	if v.VariableWeight != nil {
+		segment.Append("variable=").String(v.VariableWeight.Field).Append(", ")
+	}
+
+	return s.With(
+		segment.Append("eval=%s, test=function(v)", name),
+		NewScope().With(
+			NewStatement().Return().Predicate(v.Predicate, global),
+		),
+		Append("end},"),
+	)
+}
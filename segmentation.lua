@@ -0,0 +1,303 @@
+local tree = require("tree")
+local segmentation = {}
+
+-- NewModel creates a mining model with its options and its segments. Each segment contains its
+-- id, its weight, the field of its variable weight, the function of its model and the function
+-- which tests its predicate.
+function segmentation.NewModel(options, segments)
+    local m = options
+    m.missing = m.missing or 'continue'
+    m.threshold = m.threshold or 1
+    m.segments = segments
+    for i=1, #segments do
+        segments[i].weight = segments[i].weight or 1
+    end
+
+    -- Function which evaluates the segments and returns the combined result table
+    m.eval = function(v)
+        return segmentation.Combine(m, v)
+    end
+    return m
+end
+
+-- Combine evaluates the segments whose predicate is true and combines their results with the
+-- multiple model method of the model. The segments without any prediction are either skipped,
+-- as if their predicate was false, or counted towards the missing threshold of the model.
+function segmentation.Combine(m, v)
+    if m.method == 'modelChain' then
+        return segmentation.Chain(m, v)
+    end
+
+    local results, missing, total = {}, 0, 0
+    for i=1, #m.segments do
+        local s = m.segments[i]
+        if s.test(v) == true then
+            local r, weight = s.eval(v), segmentation.Weight(s, v)
+            local absent = r == nil or r.value == nil
+            if absent and m.missing == 'returnMissing' then
+                return nil
+            elseif absent and m.missing == 'skipSegment' then
+                -- The segment is ignored, along with its weight
+            elseif m.method == 'selectFirst' then
+                if absent then
+                    return nil
+                end
+                return r
+            elseif absent then
+                total = total + weight
+                missing = missing + weight
+            else
+                total = total + weight
+                table.insert(results, {segment = s, result = r, weight = weight})
+            end
+        end
+    end
+
+    -- Too many segments did not give any prediction
+    if #results == 0 or (total > 0 and missing / total > m.threshold) then
+        return nil
+    end
+
+    local fn = segmentation.methods[m.method]
+    if fn == nil then
+        error("multiple model method '" .. tostring(m.method) .. "' is not supported")
+    end
+    return fn(m, results)
+end
+
+-- Weight returns the weight of the segment, which is the value of the field of its variable weight
+-- if it has one. The fixed weight of the segment is used when the value of the field is missing.
+function segmentation.Weight(s, v)
+    if s.variable ~= nil then
+        local x = tonumber(v[s.variable])
+        if x ~= nil then
+            return x
+        end
+    end
+    return s.weight
+end
+
+-- Chain evaluates the segments in turn, adding the output fields of each segment to the record
+-- which is given to the following ones, and returns the result of the last segment evaluated.
+function segmentation.Chain(m, v)
+    local record = setmetatable({}, {__index = v, derived = tree.DerivedOf(v)})
+    local last = nil
+    for i=1, #m.segments do
+        local s = m.segments[i]
+        if s.test(record) == true then
+            last = s.eval(record)
+            if last ~= nil and last.outputs ~= nil then
+                for k, x in pairs(last.outputs) do
+                    record[k] = x
+                end
+            end
+        end
+    end
+    return last
+end
+
+-- Vote returns the result of the majority vote of the segments, where each vote is weighted if
+-- the weighted flag is set. The probabilities are the proportions of the votes.
+function segmentation.Vote(results, weighted)
+    local votes, order, total = {}, {}, 0
+    for i=1, #results do
+        local class, weight = results[i].result.value, 1
+        if weighted then
+            weight = results[i].weight
+        end
+
+        local key = tostring(class)
+        if votes[key] == nil then
+            votes[key] = 0
+            table.insert(order, class)
+        end
+        votes[key] = votes[key] + weight
+        total = total + weight
+    end
+
+    return segmentation.Classes(order, votes, total)
+end
+
+-- Classes creates the result table of a classification out of the scores of the classes, given
+-- in their order of appearance. The probabilities are the scores divided by the total.
+function segmentation.Classes(order, scores, total)
+    local r = {probabilities = {}}
+    local best = nil
+    for i=1, #order do
+        local key = tostring(order[i])
+        if total > 0 then
+            r.probabilities[key] = scores[key] / total
+        end
+        if best == nil or scores[key] > scores[tostring(best)] then
+            best = order[i]
+        end
+    end
+
+    r.value = best
+    return r
+end
+
+-- Probabilities returns the probabilities of a result, which are derived from its predicted value
+-- if the model did not give any.
+function segmentation.Probabilities(r)
+    if r.probabilities ~= nil and next(r.probabilities) ~= nil then
+        return r.probabilities
+    end
+    return {[tostring(r.value)] = 1}
+end
+
+-- Aggregate returns the result whose probabilities are the (weighted) averages, sums or medians
+-- of the probabilities of the segments, depending on the aggregate function. The sums are
+-- normalized so that the probabilities add up to one.
+function segmentation.Aggregate(results, weighted, aggregate)
+    local order, classes, others = {}, {}, {}
+    for i=1, #results do
+        local r = results[i].result
+        if classes[tostring(r.value)] == nil then
+            classes[tostring(r.value)] = r.value
+            table.insert(order, r.value)
+        end
+        for class, _ in pairs(segmentation.Probabilities(r)) do
+            table.insert(others, class)
+        end
+    end
+
+    -- The classes which are never predicted follow in a deterministic order
+    table.sort(others)
+    for i=1, #others do
+        if classes[others[i]] == nil then
+            classes[others[i]] = others[i]
+            table.insert(order, others[i])
+        end
+    end
+
+    local scores, total = {}, 0
+    for i=1, #order do
+        local key = tostring(order[i])
+        local items = {}
+        for j=1, #results do
+            local weight = 1
+            if weighted then
+                weight = results[j].weight
+            end
+            table.insert(items, {segmentation.Probabilities(results[j].result)[key] or 0, weight})
+        end
+        scores[key] = aggregate(items)
+        total = total + scores[key]
+    end
+
+    local r = segmentation.Classes(order, scores, total)
+    if aggregate ~= segmentation.Sum then
+        for i=1, #order do
+            local key = tostring(order[i])
+            r.probabilities[key] = scores[key]
+        end
+    end
+    return r
+end
+
+-- Numbers returns the pairs of the predicted values and of the weights of the results.
+function segmentation.Numbers(results, weighted)
+    local items = {}
+    for i=1, #results do
+        local weight = 1
+        if weighted then
+            weight = results[i].weight
+        end
+        table.insert(items, {results[i].result.value, weight})
+    end
+    return items
+end
+
+-- Mean returns the weighted mean of the pairs of values and weights.
+function segmentation.Mean(items)
+    local sum, weights = 0, 0
+    for i=1, #items do
+        sum = sum + items[i][1] * items[i][2]
+        weights = weights + items[i][2]
+    end
+    return sum / weights
+end
+
+-- Sum returns the weighted sum of the pairs of values and weights.
+function segmentation.Sum(items)
+    local sum = 0
+    for i=1, #items do
+        sum = sum + items[i][1] * items[i][2]
+    end
+    return sum
+end
+
+-- Median returns the weighted median of the pairs of values and weights, which is the smallest
+-- value whose cumulative weight reaches half of the total weight. When the cumulative weight is
+-- exactly half of the total, the mean of the two middle values is returned.
+function segmentation.Median(items)
+    local sorted, total = {}, 0
+    for i=1, #items do
+        sorted[i] = items[i]
+        total = total + items[i][2]
+    end
+    table.sort(sorted, function(a, b) return a[1] < b[1] end)
+
+    local cumulative = 0
+    for i=1, #sorted do
+        cumulative = cumulative + sorted[i][2]
+        if cumulative == total / 2 and i < #sorted then
+            return (sorted[i][1] + sorted[i+1][1]) / 2
+        elseif cumulative > total / 2 then
+            return sorted[i][1]
+        end
+    end
+    return sorted[#sorted][1]
+end
+
+-- Numeric returns a function which combines the results of the segments of a regression with the
+-- aggregate function, or their probabilities in case of a classification.
+local function numeric(aggregate, weighted)
+    return function(m, results)
+        if m.functionName == 'classification' then
+            return segmentation.Aggregate(results, weighted, aggregate)
+        end
+        return {value = aggregate(segmentation.Numbers(results, weighted))}
+    end
+end
+
+-- The multiple model methods, by their name
+segmentation.methods = {
+    majorityVote = function(m, results)
+        return segmentation.Vote(results, false)
+    end,
+    weightedMajorityVote = function(m, results)
+        return segmentation.Vote(results, true)
+    end,
+    average = numeric(segmentation.Mean, false),
+    weightedAverage = numeric(segmentation.Mean, true),
+    median = numeric(segmentation.Median, false),
+    weightedMedian = numeric(segmentation.Median, true),
+    sum = numeric(segmentation.Sum, false),
+    weightedSum = numeric(segmentation.Sum, true),
+    max = function(m, results)
+        local best, score = nil, nil
+        for i=1, #results do
+            local r = results[i].result
+            local x = r.value
+            if m.functionName == 'classification' then
+                x = segmentation.Probabilities(r)[tostring(r.value)]
+            end
+            if best == nil or x > score then
+                best, score = r, x
+            end
+        end
+        return best
+    end,
+    selectAll = function(m, results)
+        local values, segments = {}, {}
+        for i=1, #results do
+            table.insert(values, results[i].result.value)
+            table.insert(segments, results[i].result)
+        end
+        return {value = values, segments = segments}
+    end,
+}
+
+return segmentation
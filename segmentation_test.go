@@ -0,0 +1,248 @@
+package pmml2lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiningModel_Regression(t *testing.T) {
+	td := []struct {
+		method string
+		expect interface{}
+		other  interface{}
+	}{
+		{method: "average", expect: 80 / 3.0, other: 11.0},
+		{method: "weightedAverage", expect: 190 / 6.0, other: 34 / 3.0},
+		{method: "median", expect: 30.0, other: 11.0},
+		{method: "weightedMedian", expect: 35.0, other: 12.0},
+		{method: "max", expect: 40.0, other: 12.0},
+		{method: "sum", expect: 80.0, other: 22.0},
+		{method: "weightedSum", expect: 190.0, other: 34.0},
+		{method: "selectFirst", expect: 10.0, other: 10.0},
+		{method: "modelChain", expect: 40.0, other: 12.0},
+	}
+
+	b, err := ioutil.ReadFile("fixtures/mining1.xml")
+	assert.NoError(t, err)
+
+	for _, tc := range td {
+		t.Run(tc.method, func(t *testing.T) {
+			doc := strings.Replace(string(b), `multipleModelMethod="average"`, fmt.Sprintf(`multipleModelMethod="%s"`, tc.method), 1)
+			code, err := Convert([]byte(doc))
+			assert.NoError(t, err)
+			assert.Contains(t, string(code), "function forest_3(v)")
+
+			s := makeScript(string(code))
+			r, err := runScript(s, map[string]interface{}{"x": 4})
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expect, r.Value, 1e-9)
+
+			r, err = runScript(s, map[string]interface{}{"x": -1})
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.other, r.Value, 1e-9)
+		})
+	}
+}
+
+func TestMiningModel_VariableWeight(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/mining1.xml")
+	assert.NoError(t, err)
+
+	// The weight of the third segment is taken from the field w
+	doc := strings.Replace(string(b), `multipleModelMethod="average"`, `multipleModelMethod="weightedAverage"`, 1)
+	doc = strings.Replace(doc, `<MiningField name="x"/>`, `<MiningField name="x"/><MiningField name="w"/>`, 1)
+	doc = strings.Replace(doc, `<Segment id="3" weight="3">`, `<Segment id="3"><VariableWeight field="w"/>`, 1)
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "variable='w'")
+
+	tests := []struct {
+		input  map[string]interface{}
+		expect float64
+	}{
+		{input: map[string]interface{}{"x": 4, "w": 5}, expect: 270 / 8.0},
+		{input: map[string]interface{}{"x": 4, "w": 0.5}, expect: 90 / 3.5},
+		{input: map[string]interface{}{"x": 4}, expect: 110 / 4.0},
+	}
+
+	s := makeScript(string(code))
+	for _, tc := range tests {
+		r, err := runScript(s, tc.input)
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.expect, r.Value, 1e-9, tc.input)
+	}
+}
+
+func TestMiningModel_SelectAll(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/mining1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert([]byte(strings.Replace(string(b), `"average"`, `"selectAll"`, 1)))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{10.0, 30.0, 40.0}, r.Value)
+	assert.Len(t, r.Segments, 3)
+	assert.Equal(t, 30.0, r.Segments[1].Value)
+}
+
+func TestMiningModel_Classification(t *testing.T) {
+	td := []struct {
+		method string
+		expect string
+		yes    float64
+	}{
+		{method: "majorityVote", expect: "no", yes: 1 / 3.0},
+		{method: "weightedMajorityVote", expect: "no", yes: 0.4},
+		{method: "average", expect: "no", yes: 1.3 / 3},
+		{method: "weightedAverage", expect: "no", yes: 0.48},
+		{method: "median", expect: "no", yes: 0.3},
+		{method: "sum", expect: "no", yes: 1.3 / 3},
+		{method: "max", expect: "yes", yes: 0.8},
+		{method: "selectFirst", expect: "yes", yes: 0.8},
+	}
+
+	leaf := func(score string, yes, no int) string {
+		return fmt.Sprintf(`<TreeModel functionName="classification">
+			<MiningSchema><MiningField name="x"/></MiningSchema>
+			<Node score="%s"><True/>
+				<ScoreDistribution value="yes" recordCount="%d"/>
+				<ScoreDistribution value="no" recordCount="%d"/>
+			</Node>
+		</TreeModel>`, score, yes, no)
+	}
+
+	for _, tc := range td {
+		t.Run(tc.method, func(t *testing.T) {
+			code, err := Convert([]byte(fmt.Sprintf(`<PMML version="4.4">
+				<MiningModel modelName="vote" functionName="classification">
+					<MiningSchema><MiningField name="x"/></MiningSchema>
+					<Segmentation multipleModelMethod="%s">
+						<Segment id="1"><True/>%s</Segment>
+						<Segment id="2"><True/>%s</Segment>
+						<Segment id="3" weight="0.5"><True/>%s</Segment>
+					</Segmentation>
+				</MiningModel>
+			</PMML>`, tc.method, leaf("yes", 8, 2), leaf("no", 3, 7), leaf("no", 2, 8))))
+			assert.NoError(t, err)
+
+			r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 1})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, r.Value)
+			assert.InDelta(t, tc.yes, r.Probability("yes"), 1e-9)
+		})
+	}
+}
+
+func TestMiningModel_Chain(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<MiningModel modelName="boosted" functionName="regression">
+			<MiningSchema><MiningField name="x"/></MiningSchema>
+			<Output><OutputField name="Probability" feature="predictedValue"/></Output>
+			<Segmentation multipleModelMethod="modelChain">
+				<Segment id="1"><True/>
+					<RegressionModel functionName="regression">
+						<MiningSchema><MiningField name="x"/></MiningSchema>
+						<Output><OutputField name="margin" feature="predictedValue"/></Output>
+						<RegressionTable intercept="0"><NumericPredictor name="x" coefficient="2"/></RegressionTable>
+					</RegressionModel>
+				</Segment>
+				<Segment id="2"><True/>
+					<RegressionModel functionName="regression" normalizationMethod="logit">
+						<MiningSchema><MiningField name="margin"/></MiningSchema>
+						<RegressionTable intercept="-1"><NumericPredictor name="margin" coefficient="1"/></RegressionTable>
+					</RegressionModel>
+				</Segment>
+			</Segmentation>
+		</MiningModel>
+	</PMML>`))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 1})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1/(1+math.Exp(-1)), r.Value, 1e-9)
+	assert.InDelta(t, 1/(1+math.Exp(-1)), r.Output("Probability"), 1e-9)
+}
+
+func TestMiningModel_Missing(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/mining1.xml")
+	assert.NoError(t, err)
+
+	for treatment, expect := range map[string]interface{}{
+		"continue":      40.0,
+		"skipSegment":   40.0,
+		"returnMissing": nil,
+	} {
+		doc := strings.Replace(string(b), `multipleModelMethod="average"`, `multipleModelMethod="average" missingPredictionTreatment="`+treatment+`"`, 1)
+		doc = strings.Replace(doc, `<Node score="15">`, `<Node>`, 1)
+		doc = strings.Replace(doc, `<Node score="10">`, `<Node>`, 1)
+		doc = strings.Replace(doc, `<Node score="21">`, `<Node>`, 1)
+		doc = strings.Replace(doc, `<Node score="30">`, `<Node>`, 1)
+
+		code, err := Convert([]byte(doc))
+		assert.NoError(t, err)
+
+		r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 4})
+		assert.NoError(t, err)
+		assert.Equal(t, expect, valueOf(r), treatment)
+	}
+}
+
+func TestMiningModel_MissingThreshold(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/mining1.xml")
+	assert.NoError(t, err)
+
+	// The first two segments give no prediction, which is half of the weights of the segments
+	doc := strings.Replace(string(b), `<Node score="10">`, `<Node>`, 1)
+	doc = strings.Replace(doc, `<Node score="30">`, `<Node>`, 1)
+	tests := []struct {
+		method    string
+		treatment string
+		threshold string
+		expect    interface{}
+	}{
+		{method: "average", treatment: "continue", threshold: "0.5", expect: 40.0},
+		{method: "average", treatment: "continue", threshold: "0.4", expect: nil},
+		{method: "average", treatment: "continue", threshold: "0", expect: nil},
+		{method: "average", treatment: "skipSegment", threshold: "0.4", expect: 40.0},
+		{method: "average", treatment: "skipSegment", threshold: "0", expect: 40.0},
+		{method: "average", treatment: "returnMissing", threshold: "1", expect: nil},
+		{method: "selectFirst", treatment: "continue", threshold: "1", expect: nil},
+		{method: "selectFirst", treatment: "skipSegment", threshold: "1", expect: 40.0},
+	}
+
+	for _, tc := range tests {
+		code, err := Convert([]byte(strings.Replace(doc, `multipleModelMethod="average"`,
+			`multipleModelMethod="`+tc.method+`" missingPredictionTreatment="`+tc.treatment+`" missingThreshold="`+tc.threshold+`"`, 1)))
+		assert.NoError(t, err)
+
+		r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 4})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expect, valueOf(r), tc)
+	}
+}
+
+func TestMiningModel_Error(t *testing.T) {
+	tree := `<TreeModel functionName="regression"><MiningSchema/><Node score="1"><True/></Node></TreeModel>`
+	tests := []struct {
+		segmentation string
+		err          string
+	}{
+		{segmentation: ``, err: "mining model x has no segmentation"},
+		{segmentation: `<Segmentation multipleModelMethod="sum"><Segment id="1"><True/></Segment></Segmentation>`, err: "segment 1 has no model"},
+		{segmentation: `<Segmentation multipleModelMethod="sum"><Segment id="1">` + tree + `</Segment></Segmentation>`, err: "segment 1 has no predicate"},
+	}
+
+	for _, tc := range tests {
+		_, err := Convert([]byte(`<PMML version="4.4">
+			<MiningModel modelName="x" functionName="regression"><MiningSchema/>` + tc.segmentation + `</MiningModel>
+		</PMML>`))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
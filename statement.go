@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -14,6 +16,20 @@ var (
 	errNilElement = errors.New("unable to convert a nil element")
 )
 
+// The escaper for the LUA string literals
+var escaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`)
+
+// The names which can be used as LUA identifiers, except for the reserved keywords
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// The reserved keywords of LUA, which can not be used as identifiers
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true, "end": true,
+	"false": true, "for": true, "function": true, "goto": true, "if": true, "in": true,
+	"local": true, "nil": true, "not": true, "or": true, "repeat": true, "return": true,
+	"then": true, "true": true, "until": true, "while": true,
+}
+
 // Compiler represents somthing that can be compiled (statement or scope)
 type Compiler interface {
 	Compile() ([]byte, error)
@@ -29,9 +45,15 @@ var (
 
 // Scope represents a scope that can be rendered.
 type Scope struct {
-	ref string     // The reference of the scope (e.g. name of the function)
-	dst []Compiler // The list of statements
-	tab int        // The number of tabs for indentation
+	ref       string                      // The reference of the scope (e.g. name of the function)
+	dst       []Compiler                  // The list of statements
+	tab       int                         // The number of tabs for indentation
+	fields    map[string]schema.DataField // The data fields declared in the scope
+	using     map[string]bool             // The modules required by the scope
+	functions map[string]bool             // The user-defined functions declared in the scope
+	derived   []schema.DerivedField       // The derived fields of the transformation dictionary
+	nested    int                         // The depth of the segments being generated
+	parent    *Scope                      // The scope which the local scope was created from
 }
 
 // NewScope prepares a new scope.
@@ -80,6 +102,65 @@ func (s *Scope) WithIf(condition bool, body ...Compiler) *Scope {
 	return s
 }
 
+// DataDictionary declares the data fields in the scope, so their types can be used
+// during the code generation.
+func (s *Scope) DataDictionary(v schema.DataDictionary) *Scope {
+	if s.fields == nil {
+		s.fields = make(map[string]schema.DataField, len(v.DataFields))
+	}
+
+	for _, f := range v.DataFields {
+		s.fields[f.Name] = f
+	}
+	return s
+}
+
+// Require imports the LUA module into a local variable of the same name, unless the module
+// was already imported by the scope. The imports precede the other statements of the scope, so
+// that the functions generated before a module is required can refer to it as well.
+func (s *Scope) Require(module string) *Scope {
+	if s.parent != nil {
+		s.parent.Require(module)
+		return s
+	}
+
+	if s.using[module] {
+		return s
+	}
+
+	if s.using == nil {
+		s.using = make(map[string]bool, 4)
+	}
+
+	// Insert the import after the modules which are already imported
+	at := len(s.using)
+	s.using[module] = true
+	s.dst = append(s.dst, nil)
+	copy(s.dst[at+1:], s.dst[at:])
+	s.dst[at] = Append(`local %s = require("%s")`, module, module)
+	return s
+}
+
+// Local creates a scope which declares the derived fields of a model on top of the fields of the
+// scope, so the types of the local transformations of a model do not leak into the other models.
+// The modules, the functions and the derived fields of the dictionary are shared with the scope.
+func (s *Scope) Local(fields []schema.DerivedField) *Scope {
+	local := *s
+	local.dst = nil
+	local.parent = s
+	local.fields = make(map[string]schema.DataField, len(s.fields)+len(fields))
+	for name, f := range s.fields {
+		local.fields[name] = f
+	}
+	return local.DerivedFields(fields)
+}
+
+// DataField returns the declared data field.
+func (s *Scope) DataField(name string) (schema.DataField, bool) {
+	f, ok := s.fields[name]
+	return f, ok
+}
+
 // Scope creates a child scope without sub-identation
 func (s *Scope) Scope() *Scope {
 	child := NewScope()
@@ -160,6 +241,10 @@ func (s *Statement) Statement(statement *Statement) *Statement {
 	if s.err != nil {
 		return s
 	}
+	if statement.err != nil {
+		s.err = statement.err
+		return s
+	}
 
 	_, s.err = s.buf.Write(statement.buf.Bytes())
 	return s
@@ -175,7 +260,7 @@ func (s *Statement) Append(format string, args ...interface{}) *Statement {
 
 // String writes an escaped LUA string.
 func (s *Statement) String(v string) *Statement {
-	return s.Append(`'%v'`, v)
+	return s.Append(`'%v'`, escaper.Replace(v))
 }
 
 // Whitespace writes an white space character.
@@ -199,6 +284,35 @@ func (s *Statement) Value(v schema.Value) *Statement {
 	return s
 }
 
+// Literal generates the LUA code for a value of the specified data type. If the value can
+// not be converted to the data type, an error is set.
+func (s *Statement) Literal(v schema.Value, dataType string) *Statement {
+	switch dataType {
+	case "":
+		return s.Value(v)
+	case "integer":
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil || f != math.Trunc(f) {
+			return s.Error("value '%v' can not be converted to %s", v, dataType)
+		}
+		return s.Append("%d", int64(f))
+	case "float", "double":
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return s.Error("value '%v' can not be converted to %s", v, dataType)
+		}
+		return s.Append(formatFloat(f))
+	case "boolean":
+		b, err := strconv.ParseBool(string(v))
+		if err != nil {
+			return s.Error("value '%v' can not be converted to %s", v, dataType)
+		}
+		return s.Boolean(b)
+	default:
+		return s.String(string(v))
+	}
+}
+
 // Boolean writes a LUA boolean value
 func (s *Statement) Boolean(v bool) *Statement {
 	if v {
@@ -222,8 +336,40 @@ func (s *Statement) Number(v interface{}) *Statement {
 	return s
 }
 
-// Field generates the LUA code for the element.
+// Floats writes a LUA table of numbers.
+func (s *Statement) Floats(values []float64) *Statement {
+	s.Append("{")
+	for i, v := range values {
+		s.Append(formatFloat(v))
+		if i+1 < len(values) {
+			s.Append(", ")
+		}
+	}
+	return s.Append("}")
+}
+
+// formatFloat returns the shortest LUA representation of the number. LUA has no literals
+// for the special values, so these are written as the expressions which produce them, since
+// math.huge is the largest finite number rather than the infinity in some runtimes.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "(0/0)"
+	case math.IsInf(v, 1):
+		return "(1/0)"
+	case math.IsInf(v, -1):
+		return "(-1/0)"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// Field generates the LUA code for the element. The names which are not LUA identifiers, such as
+// the names with spaces or dots, are written as quoted keys instead.
 func (s *Statement) Field(fieldName string) *Statement {
+	if !identifier.MatchString(fieldName) || keywords[fieldName] {
+		return s.Append(`v[`).String(fieldName).Append(`]`)
+	}
 	return s.Append(`v.%v`, fieldName)
 }
 
@@ -1,12 +1,17 @@
 package pmml2lua
 
 import (
+	"context"
 	"encoding/xml"
 	"io/ioutil"
+	"math"
 	"os"
+	"path/filepath"
 	"strings"
+	"testing"
 
 	"github.com/kelindar/lua"
+	"github.com/stretchr/testify/assert"
 )
 
 // scopeFor creates a new writer for an input + schema combination
@@ -28,9 +33,7 @@ func scopeFor(input string, schema interface{}) (*Scope, *Scope, func() string)
 	// Create an outer scope with a global space and a main func
 	main := NewScope()
 	body := main.Function("main", "v")
-	global := NewScope().With(
-		Append(`local tree = require("tree")`),
-	)
+	global := NewScope().Require("tree").Require("json")
 
 	return body, global, func() string {
 		code1, err := global.Compile()
@@ -47,22 +50,64 @@ func scopeFor(input string, schema interface{}) (*Scope, *Scope, func() string)
 	}
 }
 
-// MakeScript makes a script for testing
-func makeScript(code string) *lua.Script {
-	f, _ := os.Open("tree.lua")
-	moduleCode, err := lua.FromReader("tree.lua", f)
-	module := &lua.ScriptModule{
-		Script:  moduleCode,
-		Name:    "tree",
-		Version: "1.0.0",
+// runScript runs the script and decodes its result
+func runScript(s *lua.Script, input interface{}) (*Result, error) {
+	v, err := s.Run(context.Background(), input)
+	if err != nil {
+		return nil, err
 	}
+
+	return ResultOf(v)
+}
+
+// MakeScript makes a script for testing, with every runtime module of the package
+func makeScript(code string) *lua.Script {
+	files, err := filepath.Glob("*.lua")
 	if err != nil {
 		panic(err)
 	}
 
-	s, err := lua.FromString("test.lua", code, module)
+	modules := make([]lua.Module, 0, len(files))
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			panic(err)
+		}
+
+		moduleCode, err := lua.FromReader(file, f)
+		f.Close()
+		if err != nil {
+			panic(err)
+		}
+
+		modules = append(modules, &lua.ScriptModule{
+			Script:  moduleCode,
+			Name:    strings.TrimSuffix(file, ".lua"),
+			Version: "1.0.0",
+		})
+	}
+
+	s, err := lua.FromString("test.lua", code, modules...)
 	if err != nil {
 		panic(err)
 	}
 	return s
 }
+
+func TestStatement_Floats(t *testing.T) {
+	values := []float64{1.5, math.NaN(), math.Inf(1), math.Inf(-1)}
+	b, err := NewStatement().Floats(values).Compile()
+	assert.NoError(t, err)
+
+	code := strings.TrimSpace(string(b))
+	assert.Equal(t, "{1.5, (0/0), (1/0), (-1/0)}", code)
+
+	// The special values must evaluate to the same numbers in LUA
+	s := makeScript(`function main()
+		local x = ` + code + `
+		return x[1] == 1.5 and x[2] ~= x[2] and x[3] == 1/0 and x[4] == -1/0
+	end`)
+	out, err := s.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, lua.Bool(true), out)
+}
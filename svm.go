@@ -0,0 +1,153 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// SupportVectorMachineModel generates the LUA code for the element. The vectors of the dictionary
+// are written as dense arrays in the order of the vector fields.
+func (s *Scope) SupportVectorMachineModel(v schema.SupportVectorMachineModel, global *Scope) *Scope {
+	options := NewStatement().Append("{functionName=").String(v.FunctionName).
+		Append(", method=").String(v.ClassificationMethod).
+		Append(", representation=").String(v.SvmRepresentation)
+	if v.MaxWins {
+		options.Append(", maxWins=true")
+	}
+
+	switch v.ClassificationMethod {
+	case "OneAgainstAll", "OneAgainstOne":
+	default:
+		options.Error("classification method %s is not supported", v.ClassificationMethod)
+	}
+
+	switch v.SvmRepresentation {
+	case "SupportVectors":
+	case "Coefficients":
+		if v.LinearKernel == nil {
+			options.Error("coefficients of %s require a linear kernel", v.ModelName)
+		}
+	default:
+		options.Error("representation %s is not supported", v.SvmRepresentation)
+	}
+
+	// The kernel function and its parameters
+	options.Append(", kernel=")
+	switch {
+	case v.LinearKernel != nil:
+		options.Append("{type='linear'}")
+	case v.PolynomialKernel != nil:
+		k := v.PolynomialKernel
+		options.Append("{type='polynomial', gamma=%s, coef0=%s, degree=%s}", formatFloat(k.Gamma), formatFloat(k.Coef0), formatFloat(k.Degree))
+	case v.RadialBasisKernel != nil:
+		options.Append("{type='radialBasis', gamma=%s}", formatFloat(v.RadialBasisKernel.Gamma))
+	case v.SigmoidKernel != nil:
+		k := v.SigmoidKernel
+		options.Append("{type='sigmoid', gamma=%s, coef0=%s}", formatFloat(k.Gamma), formatFloat(k.Coef0))
+	default:
+		options.Error("model %s has no kernel type", v.ModelName)
+	}
+
+	fields := NewStatement().Append("{")
+	for i, f := range v.VectorDictionary.Fields {
+		fields.String(f.Field)
+		if i+1 < len(v.VectorDictionary.Fields) {
+			fields.Append(", ")
+		}
+	}
+
+	vectors := NewScope()
+	for _, vector := range v.VectorDictionary.Instances {
+		vectors.With(NewStatement().VectorInstance(vector, len(v.VectorDictionary.Fields)))
+	}
+
+	machines := NewScope()
+	for _, machine := range v.Machines {
+		machines.With(NewStatement().SupportVectorMachine(machine, v, global))
+	}
+	if len(v.Machines) == 0 {
+		machines.With(NewStatement().Error("model %s has no support vector machine", v.ModelName))
+	}
+
+	global.Require("svm")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or svm.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("}, ").Statement(fields).Append("}, {"),
+			vectors,
+			Append("}, {"),
+			machines,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// VectorInstance generates the LUA code for the element.
+func (s *Statement) VectorInstance(v schema.VectorInstance, size int) *Statement {
+	values, err := v.Floats(size)
+	switch {
+	case err != nil:
+		return s.Error("vector %s is invalid: %v", v.ID, err)
+	case len(values) != size:
+		return s.Error("vector %s has %d values instead of %d", v.ID, len(values), size)
+	}
+
+	return s.Append("[").String(v.ID).Append("]=").Floats(values).Append(",")
+}
+
+// SupportVectorMachine generates the LUA code for the element. The alternate binary target
+// category of the model is used by a single machine which does not specify its own, and the
+// threshold of the model by the machines which do not specify their own.
+func (s *Statement) SupportVectorMachine(v schema.SupportVectorMachine, model schema.SupportVectorMachineModel, global *Scope) *Statement {
+	target, _ := global.DataField(model.MiningSchema.Target())
+	alternate := v.AlternateTargetCategory
+	if alternate == "" && len(model.Machines) == 1 {
+		alternate = model.AlternateBinaryTargetCategory
+	}
+
+	threshold := model.Threshold
+	if v.Threshold != nil {
+		threshold = *v.Threshold
+	}
+
+	s.Append("{")
+	if model.FunctionName == "classification" {
+		if v.TargetCategory == "" {
+			s.Error("support vector machine of %s has no target category", model.ModelName)
+		}
+		s.Append("target=").Literal(v.TargetCategory, target.DataType).Append(", ")
+		if alternate != "" {
+			s.Append("alternate=").Literal(alternate, target.DataType).Append(", ")
+		} else if model.ClassificationMethod == "OneAgainstOne" {
+			s.Error("support vector machine of %s has no alternate target category", model.ModelName)
+		}
+		s.Append("threshold=%s, ", formatFloat(threshold))
+	}
+
+	// The support vectors are only listed by the machines which are not represented by the
+	// coefficients of the vector fields
+	coefficients := v.Coefficients.Coefficients
+	s.Append("intercept=%s", formatFloat(v.Coefficients.AbsoluteValue))
+	if model.SvmRepresentation == "SupportVectors" {
+		if len(v.SupportVectors) != len(coefficients) {
+			s.Error("support vector machine of %s has %d support vectors but %d coefficients", model.ModelName, len(v.SupportVectors), len(coefficients))
+		}
+
+		s.Append(", vectors={")
+		for i, sv := range v.SupportVectors {
+			s.String(sv.VectorID)
+			if i+1 < len(v.SupportVectors) {
+				s.Append(", ")
+			}
+		}
+		s.Append("}")
+	}
+
+	values := make([]float64, 0, len(coefficients))
+	for _, c := range coefficients {
+		values = append(values, c.Value)
+	}
+	return s.Append(", coefficients=").Floats(values).Append("},")
+}
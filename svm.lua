@@ -0,0 +1,140 @@
+local svm = {}
+
+-- NewModel creates a support vector machine model with its options, its vector fields, its
+-- vectors by their id and its machines. Each machine contains its target categories, its
+-- threshold, its intercept, the ids of its support vectors and their coefficients.
+function svm.NewModel(options, fields, vectors, machines)
+    local m = options
+    m.fields = fields
+    m.vectors = vectors
+    m.machines = machines
+
+    -- Function which evaluates the machines and returns the result table
+    m.eval = function(v)
+        return svm.Evaluate(m, v)
+    end
+    return m
+end
+
+-- Evaluate returns the result of the model, or nil if one of the vector fields is missing.
+function svm.Evaluate(m, v)
+    local x = {}
+    for i=1, #m.fields do
+        local value = v[m.fields[i]]
+        if Unknown(value) then
+            return nil
+        end
+        x[i] = value
+    end
+
+    if m.functionName == 'classification' then
+        return svm.Classify(m, x)
+    end
+    return {value = svm.Decision(m, m.machines[1], x)}
+end
+
+-- Decision returns the value of the decision function of the machine, which is the sum of its
+-- intercept and of the kernel values of its support vectors weighted by their coefficients.
+function svm.Decision(m, s, x)
+    local y = s.intercept
+    if m.representation == 'Coefficients' then
+        for i=1, #s.coefficients do
+            y = y + s.coefficients[i] * x[i]
+        end
+        return y
+    end
+
+    local kernel = svm.kernels[m.kernel.type]
+    for i=1, #s.vectors do
+        y = y + s.coefficients[i] * kernel(m.kernel, x, m.vectors[s.vectors[i]])
+    end
+    return y
+end
+
+-- Classify returns the predicted category. A single machine with an alternate category is a
+-- binary classifier, the machines of the one-against-one method vote for the categories, with
+-- the ties won by the category which is declared first, and the machine with the lowest value
+-- wins with the one-against-all method, or the highest value if the maximum wins.
+function svm.Classify(m, x)
+    local machines = m.machines
+    if #machines == 1 and machines[1].alternate ~= nil then
+        return {value = svm.Vote(m, machines[1], svm.Decision(m, machines[1], x))}
+    end
+
+    if m.method == 'OneAgainstOne' then
+        local votes, order = {}, {}
+        for i=1, #machines do
+            local categories = {machines[i].target, machines[i].alternate}
+            for j=1, 2 do
+                if votes[categories[j]] == nil then
+                    votes[categories[j]] = 0
+                    table.insert(order, categories[j])
+                end
+            end
+        end
+
+        for i=1, #machines do
+            local category = svm.Vote(m, machines[i], svm.Decision(m, machines[i], x))
+            votes[category] = votes[category] + 1
+        end
+
+        local best = nil
+        for i=1, #order do
+            if best == nil or votes[order[i]] > votes[best] then
+                best = order[i]
+            end
+        end
+        return {value = best}
+    end
+
+    local best, score = nil, nil
+    for i=1, #machines do
+        local y = svm.Decision(m, machines[i], x)
+        if score == nil or (m.maxWins and y > score) or (not m.maxWins and y < score) then
+            best = i
+            score = y
+        end
+    end
+    return {value = machines[best].target}
+end
+
+-- Vote returns the category the machine votes for, which is its target category if the value
+-- of its decision function is lower than its threshold and its alternate category otherwise. If
+-- the maximum wins, the target category is voted for when the value is greater instead.
+function svm.Vote(m, s, y)
+    if (m.maxWins and y > s.threshold) or (not m.maxWins and y < s.threshold) then
+        return s.target
+    end
+    return s.alternate
+end
+
+-- Dot returns the dot product of two vectors.
+function svm.Dot(a, b)
+    local y = 0
+    for i=1, #a do
+        y = y + a[i] * b[i]
+    end
+    return y
+end
+
+-- The kernel functions, by their type
+svm.kernels = {
+    linear = function(k, a, b)
+        return svm.Dot(a, b)
+    end,
+    polynomial = function(k, a, b)
+        return (k.gamma * svm.Dot(a, b) + k.coef0) ^ k.degree
+    end,
+    radialBasis = function(k, a, b)
+        local d = 0
+        for i=1, #a do
+            d = d + (a[i] - b[i]) ^ 2
+        end
+        return math.exp(-k.gamma * d)
+    end,
+    sigmoid = function(k, a, b)
+        return math.tanh(k.gamma * svm.Dot(a, b) + k.coef0)
+    end,
+}
+
+return svm
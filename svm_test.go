@@ -0,0 +1,213 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportVectorMachineModel(t *testing.T) {
+	vectors := [][]float64{{1, 0}, {0, 1.5}, {-1, -0.5}}
+	machines := []struct {
+		target, alternate string
+		intercept         float64
+		vectors           []int
+		coefficients      []float64
+	}{
+		{target: "a", alternate: "b", intercept: 0.1, vectors: []int{0, 1}, coefficients: []float64{-0.8, 0.6}},
+		{target: "a", alternate: "c", intercept: -0.2, vectors: []int{0, 2}, coefficients: []float64{-0.5, 0.7}},
+		{target: "b", alternate: "c", intercept: 0, vectors: []int{1, 2}, coefficients: []float64{-1, 1}},
+	}
+
+	dot := func(a, b []float64) float64 { return a[0]*b[0] + a[1]*b[1] }
+	kernels := map[string]func(a, b []float64) float64{
+		`<LinearKernelType/>`: dot,
+		`<PolynomialKernelType gamma="0.5" coef0="1" degree="2"/>`: func(a, b []float64) float64 {
+			return math.Pow(0.5*dot(a, b)+1, 2)
+		},
+		`<RadialBasisKernelType gamma="0.5"/>`: func(a, b []float64) float64 {
+			return math.Exp(-0.5 * ((a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1])))
+		},
+		`<SigmoidKernelType gamma="0.5" coef0="0.2"/>`: func(a, b []float64) float64 {
+			return math.Tanh(0.5*dot(a, b) + 0.2)
+		},
+	}
+
+	methods := []string{
+		`classificationMethod="OneAgainstOne"`,
+		`classificationMethod="OneAgainstOne" maxWins="true"`,
+		`classificationMethod="OneAgainstAll"`,
+		`classificationMethod="OneAgainstAll" maxWins="true"`,
+	}
+
+	b, err := ioutil.ReadFile("fixtures/svm1.xml")
+	assert.NoError(t, err)
+
+	for element, kernel := range kernels {
+		for _, method := range methods {
+			t.Run(element+method, func(t *testing.T) {
+				doc := strings.Replace(string(b), `<RadialBasisKernelType gamma="0.5" description="Radial basis kernel type"/>`, element, 1)
+				doc = strings.Replace(doc, `classificationMethod="OneAgainstOne"`, method, 1)
+				code, err := Convert([]byte(doc))
+				assert.NoError(t, err)
+
+				s := makeScript(string(code))
+				for _, x := range [][]float64{{0, 0}, {1, 1}, {-1, 0.5}, {0.5, -2}, {2, 2}} {
+					maxWins := strings.Contains(method, "maxWins")
+					votes, best, score := map[string]int{}, "", 0.0
+					for i, m := range machines {
+						y := m.intercept
+						for j, id := range m.vectors {
+							y += m.coefficients[j] * kernel(x, vectors[id])
+						}
+
+						if (maxWins && y > 0) || (!maxWins && y < 0) {
+							votes[m.target]++
+						} else {
+							votes[m.alternate]++
+						}
+						if i == 0 || (maxWins && y > score) || (!maxWins && y < score) {
+							best, score = m.target, y
+						}
+					}
+
+					if strings.Contains(method, "OneAgainstOne") {
+						best = ""
+						for _, category := range []string{"a", "b", "c"} {
+							if best == "" || votes[category] > votes[best] {
+								best = category
+							}
+						}
+					}
+
+					r, err := runScript(s, map[string]interface{}{"x1": x[0], "x2": x[1]})
+					assert.NoError(t, err)
+					assert.Equal(t, best, r.Value, x)
+					assert.Equal(t, best, r.Output("Class"), x)
+				}
+
+				r, err := runScript(s, map[string]interface{}{"x1": 1})
+				assert.NoError(t, err)
+				assert.Nil(t, r)
+			})
+		}
+	}
+}
+
+func TestSupportVectorMachineModel_Binary(t *testing.T) {
+	doc := `<PMML version="4.4">
+		<DataDictionary>
+			<DataField name="x" optype="continuous" dataType="double"/>
+			<DataField name="y" optype="continuous" dataType="double"/>
+			<DataField name="fraud" optype="categorical" dataType="integer"/>
+		</DataDictionary>
+		<SupportVectorMachineModel modelName="binary" functionName="classification" svmRepresentation="Coefficients" threshold="0.5" alternateBinaryTargetCategory="0">
+			<MiningSchema><MiningField name="x"/><MiningField name="y"/><MiningField name="fraud" usageType="predicted"/></MiningSchema>
+			<LinearKernelType/>
+			<VectorDictionary>
+				<VectorFields><FieldRef field="x"/><FieldRef field="y"/></VectorFields>
+			</VectorDictionary>
+			<SupportVectorMachine targetCategory="1">
+				<Coefficients absoluteValue="1"><Coefficient value="2"/><Coefficient value="-1"/></Coefficients>
+			</SupportVectorMachine>
+		</SupportVectorMachineModel>
+	</PMML>`
+
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "{target=1, alternate=0, threshold=0.5, intercept=1, coefficients={2, -1}},")
+
+	s := makeScript(string(code))
+	r, err := runScript(s, map[string]interface{}{"x": -1, "y": 0})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, r.Value)
+
+	r, err = runScript(s, map[string]interface{}{"x": 1, "y": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, r.Value)
+
+	// If the maximum wins, the target category is chosen above the threshold
+	code, err = Convert([]byte(strings.Replace(doc, `threshold="0.5"`, `threshold="0.5" maxWins="true"`, 1)))
+	assert.NoError(t, err)
+
+	s = makeScript(string(code))
+	r, err = runScript(s, map[string]interface{}{"x": -1, "y": 0})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, r.Value)
+
+	r, err = runScript(s, map[string]interface{}{"x": 1, "y": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, r.Value)
+}
+
+func TestSupportVectorMachineModel_Regression(t *testing.T) {
+	code, err := Convert([]byte(`<PMML version="4.4">
+		<SupportVectorMachineModel modelName="amount" functionName="regression">
+			<MiningSchema><MiningField name="x"/><MiningField name="y" usageType="predicted"/></MiningSchema>
+			<PolynomialKernelType/>
+			<VectorDictionary>
+				<VectorFields><FieldRef field="x"/></VectorFields>
+				<VectorInstance id="a"><REAL-SparseArray n="1"/></VectorInstance>
+				<VectorInstance id="b"><Array n="1" type="real">2</Array></VectorInstance>
+			</VectorDictionary>
+			<SupportVectorMachine>
+				<SupportVectors><SupportVector vectorId="a"/><SupportVector vectorId="b"/></SupportVectors>
+				<Coefficients absoluteValue="3"><Coefficient value="0.5"/><Coefficient value="2"/></Coefficients>
+			</SupportVectorMachine>
+		</SupportVectorMachineModel>
+	</PMML>`))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "kernel={type='polynomial', gamma=1, coef0=1, degree=1}")
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 4})
+	assert.NoError(t, err)
+	assert.InDelta(t, 3+0.5*(0+1)+2*(8+1), r.Value, 1e-9)
+}
+
+func TestSupportVectorMachineModel_Error(t *testing.T) {
+	linear := `<LinearKernelType/>`
+	vector := func(instance string) string {
+		return `<VectorDictionary><VectorFields><FieldRef field="x"/></VectorFields><VectorInstance id="1">` + instance + `</VectorInstance></VectorDictionary>`
+	}
+	machine := func(attrs, coefficients string) string {
+		return `<SupportVectorMachine ` + attrs + `><SupportVectors><SupportVector vectorId="1"/></SupportVectors>` +
+			`<Coefficients absoluteValue="0">` + coefficients + `</Coefficients></SupportVectorMachine>`
+	}
+
+	dense := vector(`<Array n="1" type="real">1</Array>`)
+	valid := machine(`targetCategory="a" alternateTargetCategory="b"`, `<Coefficient value="1"/>`)
+	tests := []struct {
+		options string
+		body    string
+		err     string
+	}{
+		{options: `classificationMethod="OneAgainstSome"`, body: linear + dense + valid, err: "classification method OneAgainstSome is not supported"},
+		{options: `svmRepresentation="Matrix"`, body: linear + dense + valid, err: "representation Matrix is not supported"},
+		{options: `svmRepresentation="Coefficients"`, body: `<RadialBasisKernelType gamma="1"/>` + dense + valid, err: "coefficients of x require a linear kernel"},
+		{body: dense + valid, err: "model x has no kernel type"},
+		{body: linear + dense, err: "model x has no support vector machine"},
+		{body: linear + dense + machine(``, `<Coefficient value="1"/>`), err: "support vector machine of x has no target category"},
+		{options: `classificationMethod="OneAgainstOne"`, body: linear + dense + machine(`targetCategory="a"`, `<Coefficient value="1"/>`) + valid,
+			err: "support vector machine of x has no alternate target category"},
+		{body: linear + dense + machine(`targetCategory="a"`, ``), err: "support vector machine of x has 1 support vectors but 0 coefficients"},
+		{body: linear + vector(`<Array n="2" type="real">1 2</Array>`) + valid, err: "vector 1 has 2 values instead of 1"},
+		{body: linear + vector(`<REAL-SparseArray n="1"><Indices>2</Indices><REAL-Entries>1</REAL-Entries></REAL-SparseArray>`) + valid, err: "vector 1 is invalid"},
+	}
+
+	for _, tc := range tests {
+		_, err := Convert([]byte(`<PMML version="4.4">
+			<DataDictionary>
+				<DataField name="x" optype="continuous" dataType="double"/>
+				<DataField name="y" optype="categorical" dataType="string"/>
+			</DataDictionary>
+			<SupportVectorMachineModel modelName="x" functionName="classification" ` + tc.options + `>
+				<MiningSchema><MiningField name="x"/><MiningField name="y" usageType="predicted"/></MiningSchema>
+				` + tc.body + `
+			</SupportVectorMachineModel>
+		</PMML>`))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
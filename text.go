@@ -0,0 +1,121 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The term weights and normalizations of the text models.
+var (
+	localTermWeights = map[string]bool{
+		"termFrequency":                    true,
+		"binary":                           true,
+		"logarithmic":                      true,
+		"augmentedNormalizedTermFrequency": true,
+	}
+	globalTermWeights = map[string]bool{
+		"inverseDocumentFrequency": true,
+		"none":                     true,
+		"GFIDF":                    true,
+		"normal":                   true,
+		"probabilisticInverse":     true,
+	}
+	documentNormalizations = map[string]bool{
+		"none":   true,
+		"cosine": true,
+	}
+)
+
+// TextModel generates the LUA code for the element. The document is the value of the first
+// active field, which is tokenized and compared with every document of the corpus. The term
+// frequencies of the corpus are embedded as a row per document, in the order of the terms.
+func (s *Scope) TextModel(v schema.TextModel, global *Scope) *Scope {
+	field := ""
+	for _, f := range v.MiningSchema.MiningFields {
+		if f.IsInput() {
+			field = f.Name
+			break
+		}
+	}
+
+	norm := v.Normalization
+	options := NewStatement().Append("{field=").String(field).
+		Append(", localWeights=").String(norm.LocalTermWeights).
+		Append(", globalWeights=").String(norm.GlobalTermWeights).
+		Append(", normalization=").String(norm.DocumentNormalization).
+		Append(", similarity=").String(v.Similarity.SimilarityType).
+		Append("}")
+
+	switch {
+	case field == "":
+		options.Error("model %s has no document field", v.ModelName)
+	case !localTermWeights[norm.LocalTermWeights]:
+		options.Error("local term weights %s are not supported", norm.LocalTermWeights)
+	case !globalTermWeights[norm.GlobalTermWeights]:
+		options.Error("global term weights %s are not supported", norm.GlobalTermWeights)
+	case !documentNormalizations[norm.DocumentNormalization]:
+		options.Error("document normalization %s is not supported", norm.DocumentNormalization)
+	case v.Similarity.SimilarityType != "cosine" && v.Similarity.SimilarityType != "euclidean":
+		options.Error("similarity type %s is not supported", v.Similarity.SimilarityType)
+	}
+
+	terms, err := v.TextDictionary.Terms.Strings()
+	switch {
+	case err != nil:
+		options.Error("dictionary of %s is invalid: %v", v.ModelName, err)
+	case len(terms) == 0:
+		options.Error("dictionary of %s has no terms", v.ModelName)
+	case v.NumberOfTerms > 0 && len(terms) != v.NumberOfTerms:
+		options.Error("dictionary of %s has %d terms instead of %d", v.ModelName, len(terms), v.NumberOfTerms)
+	}
+
+	dictionary := NewStatement().Append("{")
+	for i, term := range terms {
+		dictionary.String(term)
+		if i+1 < len(terms) {
+			dictionary.Append(", ")
+		}
+	}
+	dictionary.Append("}")
+
+	documents := NewScope()
+	matrix, err := v.DocumentTermMatrix.Floats()
+	switch {
+	case err != nil:
+		documents.With(NewStatement().Error("document term matrix of %s is invalid: %v", v.ModelName, err))
+	case len(matrix) != len(v.TextCorpus):
+		documents.With(NewStatement().Error("document term matrix of %s has %d rows instead of %d", v.ModelName, len(matrix), len(v.TextCorpus)))
+	case len(v.TextCorpus) == 0:
+		documents.With(NewStatement().Error("corpus of %s has no documents", v.ModelName))
+	default:
+		for i, doc := range v.TextCorpus {
+			documents.With(NewStatement().TextDocument(doc, matrix[i], len(terms)))
+		}
+	}
+
+	global.Require("text")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or text.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append(", ").Statement(dictionary).Append(", {"),
+			documents,
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// TextDocument generates the LUA code for the element, with the frequencies of the terms in the
+// document.
+func (s *Statement) TextDocument(v schema.TextDocument, counts []float64, size int) *Statement {
+	if len(counts) != size {
+		return s.Error("document %s has %d term frequencies instead of %d", v.ID, len(counts), size)
+	}
+
+	s.Append("{id=").String(v.ID)
+	if v.Name != "" {
+		s.Append(", name=").String(v.Name)
+	}
+	return s.Append(", counts=").Floats(counts).Append("},")
+}
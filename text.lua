@@ -0,0 +1,184 @@
+local text = {}
+
+-- NewModel creates a text model with its options, the terms of its dictionary and the documents
+-- of its corpus. The global weights of the terms and the weighted vectors of the documents are
+-- computed once.
+function text.NewModel(options, terms, documents)
+    local m = options
+    m.terms = {}
+    for i=1, #terms do
+        m.terms[i] = text.Tokenize(terms[i])
+    end
+
+    m.global = text.GlobalWeights(m, documents)
+    m.documents = documents
+    for i=1, #documents do
+        documents[i].vector = text.Vector(m, documents[i].counts)
+    end
+
+    -- Function which compares the document with the corpus and returns the result table
+    m.eval = function(v)
+        return text.Score(m, v)
+    end
+    return m
+end
+
+-- Score returns the document of the corpus which is the most similar to the document, along
+-- with the similarity or the distance to every document of the corpus.
+function text.Score(m, v)
+    local doc = v[m.field]
+    if Unknown(doc) then
+        return nil
+    end
+
+    local x = text.Vector(m, text.Count(m, text.Tokenize(tostring(doc))))
+    local r = {affinities = {}}
+    local best, score = nil, nil
+    for i=1, #m.documents do
+        local d = m.documents[i]
+        local s = text.Similarity(m, x, d.vector)
+        r.affinities[d.id] = s
+        if best == nil or (m.similarity == 'euclidean' and s < score) or (m.similarity == 'cosine' and s > score) then
+            best = d
+            score = s
+        end
+    end
+
+    r.value = best.id
+    r.entityId = best.id
+    r.displayValue = best.name
+    r.affinity = score
+    return r
+end
+
+-- Tokenize returns the lower case words of the text.
+function text.Tokenize(s)
+    local words = {}
+    for w in string.gmatch(string.lower(s), "%w+") do
+        table.insert(words, w)
+    end
+    return words
+end
+
+-- Count returns the frequency of each term of the dictionary in the words of a document. The
+-- terms with several words are counted as phrases.
+function text.Count(m, words)
+    local counts = {}
+    for i=1, #m.terms do
+        local term, n = m.terms[i], 0
+        if #term > 0 then
+            for j=1, #words - #term + 1 do
+                local match = true
+                for k=1, #term do
+                    if words[j+k-1] ~= term[k] then
+                        match = false
+                        break
+                    end
+                end
+                if match then
+                    n = n + 1
+                end
+            end
+        end
+        counts[i] = n
+    end
+    return counts
+end
+
+-- Vector returns the weights of the terms of a document, from their frequencies in the document
+-- and their global weights, normalized if required.
+function text.Vector(m, counts)
+    local max = 0
+    for i=1, #counts do
+        max = math.max(max, counts[i])
+    end
+
+    local out, norm = {}, 0
+    for i=1, #counts do
+        local f, w = counts[i], 0
+        if f > 0 then
+            w = text.localWeights[m.localWeights](f, max) * m.global[i]
+        end
+        out[i] = w
+        norm = norm + w * w
+    end
+
+    if m.normalization == 'cosine' and norm > 0 then
+        norm = math.sqrt(norm)
+        for i=1, #out do
+            out[i] = out[i] / norm
+        end
+    end
+    return out
+end
+
+-- GlobalWeights returns the weight of each term, from its frequencies in the documents.
+function text.GlobalWeights(m, documents)
+    local out, n = {}, #documents
+    for i=1, #m.terms do
+        local df, gf, sq = 0, 0, 0
+        for j=1, n do
+            local f = documents[j].counts[i]
+            if f > 0 then
+                df = df + 1
+            end
+            gf = gf + f
+            sq = sq + f * f
+        end
+
+        local w = 0
+        if m.globalWeights == 'none' then
+            w = 1
+        elseif df > 0 then
+            if m.globalWeights == 'inverseDocumentFrequency' then
+                w = math.log(n / df)
+            elseif m.globalWeights == 'GFIDF' then
+                w = gf / df
+            elseif m.globalWeights == 'normal' then
+                w = 1 / math.sqrt(sq)
+            elseif m.globalWeights == 'probabilisticInverse' and n > df then
+                w = math.log((n - df) / df)
+            end
+        end
+        out[i] = w
+    end
+    return out
+end
+
+-- Similarity returns the cosine similarity or the euclidean distance between two vectors. The
+-- cosine similarity with an empty vector is 0.
+function text.Similarity(m, x, y)
+    local dot, nx, ny, sum = 0, 0, 0, 0
+    for i=1, #x do
+        dot = dot + x[i] * y[i]
+        nx = nx + x[i] * x[i]
+        ny = ny + y[i] * y[i]
+        sum = sum + (x[i] - y[i]) ^ 2
+    end
+
+    if m.similarity == 'euclidean' then
+        return math.sqrt(sum)
+    end
+    if nx == 0 or ny == 0 then
+        return 0
+    end
+    return dot / math.sqrt(nx * ny)
+end
+
+-- The local weights of the terms, by their name in the TextModelNormalization
+text.localWeights = {
+    termFrequency = function(f, max)
+        return f
+    end,
+    binary = function(f, max)
+        return 1
+    end,
+    logarithmic = function(f, max)
+        return math.log(1 + f)
+    end,
+    augmentedNormalizedTermFrequency = function(f, max)
+        return 0.5 * (1 + f / max)
+    end,
+}
+
+return text
@@ -0,0 +1,188 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The term frequencies of the corpus of the fixture
+var textCorpus = [][]float64{
+	{3, 2, 0, 0},
+	{1, 1, 4, 0},
+	{1, 0, 0, 5},
+}
+
+func TestTextModel(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/text1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	// The inverse document frequencies of the terms, with the vectors normalized by their length
+	idf := []float64{0, math.Log(1.5), math.Log(3), math.Log(3)}
+	vector := func(counts []float64) []float64 {
+		out, norm := make([]float64, len(counts)), 0.0
+		for i, f := range counts {
+			out[i] = f * idf[i]
+			norm += out[i] * out[i]
+		}
+		for i := range out {
+			if norm > 0 {
+				out[i] /= math.Sqrt(norm)
+			}
+		}
+		return out
+	}
+
+	s := makeScript(string(code))
+	tests := []struct {
+		input  string
+		counts []float64
+		expect string
+		title  string
+	}{
+		{input: "A model of the data, and another Model.", counts: []float64{1, 2, 0, 0}, expect: "a1", title: "Data Models"},
+		{input: "Machine learning: a machine, learning from data.", counts: []float64{1, 0, 1, 0}, expect: "a2", title: "Learning Machines"},
+		{input: "FOOTBALL data and football models", counts: []float64{1, 0, 0, 2}, expect: "a3", title: "Football Data"},
+		{input: "learning football machine learning", counts: []float64{0, 0, 1, 1}, expect: "a3", title: "Football Data"},
+	}
+
+	for _, tc := range tests {
+		r, err := runScript(s, map[string]interface{}{"document": tc.input})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expect, r.Value, tc.input)
+		assert.Equal(t, tc.expect, r.Output("Article"), tc.input)
+		assert.Equal(t, tc.title, r.Output("Title"), tc.input)
+
+		x := vector(tc.counts)
+		for i, id := range []string{"a1", "a2", "a3"} {
+			y, dot := vector(textCorpus[i]), 0.0
+			for j := range x {
+				dot += x[j] * y[j]
+			}
+			assert.InDelta(t, dot, r.Affinities[id], 1e-9, tc.input)
+			if id == tc.expect {
+				assert.InDelta(t, dot, r.Output("Similarity"), 1e-9, tc.input)
+			}
+		}
+	}
+
+	// Without any term of the dictionary, every document is equally dissimilar
+	r, err := runScript(s, map[string]interface{}{"document": "nothing relevant"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a1", r.Value)
+	assert.Equal(t, 0.0, r.Output("Similarity"))
+
+	// Without a document, there is no result
+	r, err = runScript(s, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestTextModel_Weights(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/text1.xml")
+	assert.NoError(t, err)
+
+	normalization := `<TextModelNormalization localTermWeights="termFrequency" globalTermWeights="inverseDocumentFrequency" documentNormalization="cosine"/>
+<TextModelSimiliarity similarityType="cosine"/>`
+
+	// The document contains "data" twice and "model" once
+	document := "data model data"
+	tests := []struct {
+		normalization string
+		local         func(f, max float64) float64
+		global        func(i int) float64
+		euclidean     bool
+	}{
+		{
+			normalization: `<TextModelNormalization localTermWeights="binary" globalTermWeights="none"/>`,
+			local:         func(f, max float64) float64 { return 1 },
+			global:        func(i int) float64 { return 1 },
+		},
+		{
+			normalization: `<TextModelNormalization localTermWeights="logarithmic" globalTermWeights="GFIDF"/><TextModelSimiliarity similarityType="euclidean"/>`,
+			local:         func(f, max float64) float64 { return math.Log(1 + f) },
+			global:        func(i int) float64 { return []float64{5 / 3.0, 1.5, 4, 5}[i] },
+			euclidean:     true,
+		},
+		{
+			normalization: `<TextModelNormalization localTermWeights="augmentedNormalizedTermFrequency" globalTermWeights="normal"/>`,
+			local:         func(f, max float64) float64 { return 0.5 * (1 + f/max) },
+			global:        func(i int) float64 { return 1 / []float64{math.Sqrt(11), math.Sqrt(5), 4, 5}[i] },
+		},
+		{
+			normalization: `<TextModelNormalization globalTermWeights="probabilisticInverse"/>`,
+			local:         func(f, max float64) float64 { return f },
+			global:        func(i int) float64 { return []float64{0, math.Log(0.5), math.Log(2), math.Log(2)}[i] },
+		},
+	}
+
+	for _, tc := range tests {
+		code, err := Convert([]byte(strings.Replace(string(b), normalization, tc.normalization, 1)))
+		assert.NoError(t, err)
+
+		vector := func(counts []float64) []float64 {
+			max := 0.0
+			for _, f := range counts {
+				max = math.Max(max, f)
+			}
+
+			out := make([]float64, len(counts))
+			for i, f := range counts {
+				if f > 0 {
+					out[i] = tc.local(f, max) * tc.global(i)
+				}
+			}
+			return out
+		}
+
+		r, err := runScript(makeScript(string(code)), map[string]interface{}{"document": document})
+		assert.NoError(t, err)
+
+		x := vector([]float64{2, 1, 0, 0})
+		for i, id := range []string{"a1", "a2", "a3"} {
+			y := vector(textCorpus[i])
+			dot, nx, ny, sum := 0.0, 0.0, 0.0, 0.0
+			for j := range x {
+				dot += x[j] * y[j]
+				nx += x[j] * x[j]
+				ny += y[j] * y[j]
+				sum += (x[j] - y[j]) * (x[j] - y[j])
+			}
+
+			expect := dot / math.Sqrt(nx*ny)
+			if tc.euclidean {
+				expect = math.Sqrt(sum)
+			}
+			assert.InDelta(t, expect, r.Affinities[id], 1e-9, tc.normalization)
+		}
+	}
+}
+
+func TestTextModel_Errors(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/text1.xml")
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(string(b), `localTermWeights="termFrequency"`, `localTermWeights="unknown"`, 1), err: "local term weights unknown are not supported"},
+		{doc: strings.Replace(string(b), `globalTermWeights="inverseDocumentFrequency"`, `globalTermWeights="unknown"`, 1), err: "global term weights unknown are not supported"},
+		{doc: strings.Replace(string(b), `documentNormalization="cosine"`, `documentNormalization="unknown"`, 1), err: "document normalization unknown is not supported"},
+		{doc: strings.Replace(string(b), `similarityType="cosine"`, `similarityType="jaccard"`, 1), err: "similarity type jaccard is not supported"},
+		{doc: strings.Replace(string(b), `numberOfTerms="4"`, `numberOfTerms="5"`, 1), err: "dictionary of articles has 4 terms instead of 5"},
+		{doc: strings.Replace(string(b), `nbRows="3"`, `nbRows="2"`, 1), err: "matrix has more than 2 rows"},
+		{doc: strings.Replace(strings.Replace(string(b), `nbRows="3" `, ``, 1), `<Array n="4" type="real">1 0 0 5</Array>`, ``, 1), err: "document term matrix of articles has 2 rows instead of 3"},
+		{doc: strings.Replace(string(b), `<Array n="4" type="real">1 0 0 5</Array>`, `<Array n="4" type="real">1 0 0</Array>`, 1), err: "row 3 of the matrix has 3 values instead of 4"},
+		{doc: strings.Replace(string(b), `<MiningField name="document"/>`, `<MiningField name="document" usageType="target"/>`, 1), err: "model articles has no document field"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,282 @@
+package pmml2lua
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// The trends of the exponential smoothing which can be forecast
+var trends = map[string]bool{
+	"additive": true, "damped_additive": true, "multiplicative": true, "damped_multiplicative": true,
+}
+
+// The transformations of the series which can be reverted on the forecasts
+var transformations = map[string]bool{
+	"none": true, "logarithmic": true, "squareroot": true,
+}
+
+// TimeSeriesModel generates the LUA code for the element. The forecast horizon is given by the
+// only active field of the mining schema, and the model which was the best fit is forecast.
+func (s *Scope) TimeSeriesModel(v schema.TimeSeriesModel, global *Scope) *Scope {
+	var active []string
+	for _, f := range v.MiningSchema.MiningFields {
+		if f.UsageType == "" || f.UsageType == "active" {
+			active = append(active, f.Name)
+		}
+	}
+
+	options := NewStatement()
+	switch len(active) {
+	case 0:
+		options.Error("model %s requires an active field for the forecast horizon", v.ModelName)
+	case 1:
+		options.Append("{horizon=").String(active[0])
+	default:
+		options.Error("model %s has an ambiguous forecast horizon in %s", v.ModelName, strings.Join(active, ", "))
+	}
+
+	switch {
+	case v.BestFit == "ExponentialSmoothing" && v.ExponentialSmoothing != nil:
+		options.ExponentialSmoothing(*v.ExponentialSmoothing)
+	case v.BestFit == "ARIMA" && v.ARIMA != nil:
+		options.ARIMA(*v.ARIMA, v.Series())
+	default:
+		options.Error("best fit %s of model %s is not supported", v.BestFit, v.ModelName)
+	}
+
+	global.Require("timeseries")
+	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
+		With(
+			Append("model = model or {}"),
+			Append("model.%s = model.%s or timeseries.NewModel(", v.ModelName, v.ModelName).
+				Statement(options).Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
+}
+
+// ExponentialSmoothing generates the LUA code for the element, which only retains the final
+// smoothed values of the level, the trend and the seasonal indices.
+func (s *Statement) ExponentialSmoothing(v schema.ExponentialSmoothing) *Statement {
+	s.Append(", kind='smoothing', transformation=").String(v.Transformation).
+		Append(", level=%s", formatFloat(v.Level.SmoothedValue))
+	if !transformations[v.Transformation] {
+		s.Error("transformation %s is not supported", v.Transformation)
+	}
+
+	if t := v.Trend; t != nil {
+		s.Append(", trend={kind=").String(t.Trend).Append(", value=%s", formatFloat(t.SmoothedValue))
+		if t.Trend == "damped_additive" || t.Trend == "damped_multiplicative" {
+			s.Append(", phi=%s", formatFloat(t.Phi))
+		}
+		s.Append("}")
+		if !trends[t.Trend] {
+			s.Error("trend %s is not supported", t.Trend)
+		}
+	}
+
+	if season := v.Seasonality; season != nil {
+		var values []float64
+		var err error
+		if season.Array != nil {
+			values, err = season.Array.Floats()
+		}
+
+		switch {
+		case season.Type != "additive" && season.Type != "multiplicative":
+			return s.Error("seasonality %s is not supported", season.Type)
+		case err != nil:
+			return s.Error("seasonal indices are invalid: %v", err)
+		case season.Period < 1 || len(values) != season.Period:
+			return s.Error("seasonality has %d indices for a period of %d", len(values), season.Period)
+		}
+
+		s.Append(", season={kind=").String(season.Type).
+			Append(", period=%d, phase=%d, values=", season.Period, season.Phase).
+			Floats(values).Append("}")
+	}
+	return s
+}
+
+// ARIMA generates the LUA code for the element. The lag polynomials of the nonseasonal and the
+// seasonal components are multiplied together, and the original series is embedded so that it
+// can be differenced and then integrated back with the forecasts.
+func (s *Statement) ARIMA(v schema.ARIMA, series *schema.TimeSeries) *Statement {
+	if !transformations[v.Transformation] {
+		return s.Error("transformation %s is not supported", v.Transformation)
+	}
+
+	var ar, ma, residuals, sar, sma, seasonal []float64
+	var diff []int
+	var err error
+	period := 0
+	if c := v.SeasonalComponent; c != nil {
+		if period = c.Period; period < 1 {
+			return s.Error("seasonal component requires a positive period")
+		}
+		if sar, sma, seasonal, err = arimaComponent(c.P, c.Q, c.AR, c.MA); err != nil {
+			return s.Error("seasonal component is invalid: %v", err)
+		}
+		for i := 0; i < c.D; i++ {
+			diff = append(diff, period)
+		}
+	}
+
+	if c := v.NonseasonalComponent; c != nil {
+		if ar, ma, residuals, err = arimaComponent(c.P, c.Q, c.AR, c.MA); err != nil {
+			return s.Error("nonseasonal component is invalid: %v", err)
+		}
+		for i := 0; i < c.D; i++ {
+			diff = append(diff, 1)
+		}
+	}
+
+	// The residuals of the seasonal component go further back in time when they are present
+	if len(seasonal) > len(residuals) {
+		residuals = seasonal
+	}
+
+	ar, ma = lagPolynomial(ar, sar, period), lagPolynomial(ma, sma, period)
+	s.Append(", kind='arima', transformation=").String(v.Transformation).
+		Append(", constant=%s, ar=", formatFloat(v.ConstantTerm)).Floats(ar).
+		Append(", ma=").Floats(ma).Append(", diff={")
+	for i, lag := range diff {
+		s.Append("%d", lag)
+		if i+1 < len(diff) {
+			s.Append(", ")
+		}
+	}
+	s.Append("}")
+
+	// The exact least squares forecasts start from the final state of the likelihood estimation
+	conditional := true
+	switch stat := v.MaximumLikelihoodStat; {
+	case v.PredictionMethod == "conditionalLeastSquares":
+	case v.PredictionMethod != "exactLeastSquares":
+		return s.Error("prediction method %s is not supported", v.PredictionMethod)
+	case stat != nil && stat.KalmanState != nil:
+		conditional = false
+		s.KalmanState(*stat.KalmanState, len(ar), len(ma))
+	case stat != nil && stat.ThetaRecursionState != nil:
+		if residuals, err = stat.ThetaRecursionState.FinalNoise.Floats(); err != nil {
+			return s.Error("final noise is invalid: %v", err)
+		}
+	default:
+		return s.Error("exact least squares require the maximum likelihood state")
+	}
+
+	if conditional {
+		s.Append(", residuals=").Floats(residuals)
+	}
+
+	// The series must be long enough to be differenced and to start the autoregression
+	values := seriesValues(series)
+	required := 0
+	for _, lag := range diff {
+		required += lag
+	}
+	if conditional {
+		required += len(ar)
+	}
+	if len(values) < required {
+		return s.Error("time series has %d values but at least %d are required", len(values), required)
+	}
+
+	return s.Append(", series=").Floats(values)
+}
+
+// KalmanState generates the LUA code for the element. The state vector follows the state space
+// representation of the differenced series, with the autoregressive coefficients in the first
+// column of its transition matrix.
+func (s *Statement) KalmanState(v schema.KalmanState, p, q int) *Statement {
+	state, err := v.FinalStateVector.Floats()
+	if err != nil {
+		return s.Error("final state vector is invalid: %v", err)
+	}
+
+	size := q + 1
+	if p > size {
+		size = p
+	}
+	if len(state) != size {
+		return s.Error("final state vector has %d values instead of %d", len(state), size)
+	}
+
+	observation := []float64{1}
+	if v.HVector != nil {
+		if observation, err = v.HVector.Floats(); err != nil {
+			return s.Error("observation vector is invalid: %v", err)
+		}
+	}
+
+	return s.Append(", state={x=").Floats(state).Append(", h=").Floats(observation).Append("}")
+}
+
+// arimaComponent returns the autoregressive and moving average coefficients of a component, along
+// with its residuals, after checking them against the orders of the component.
+func arimaComponent(p, q int, ar *schema.AR, ma *schema.MA) (phi, theta, residuals []float64, err error) {
+	if ar != nil {
+		if phi, err = ar.Array.Floats(); err != nil {
+			return
+		}
+	}
+
+	if ma != nil && ma.Coefficients != nil {
+		if theta, err = ma.Coefficients.Floats(); err != nil {
+			return
+		}
+	}
+
+	if ma != nil && ma.Residuals != nil {
+		if residuals, err = ma.Residuals.Floats(); err != nil {
+			return
+		}
+	}
+
+	switch {
+	case len(phi) != p:
+		err = fmt.Errorf("%d autoregressive coefficients instead of %d", len(phi), p)
+	case len(theta) != q:
+		err = fmt.Errorf("%d moving average coefficients instead of %d", len(theta), q)
+	}
+	return
+}
+
+// lagPolynomial multiplies the nonseasonal lag polynomial (1 - a1 B - a2 B^2 ...) with the seasonal
+// one (1 - b1 B^s - b2 B^2s ...) and returns the coefficients c of the product, written in the same
+// form (1 - c1 B - c2 B^2 ...).
+func lagPolynomial(nonseasonal, seasonal []float64, period int) []float64 {
+	out := make([]float64, len(nonseasonal)+len(seasonal)*period)
+	copy(out, nonseasonal)
+	for j, b := range seasonal {
+		lag := (j + 1) * period
+		out[lag-1] += b
+		for i, a := range nonseasonal {
+			out[lag+i] -= a * b
+		}
+	}
+	return out
+}
+
+// seriesValues returns the values of the time series, ordered by their index.
+func seriesValues(series *schema.TimeSeries) []float64 {
+	if series == nil {
+		return nil
+	}
+
+	values := make([]schema.TimeValue, len(series.TimeValues))
+	copy(values, series.TimeValues)
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].Index < values[j].Index
+	})
+
+	out := make([]float64, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.Value)
+	}
+	return out
+}
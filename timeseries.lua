@@ -0,0 +1,192 @@
+local timeseries = {}
+
+-- NewModel creates a time series model with its options, which contain either the final smoothed
+-- values of an exponential smoothing or the coefficients and the series of an ARIMA model.
+function timeseries.NewModel(options)
+    local m = options
+    if m.series ~= nil then
+        for i=1, #m.series do
+            m.series[i] = timeseries.Transform(m.series[i], m.transformation)
+        end
+    end
+
+    -- Function which forecasts the series and returns the result table
+    m.eval = function(v)
+        return timeseries.Score(m, v)
+    end
+    return m
+end
+
+-- Score forecasts the series up to the horizon given by the record and returns the forecast of
+-- the last step, along with the forecasts of every step until then.
+function timeseries.Score(m, v)
+    local h = tonumber(v[m.horizon])
+    if h == nil or h < 1 then
+        return nil
+    end
+
+    local forecasts
+    h = math.floor(h)
+    if m.kind == 'arima' then
+        forecasts = timeseries.Arima(m, h)
+    else
+        forecasts = timeseries.Smoothing(m, h)
+    end
+
+    for i=1, h do
+        forecasts[i] = timeseries.Inverse(forecasts[i], m.transformation)
+    end
+    return {value = forecasts[h], forecasts = forecasts}
+end
+
+-- Transform applies the transformation of the model to a value of the series.
+function timeseries.Transform(x, transformation)
+    if transformation == 'logarithmic' then
+        return math.log(x)
+    elseif transformation == 'squareroot' then
+        return math.sqrt(x)
+    end
+    return x
+end
+
+-- Inverse reverts the transformation of the model on a forecast.
+function timeseries.Inverse(x, transformation)
+    if transformation == 'logarithmic' then
+        return math.exp(x)
+    elseif transformation == 'squareroot' then
+        return x * x
+    end
+    return x
+end
+
+-- Smoothing forecasts the exponential smoothing out of its final level, trend and seasonal
+-- indices. The damped trends add up the powers of the damping factor instead of the steps.
+function timeseries.Smoothing(m, h)
+    local out, damping = {}, 0
+    for k=1, h do
+        local y, t, s = m.level, m.trend, m.season
+        if t ~= nil then
+            local steps = k
+            if t.phi ~= nil then
+                damping = damping + t.phi ^ k
+                steps = damping
+            end
+
+            if t.kind == 'additive' or t.kind == 'damped_additive' then
+                y = y + steps * t.value
+            else
+                y = y * t.value ^ steps
+            end
+        end
+
+        if s ~= nil then
+            local index = s.values[(s.phase + k - 1) % s.period + 1]
+            if s.kind == 'multiplicative' then
+                y = y * index
+            else
+                y = y + index
+            end
+        end
+        out[k] = y
+    end
+    return out
+end
+
+-- Arima forecasts the differenced series with the autoregressive and moving average terms, and
+-- then integrates the forecasts back, starting from the last differencing.
+function timeseries.Arima(m, h)
+    local levels = {m.series}
+    for i=1, #m.diff do
+        levels[i + 1] = timeseries.Difference(levels[i], m.diff[i])
+    end
+
+    local forecasts
+    if m.state ~= nil then
+        forecasts = timeseries.Kalman(m, h)
+    else
+        forecasts = timeseries.Recursion(m, levels[#levels], h)
+    end
+
+    for i=#m.diff, 1, -1 do
+        forecasts = timeseries.Integrate(levels[i], forecasts, m.diff[i])
+    end
+    return forecasts
+end
+
+-- Difference returns the differences of the series at the lag.
+function timeseries.Difference(y, lag)
+    local out = {}
+    for i=lag + 1, #y do
+        out[i - lag] = y[i] - y[i - lag]
+    end
+    return out
+end
+
+-- Integrate reverts the differencing at the lag of the forecasts, out of the series which was
+-- differenced. The forecasts further than the lag are integrated from the previous forecasts.
+function timeseries.Integrate(y, forecasts, lag)
+    local n, out = #y, {}
+    for k=1, #forecasts do
+        local i = n + k - lag
+        if i > n then
+            out[k] = forecasts[k] + out[i - n]
+        else
+            out[k] = forecasts[k] + y[i]
+        end
+    end
+    return out
+end
+
+-- Recursion forecasts the differenced series with the conditional least squares, where the
+-- future residuals are zero and the past ones are aligned with the end of the series.
+function timeseries.Recursion(m, w, h)
+    local n, out, e = #w, {}, m.residuals
+    for k=1, h do
+        local y = m.constant
+        for i=1, #m.ar do
+            local t = n + k - i
+            if t > n then
+                y = y + m.ar[i] * out[t - n]
+            else
+                y = y + m.ar[i] * w[t]
+            end
+        end
+
+        for j=k, #m.ma do
+            local t = #e - (j - k)
+            if t >= 1 then
+                y = y - m.ma[j] * e[t]
+            end
+        end
+        out[k] = y
+    end
+    return out
+end
+
+-- Kalman forecasts the differenced series out of the final state of its state space model,
+-- whose transition matrix has the autoregressive coefficients in its first column and ones on
+-- its superdiagonal. The state is the deviation from the mean of the differenced series.
+function timeseries.Kalman(m, h)
+    local sum = 0
+    for i=1, #m.ar do
+        sum = sum + m.ar[i]
+    end
+
+    local mean, x, out = m.constant / (1 - sum), m.state.x, {}
+    for k=1, h do
+        local next = {}
+        for i=1, #x do
+            next[i] = (m.ar[i] or 0) * x[1] + (x[i + 1] or 0)
+        end
+        x = next
+
+        local y = mean
+        for i=1, #m.state.h do
+            y = y + m.state.h[i] * x[i]
+        end
+        out[k] = y
+    end
+    return out
+end
+
+return timeseries
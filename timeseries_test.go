@@ -0,0 +1,186 @@
+package pmml2lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeSeriesModel_ExponentialSmoothing(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/timeseries1.xml")
+	assert.NoError(t, err)
+
+	seasons := []float64{0.9, 1.1, 1.2, 0.8}
+	tests := []struct {
+		trend    string
+		forecast func(k int) float64
+	}{
+		{trend: `trend="damped_additive"`, forecast: func(k int) float64 {
+			damping := 0.0
+			for i := 1; i <= k; i++ {
+				damping += math.Pow(0.8, float64(i))
+			}
+			return (120 + damping*5) * seasons[(k-1)%4]
+		}},
+		{trend: `trend="additive"`, forecast: func(k int) float64 {
+			return (120 + float64(k)*5) * seasons[(k-1)%4]
+		}},
+		{trend: `trend="multiplicative"`, forecast: func(k int) float64 {
+			return 120 * math.Pow(5, float64(k)) * seasons[(k-1)%4]
+		}},
+	}
+
+	for _, tc := range tests {
+		code, err := Convert([]byte(strings.Replace(string(b), `trend="damped_additive"`, tc.trend, 1)))
+		assert.NoError(t, err)
+
+		s := makeScript(string(code))
+		for h := 1; h <= 6; h++ {
+			r, err := runScript(s, map[string]interface{}{"horizon": h})
+			assert.NoError(t, err)
+			assert.Len(t, r.Forecasts, h)
+			assert.InDelta(t, tc.forecast(h), r.Value, 1e-9, tc.trend)
+			assert.InDelta(t, tc.forecast(h), r.Output("Forecast"), 1e-9, tc.trend)
+			for k := 1; k <= h; k++ {
+				assert.InDelta(t, tc.forecast(k), r.Forecasts[k-1], 1e-9, tc.trend)
+			}
+		}
+
+		for _, h := range []interface{}{0, nil} {
+			r, err := runScript(s, map[string]interface{}{"horizon": h})
+			assert.NoError(t, err)
+			assert.Nil(t, r)
+		}
+	}
+
+	// The forecasts are transformed back, and the additive seasonality starts at its phase
+	doc := strings.Replace(string(b), `transformation="none"`, `transformation="logarithmic"`, 1)
+	doc = strings.Replace(doc, `type="multiplicative" period="4"`, `type="additive" period="4" phase="2"`, 1)
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"horizon": 3})
+	assert.NoError(t, err)
+	assert.InEpsilon(t, math.Exp(120+(0.8+0.64+0.512)*5+0.9), r.Value, 1e-9)
+}
+
+func TestTimeSeriesModel_ARIMA(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/timeseries2.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	// The reference forecasts of the seasonal model, on the differenced series
+	y := []float64{10, 12, 15, 11, 13, 16, 18, 14, 15, 19, 21, 17}
+	w := make([]float64, 0, len(y)-1)
+	for i := 1; i < len(y); i++ {
+		w = append(w, y[i]-y[i-1])
+	}
+
+	residuals := []float64{-0.4, 0.5, -0.3, 0.1, 0.2}
+	e := make([]float64, len(w))
+	copy(e[len(w)-len(residuals):], residuals)
+
+	forecasts := make([]float64, 0, 8)
+	for k := 0; k < 8; k++ {
+		n := len(w)
+		x := 0.1 + 0.5*w[n-1] + 0.2*w[n-4] - 0.5*0.2*w[n-5] -
+			0.3*e[n-1] - 0.4*e[n-4] + 0.3*0.4*e[n-5]
+
+		w, e = append(w, x), append(e, 0)
+		if len(forecasts) == 0 {
+			forecasts = append(forecasts, y[len(y)-1]+x)
+		} else {
+			forecasts = append(forecasts, forecasts[len(forecasts)-1]+x)
+		}
+	}
+
+	s := makeScript(string(code))
+	for h := 1; h <= 8; h++ {
+		r, err := runScript(s, map[string]interface{}{"horizon": h})
+		assert.NoError(t, err)
+		assert.InDelta(t, forecasts[h-1], r.Value, 1e-9, h)
+		assert.Len(t, r.Forecasts, h)
+	}
+}
+
+func TestTimeSeriesModel_MaximumLikelihood(t *testing.T) {
+	doc := `<PMML version="4.4">
+		<TimeSeriesModel modelName="arma" functionName="timeSeries" bestFit="ARIMA">
+			<MiningSchema><MiningField name="h"/></MiningSchema>
+			<TimeSeries><TimeValue index="1" value="2"/><TimeValue index="2" value="3"/></TimeSeries>
+			<ARIMA constantTerm="1" predictionMethod="exactLeastSquares">
+				<NonseasonalComponent p="1" q="1">
+					<AR><Array type="real">0.6</Array></AR>
+					<MA><MACoefficients><Array type="real">0.3</Array></MACoefficients></MA>
+				</NonseasonalComponent>
+				%s
+			</ARIMA>
+		</TimeSeriesModel>
+	</PMML>`
+
+	// The state is projected by the transition matrix, around the mean of the series
+	code, err := Convert([]byte(fmt.Sprintf(doc, `<MaximumLikelihoodStat method="kalman"><KalmanState>
+		<FinalOmega><Matrix><Array type="real">1 0</Array><Array type="real">0 1</Array></Matrix></FinalOmega>
+		<FinalStateVector><Array type="real">0.5 0.2</Array></FinalStateVector>
+	</KalmanState></MaximumLikelihoodStat>`)))
+	assert.NoError(t, err)
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"h": 3})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{2.5 + 0.6*0.5 + 0.2, 2.5 + 0.6*0.5, 2.5 + 0.6*0.6*0.5}, r.Forecasts, 1e-9)
+
+	// The final noise of the theta recursion is used as the residuals
+	code, err = Convert([]byte(fmt.Sprintf(doc, `<MaximumLikelihoodStat method="thetaRecursionState"><ThetaRecursionState>
+		<FinalNoise><Array type="real">0.5</Array></FinalNoise>
+		<FinalPredictedNoise><Array type="real">0.4</Array></FinalPredictedNoise>
+		<FinalTheta><Theta i="1" j="1" theta="0.3"/></FinalTheta>
+		<FinalNu><Array type="real">1</Array></FinalNu>
+	</ThetaRecursionState></MaximumLikelihoodStat>`)))
+	assert.NoError(t, err)
+
+	r, err = runScript(makeScript(string(code)), map[string]interface{}{"h": 2})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{1 + 0.6*3 - 0.3*0.5, 1 + 0.6*(1+0.6*3-0.3*0.5)}, r.Forecasts, 1e-9)
+}
+
+func TestTimeSeriesModel_Error(t *testing.T) {
+	b1, err := ioutil.ReadFile("fixtures/timeseries1.xml")
+	assert.NoError(t, err)
+	b2, err := ioutil.ReadFile("fixtures/timeseries2.xml")
+	assert.NoError(t, err)
+
+	smoothing, arima := string(b1), string(b2)
+	exact := strings.Replace(arima, `"conditionalLeastSquares"`, `"exactLeastSquares"`, 1)
+	for _, tc := range []struct {
+		doc string
+		err string
+	}{
+		{doc: strings.Replace(smoothing, `<MiningField name="horizon"/>`, ``, 1), err: "model capacity requires an active field for the forecast horizon"},
+		{doc: strings.Replace(smoothing, `<MiningField name="horizon"/>`, `<MiningField name="horizon"/><MiningField name="week"/>`, 1),
+			err: "model capacity has an ambiguous forecast horizon in horizon, week"},
+		{doc: strings.Replace(smoothing, `<MiningField name="horizon"/>`, `<MiningField name="horizon" usageType="supplementary"/>`, 1),
+			err: "model capacity requires an active field for the forecast horizon"},
+		{doc: strings.Replace(smoothing, `bestFit="ExponentialSmoothing"`, `bestFit="ARIMA"`, 1), err: "best fit ARIMA of model capacity is not supported"},
+		{doc: strings.Replace(smoothing, `bestFit="ExponentialSmoothing"`, `bestFit="StateSpaceModel"`, 1), err: "best fit StateSpaceModel of model capacity is not supported"},
+		{doc: strings.Replace(smoothing, `transformation="none"`, `transformation="other"`, 1), err: "transformation other is not supported"},
+		{doc: strings.Replace(smoothing, `trend="damped_additive"`, `trend="polynomial_exponential"`, 1), err: "trend polynomial_exponential is not supported"},
+		{doc: strings.Replace(smoothing, `<Array n="4" type="real">0.9 1.1 1.2 0.8</Array>`, `<Array n="2" type="real">0.9 1.1</Array>`, 1),
+			err: "seasonality has 2 indices for a period of 4"},
+		{doc: strings.Replace(arima, `p="1" d="1"`, `p="2" d="1"`, 1), err: "nonseasonal component is invalid: 1 autoregressive coefficients instead of 2"},
+		{doc: strings.Replace(arima, `d="1"`, `d="11"`, 1), err: "time series has 12 values but at least 16 are required"},
+		{doc: exact, err: "exact least squares require the maximum likelihood state"},
+		{doc: strings.Replace(exact, `</ARIMA>`, `<MaximumLikelihoodStat method="kalman"><KalmanState>
+			<FinalStateVector><Array type="real">1 2 3</Array></FinalStateVector>
+		</KalmanState></MaximumLikelihoodStat></ARIMA>`, 1), err: "final state vector has 3 values instead of"},
+		{doc: strings.Replace(arima, `period="4"`, `period="0"`, 1), err: "seasonal component requires a positive period"},
+	} {
+		_, err := Convert([]byte(tc.doc))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,99 @@
+package pmml2lua
+
+import (
+	"github.com/kelindar/pmml2lua/schema"
+)
+
+// TransformationDictionary generates the LUA code for the element. The functions are defined
+// once for the document, while the derived fields are computed by the prologue of every
+// top-level model.
+func (s *Scope) TransformationDictionary(v schema.TransformationDictionary) *Scope {
+	if s.functions == nil {
+		s.functions = make(map[string]bool, len(v.DefineFunctions))
+	}
+
+	// The functions are declared first, so they can call each other
+	for _, f := range v.DefineFunctions {
+		s.functions[f.Name] = true
+	}
+
+	if len(v.DefineFunctions) > 0 {
+		s.With(Append("local functions = {}"))
+	}
+	for _, f := range v.DefineFunctions {
+		s.DefineFunction(f, s)
+	}
+
+	s.DerivedFields(v.DerivedFields)
+	s.derived = append(s.derived, v.DerivedFields...)
+	return s
+}
+
+// DerivedFields declares the derived fields in the scope, so their types can be used during the
+// code generation just like the types of the data fields.
+func (s *Scope) DerivedFields(fields []schema.DerivedField) *Scope {
+	if s.fields == nil {
+		s.fields = make(map[string]schema.DataField, len(fields))
+	}
+
+	for _, f := range fields {
+		s.fields[f.Name] = schema.DataField{Name: f.Name, DisplayName: f.DisplayName, Optype: f.Optype, DataType: f.DataType}
+	}
+	return s
+}
+
+// DefineFunction generates the LUA code for the element. The parameters are bound to a record,
+// so the expression of the function refers to them just like to the fields.
+func (s *Scope) DefineFunction(v schema.DefineFunction, global *Scope) *Scope {
+	params := NewStatement().Append("{")
+	for i, p := range v.ParameterFields {
+		params.String(p.Name)
+		if i+1 < len(v.ParameterFields) {
+			params.Append(", ")
+		}
+	}
+	params.Append("}")
+
+	return s.With(
+		NewStatement().Append("functions[").String(v.Name).Append("] = function(...)"),
+		NewScope().With(
+			NewStatement().Append("local v = tree.Bind(").Statement(params).Append(", ...)"),
+			NewStatement().Return().Expression(v.Expression, global),
+		),
+		Append("end"),
+	)
+}
+
+// Transformations generates the LUA code which computes the derived fields of the model, after
+// the mining schema is applied to the record. The derived fields of the transformation dictionary
+// are only computed by the top-level models, as the segments inherit them from their parent.
+func (s *Scope) Transformations(v *schema.LocalTransformations, global *Scope) *Scope {
+	var fields []schema.DerivedField
+	if global.nested == 0 {
+		fields = global.derived
+	}
+	if v != nil {
+		fields = append(fields[:len(fields):len(fields)], v.DerivedFields...)
+	}
+
+	if len(fields) == 0 {
+		return s
+	}
+
+	derived := NewScope()
+	for _, f := range fields {
+		derived.With(
+			NewStatement().Append("{name=").String(f.Name).Append(", eval=function(v)"),
+			NewScope().With(
+				NewStatement().Return().Expression(f.Expression, global),
+			),
+			Append("end},"),
+		)
+	}
+
+	return s.With(
+		Append("v = tree.Derive(v, {"),
+		derived,
+		Append("})"),
+	)
+}
@@ -0,0 +1,230 @@
+package pmml2lua
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformationDictionary(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/transform1.xml")
+	assert.NoError(t, err)
+
+	code, err := Convert(b)
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	tests := []struct {
+		input  map[string]interface{}
+		expect string
+	}{
+		{input: map[string]interface{}{"time": 50000, "amount": 30}, expect: "high"},
+		{input: map[string]interface{}{"time": 50000, "amount": 4}, expect: "medium"},
+		{input: map[string]interface{}{"time": 100, "amount": 4}, expect: "low"},
+		{input: map[string]interface{}{"time": 100}, expect: "low"},
+		{input: map[string]interface{}{"amount": 4}, expect: "low"},
+	}
+
+	for _, tc := range tests {
+		r, err := runScript(s, tc.input)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expect, r.Value, tc.input)
+		assert.NotContains(t, tc.input, "period")
+	}
+}
+
+func TestTransformationDictionary_Segments(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/transform1.xml")
+	assert.NoError(t, err)
+
+	// The tree is nested in a segment, whose parent computes the derived fields of the dictionary
+	for _, method := range []string{"selectFirst", "modelChain"} {
+		doc := strings.Replace(string(b), `<TreeModel modelName="risk" functionName="classification">`, `<MiningModel modelName="ensemble" functionName="classification">
+  <MiningSchema>
+    <MiningField name="time"/>
+    <MiningField name="amount"/>
+    <MiningField name="risk" usageType="target"/>
+  </MiningSchema>
+  <Segmentation multipleModelMethod="`+method+`">
+  <Segment id="1">
+  <SimplePredicate field="period" operator="equal" value="PM"/>
+  <TreeModel modelName="risk" functionName="classification">`, 1)
+		doc = strings.Replace(doc, `</TreeModel>`, `</TreeModel>
+  </Segment>
+  </Segmentation>
+  </MiningModel>`, 1)
+
+		code, err := Convert([]byte(doc))
+		assert.NoError(t, err)
+
+		// The derived fields of the dictionary are only computed by the top-level model
+		assert.Equal(t, 1, strings.Count(string(code), `{name='period'`), method)
+		assert.Equal(t, 1, strings.Count(string(code), `{name='large'`), method)
+
+		s := makeScript(string(code))
+		r, err := runScript(s, map[string]interface{}{"time": 50000, "amount": 30})
+		assert.NoError(t, err)
+		assert.Equal(t, "high", r.Value, method)
+
+		r, err = runScript(s, map[string]interface{}{"time": 100, "amount": 30})
+		assert.NoError(t, err)
+		assert.Nil(t, r, method)
+	}
+}
+
+func TestLocalTransformations_Segments(t *testing.T) {
+	doc := `<PMML xmlns="http://www.dmg.org/PMML-4_4" version="4.4">
+<DataDictionary>
+  <DataField name="x" optype="continuous" dataType="double"/>
+  <DataField name="z" optype="categorical" dataType="string"/>
+  <DataField name="y" optype="categorical" dataType="string"/>
+</DataDictionary>
+<MiningModel modelName="ensemble" functionName="classification">
+  <MiningSchema>
+    <MiningField name="x"/>
+    <MiningField name="z"/>
+    <MiningField name="y" usageType="target"/>
+  </MiningSchema>
+  <Segmentation multipleModelMethod="selectAll">
+  <Segment id="1">
+    <True/>
+    <TreeModel functionName="classification">
+      <MiningSchema>
+        <MiningField name="x"/>
+        <MiningField name="y" usageType="target"/>
+      </MiningSchema>
+      <LocalTransformations>
+        <DerivedField name="z" optype="continuous" dataType="double">
+          <Apply function="*"><FieldRef field="x"/><Constant>2</Constant></Apply>
+        </DerivedField>
+      </LocalTransformations>
+      <Node score="none">
+        <True/>
+        <Node score="double"><SimplePredicate field="z" operator="greaterThan" value="10"/></Node>
+      </Node>
+    </TreeModel>
+  </Segment>
+  <Segment id="2">
+    <True/>
+    <TreeModel functionName="classification">
+      <MiningSchema>
+        <MiningField name="z"/>
+        <MiningField name="y" usageType="target"/>
+      </MiningSchema>
+      <Node score="none">
+        <True/>
+        <Node score="string"><SimplePredicate field="z" operator="equal" value="a"/></Node>
+      </Node>
+    </TreeModel>
+  </Segment>
+  </Segmentation>
+</MiningModel>
+</PMML>`
+
+	// The second segment compares the data field, not the derived field of the first segment
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "v.z > 10")
+	assert.Contains(t, string(code), "v.z == 'a'")
+
+	r, err := runScript(makeScript(string(code)), map[string]interface{}{"x": 6, "z": "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"double", "string"}, r.Value)
+}
+
+func TestTransformationDictionary_MiningSchema(t *testing.T) {
+	doc := `<PMML xmlns="http://www.dmg.org/PMML-4_4" version="4.4">
+<DataDictionary>
+  <DataField name="x" optype="continuous" dataType="double"/>
+  <DataField name="y" optype="continuous" dataType="double"/>
+</DataDictionary>
+<TransformationDictionary>
+  <DerivedField name="twice" optype="continuous" dataType="double">
+    <Apply function="*"><FieldRef field="x"/><Constant>2</Constant></Apply>
+  </DerivedField>
+</TransformationDictionary>
+<RegressionModel modelName="twice" functionName="regression">
+  <MiningSchema>
+    <MiningField name="x" missingValueReplacement="5" outliers="asExtremeValues" lowValue="0" highValue="100"/>
+    <MiningField name="y" usageType="target"/>
+  </MiningSchema>
+  <RegressionTable intercept="0">
+    <NumericPredictor name="twice" coefficient="1"/>
+  </RegressionTable>
+</RegressionModel>
+</PMML>`
+
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	// The derived fields read the fields once their missing and outlier values are treated
+	s := makeScript(string(code))
+	tests := []struct {
+		input  map[string]interface{}
+		expect float64
+	}{
+		{input: map[string]interface{}{"x": 3}, expect: 6},
+		{input: map[string]interface{}{}, expect: 10},
+		{input: map[string]interface{}{"x": 500}, expect: 200},
+		{input: map[string]interface{}{"x": -1}, expect: 0},
+	}
+
+	for _, tc := range tests {
+		r, err := runScript(s, tc.input)
+		assert.NoError(t, err)
+		assert.InDelta(t, tc.expect, r.Value, 1e-9, tc.input)
+	}
+}
+
+func TestTransformationDictionary_Functions(t *testing.T) {
+	doc := `<PMML xmlns="http://www.dmg.org/PMML-4_4" version="4.4">
+<DataDictionary>
+  <DataField name="x" optype="continuous" dataType="double"/>
+  <DataField name="y" optype="continuous" dataType="double"/>
+</DataDictionary>
+<TransformationDictionary>
+  <DefineFunction name="square" optype="continuous" dataType="double">
+    <ParameterField name="a"/>
+    <Apply function="*"><FieldRef field="a"/><FieldRef field="a"/></Apply>
+  </DefineFunction>
+  <DefineFunction name="norm" optype="continuous" dataType="double">
+    <ParameterField name="a"/>
+    <ParameterField name="b"/>
+    <Apply function="sqrt">
+      <Apply function="+">
+        <Apply function="square"><FieldRef field="a"/></Apply>
+        <Apply function="square"><FieldRef field="b"/></Apply>
+      </Apply>
+    </Apply>
+  </DefineFunction>
+</TransformationDictionary>
+<RegressionModel modelName="length" functionName="regression">
+  <MiningSchema>
+    <MiningField name="x"/>
+    <MiningField name="y" usageType="target"/>
+  </MiningSchema>
+  <LocalTransformations>
+    <DerivedField name="z" optype="continuous" dataType="double">
+      <Apply function="norm" mapMissingTo="-1"><FieldRef field="x"/><Constant>4</Constant></Apply>
+    </DerivedField>
+  </LocalTransformations>
+  <RegressionTable intercept="0">
+    <NumericPredictor name="z" coefficient="1"/>
+  </RegressionTable>
+</RegressionModel>
+</PMML>`
+
+	code, err := Convert([]byte(doc))
+	assert.NoError(t, err)
+
+	s := makeScript(string(code))
+	r, err := runScript(s, map[string]interface{}{"x": 3})
+	assert.NoError(t, err)
+	assert.InDelta(t, 5.0, r.Value, 1e-9)
+
+	r, err = runScript(s, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.InDelta(t, -1.0, r.Value, 1e-9)
+}
@@ -7,68 +7,114 @@ import (
 // DecisionTree generates the LUA code for the element.
 func (s *Scope) DecisionTree(v schema.DecisionTree, global *Scope) *Scope {
 	return s.Function(v.ModelName, "v").
+		MiningSchema(v.MiningSchema, global).
+		Transformations(v.LocalTransformations, global).
 		With(
 			Append("model = model or {}"),
-			Append("model.%s = model.%s or ", v.ModelName, v.ModelName),
+			Append("model.%s = model.%s or tree.NewTree(", v.ModelName, v.ModelName).
+				TreeOptions(v).Append("}, {"),
 			NewScope().Node(v.Node, v, global),
-			Append("return tree.NewTree('%s', model.%s).next(v)", v.MissingValueStrategy, v.ModelName),
-		)
+			Append("})"),
+		).
+		Output(v.Output, v.MiningSchema.Target(), "model."+v.ModelName+".eval(v)", global)
 }
 
-// Node generates the LUA code for the element.
-func (s *Scope) Node(v schema.Node, tree schema.DecisionTree, global *Scope) *Scope {
-	hasChildren := len(v.Nodes) > 0
-	hasMissingStrategy := (tree.MissingValueStrategy != "none" && tree.MissingValueStrategy != "")
-	masMissingEstimate := tree.MissingValueStrategy == "weightedConfidence" ||
-		tree.MissingValueStrategy == "aggregateNodes" ||
-		tree.MissingValueStrategy == "defaultChild"
+// TreeOptions generates the LUA code for the strategies of the tree, without the closing brace.
+func (s *Statement) TreeOptions(v schema.DecisionTree) *Statement {
+	s.Append("{missing=")
+	if v.MissingValueStrategy != "" {
+		s.String(v.MissingValueStrategy)
+	} else {
+		s.String("none")
+	}
 
-	// Generate the run content
-	inner := NewScope().With(
-		Append("t.last = '%v'", v.Score),
-		AppendIf(!hasChildren, "return true"), // Stop
-	)
+	// The penalty applied to the confidences for each missing value decision
+	if v.MissingValuePenalty != 0 && v.MissingValuePenalty != 1 {
+		s.Append(", penalty=%v", v.MissingValuePenalty)
+	}
 
-	// Missing values aggregation
-	missing := NewScope()
-	for _, d := range v.Distributions {
-		missing.WithIf(masMissingEstimate,
-			Append(`t.miss('%s', %d, %v)`, d.Value, d.RecordCount, d.Confidence),
-		)
+	// What to do when none of the child predicates of a node is true
+	if v.NoTrueChildStrategy != "" {
+		s.Append(", noTrueChild=").String(v.NoTrueChildStrategy)
 	}
 
-	missing.WithIf(tree.MissingValueStrategy == "defaultChild" && v.DefaultChild != "",
-		Append(`return n.children["%s"].eval(t, n, v)`, v.DefaultChild),
-	)
+	// The numeric predictions of regression trees are averaged by the missing value strategies
+	if v.FunctionName == "regression" {
+		s.Append(", regression=true")
+	}
+	return s
+}
 
-	missing.WithIf(tree.MissingValueStrategy == "nullPrediction",
-		Append("t.last = nil"), // Set last to UNKNOWN
-		Append("return true"),  // Stop
-	)
+// Node generates the LUA code for the element.
+func (s *Scope) Node(v schema.Node, tree schema.DecisionTree, global *Scope) *Scope {
+	if v.Predicate == nil {
+		return s.With(NewStatement().Error("node %s has no predicate", v.ID))
+	}
 
-	missing.WithIf(tree.MissingValueStrategy == "lastPrediction",
-		Append("return true"), // Stop
-	)
+	// Generate the metadata of the node
+	node := NewStatement().Append("tree.NewNode({")
+	if v.ID != "" {
+		node.Append("id=").String(v.ID).Append(", ")
+	}
+	if v.Score != "" {
+		node.Append("score=").Literal(schema.Value(v.Score), scoreType(tree.FunctionName, tree.MiningSchema.Target(), global)).Append(", ")
+	}
+	if v.DefaultChild != "" {
+		node.Append("default=").String(v.DefaultChild).Append(", ")
+	}
+	node.Append("count=%d", v.RecordCount)
+	if len(v.Distributions) > 0 {
+		node.Append(", dist={")
+		for i, d := range v.Distributions {
+			node.ScoreDistribution(d)
+			if i+1 < len(v.Distributions) {
+				node.Append(", ")
+			}
+		}
+		node.Append("}")
+	}
 
 	// Generate children nodes
+	hasChildren := len(v.Nodes) > 0
 	children := NewScope()
 	for _, child := range v.Nodes {
 		children.Node(child, tree, global)
 	}
 
 	return s.With(
-		Append("tree.NewNode('%v', function(t, n, v) ", v.ID),
+		node.Append("}, function(v)"),
 		NewScope().With(
-			Append("local x = (").Predicate(v.Predicate, global).Append("); if x then"),
-			inner,
-			AppendIf(hasMissingStrategy, "elseif x == nil then"),
-			missing,
-			Append("end"),
-			Append("return false"), // Continue
+			NewStatement().Return().Predicate(v.Predicate, global),
 		),
-		AppendIf(!hasChildren, "end)"),
-		AppendIf(hasChildren, "end,"),
+		AppendIf(!hasChildren, "end),"),
+		AppendIf(hasChildren, "end, {"),
 		children,
-		AppendIf(hasChildren, ")"),
+		AppendIf(hasChildren, "}),"),
 	)
 }
+
+// ScoreDistribution generates the LUA code for the element.
+func (s *Statement) ScoreDistribution(v schema.ScoreDistribution) *Statement {
+	s.Append("{value=").String(v.Value).Append(", count=%d", v.RecordCount)
+	if v.Confidence != 0 {
+		s.Append(", confidence=%v", v.Confidence)
+	}
+	if v.Probability != 0 {
+		s.Append(", probability=%v", v.Probability)
+	}
+	return s.Append("}")
+}
+
+// scoreType returns the data type of the scores of a model. The scores of classification models
+// are class labels and are always written as strings, while the scores of regression models are
+// written with the data type of the target field.
+func scoreType(functionName, target string, global *Scope) string {
+	if functionName != "regression" {
+		return "string"
+	}
+
+	if field, ok := global.DataField(target); ok && field.DataType != "" {
+		return field.DataType
+	}
+	return "double"
+}
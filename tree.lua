@@ -103,6 +103,10 @@ end
 
 -- Checks if the value is present in the set
 function tree.IsIn(target, array)
+    if Unknown(target) then
+        return nil
+    end
+
     for i, v in ipairs(array) do
         if v == target then
             return true
@@ -114,71 +118,201 @@ end
 
 -- Checks if the value is missing in the set
 function tree.IsNotIn(target, array)
-	return not tree.IsIn(target, array)
+    if Unknown(target) then
+        return nil
+    end
+    return not tree.IsIn(target, array)
+end
+
+-- NewNode creates a node of the tree with its predicate and its child nodes. The node table
+-- contains the id, the score, the record count and the score distribution of the node.
+function tree.NewNode(node, test, children)
+    node.test = test
+    node.dist = node.dist or {}
+    node.children = children or {}
+    return node
 end
 
--- NewNode creates a node structure
-function tree.NewNode(id, run, ...)
-    n = {}
-    n.id = id
-    n.run = run
-    n.children = {}
+-- NewTree creates a new decision tree with its strategies and its root nodes. The root nodes are
+-- kept under a virtual node, which has no score and thus gives no prediction.
+function tree.NewTree(options, nodes)
+    local t = options
+    t.missing = t.missing or 'none'
+    t.noTrueChild = t.noTrueChild or 'returnNullPrediction'
+    t.penalty = t.penalty or 1.0
+    t.root = tree.NewNode({virtual = true}, nil, nodes)
+
+    -- Function which traverses the tree and returns the prediction
+    t.predict = function(v)
+        return tree.Walk(t, t.root, v)
+    end
+
+    -- Function which traverses the tree and returns the result table
+    t.eval = function(v)
+        return tree.Result(t.predict(v))
+    end
+    return t
+end
 
-    -- Create a table with all of the children
-    for k, v in pairs({ ... }) do
-        n.children[v.id] = v
+-- Result converts the prediction into the result table returned by the model, with the predicted
+-- value, the id of the winning node and the probability, confidence and record count per class.
+function tree.Result(p)
+    if p == nil then
+        return nil
     end
 
-    -- The evaluation function
-    n.eval = function(t, v)
-        local done = n.run(t, n, v); if done then
-            return
+    local r = {value = p.score, entityId = p.id}
+    if #p.dist > 0 then
+        r.probabilities, r.confidences, r.recordCounts = {}, {}, {}
+        for i=1, #p.dist do
+            local d = p.dist[i]
+            r.probabilities[d.value] = d.probability
+            r.confidences[d.value] = d.confidence
+            r.recordCounts[d.value] = d.count
         end
-        
-        -- Evaluate siblings
-        for k, n in pairs(nodes) do
-            local done = n.eval(t, v); if done then
-                return
+    end
+    return r
+end
+
+-- Walk traverses the tree from a node whose predicate is true and returns the prediction, or
+-- nil if there is no prediction.
+function tree.Walk(t, n, v)
+    if #n.children == 0 then
+        return tree.Predict(n)
+    end
+
+    for i=1, #n.children do
+        local x = n.children[i].test(v)
+        if x == true then
+            return tree.Walk(t, n.children[i], v)
+        elseif x == nil then
+            local p, done = tree.strategies[t.missing](t, n, v)
+            if done then
+                return p
             end
-        end 
+        end
     end
-    
-    return n
+
+    -- None of the child predicates is true
+    if t.noTrueChild == 'returnLastPrediction' then
+        return tree.Predict(n)
+    end
+    return nil
 end
 
--- NewTree creates a new decision tree
-function tree.NewTree(strategy, node)
-    t = {}
-    t.onMiss = strategy
-    t.node = node
-    t.conf = {}
-    t.last = nil
+-- Predict creates a prediction out of the node, with the confidence and the probability of each
+-- class derived from the record counts when they are not specified. The virtual node above the
+-- root nodes gives no prediction, since the record did not reach any node of the model.
+function tree.Predict(n)
+    if n.virtual then
+        return nil
+    end
 
-    -- Function which traverses the tree
-    t.eval = function(v)
-        t.node.eval(t, v)
-        return t.last
+    local total = 0
+    for i=1, #n.dist do
+        total = total + n.dist[i].count
     end
 
-    -- Function that records an unknown value
-    t.miss = function(score, count, confidence)
-        t.conf[score] = t.conf[score] or {}
-        table.insert(t.conf[score], {count, confidence})
+    local p = {score = n.score, id = n.id, count = n.count, dist = {}}
+    for i=1, #n.dist do
+        local d = n.dist[i]
+        local ratio = 0
+        if total > 0 then
+            ratio = d.count / total
+        end
+
+        p.dist[i] = {
+            value = d.value,
+            count = d.count,
+            confidence = d.confidence or ratio,
+            probability = d.probability or ratio,
+        }
+    end
+    return p
+end
+
+-- Merge adds the class counts and the weighted confidences and probabilities of a prediction to
+-- the distribution.
+function tree.Merge(dist, p, weight)
+    for i=1, #p.dist do
+        local d, found = p.dist[i], nil
+        for j=1, #dist do
+            if dist[j].value == d.value then
+                found = dist[j]
+            end
+        end
+
+        if found == nil then
+            found = {value = d.value, count = 0, confidence = 0, probability = 0}
+            table.insert(dist, found)
+        end
+
+        found.count = found.count + d.count
+        found.confidence = found.confidence + weight * d.confidence
+        found.probability = found.probability + weight * d.probability
     end
-    return t
+end
+
+-- Penalize multiplies the confidences of the prediction by the missing value penalty of the
+-- tree, once for each of the missing value decisions which were taken.
+function tree.Penalize(t, p, decisions)
+    if p == nil or decisions == 0 then
+        return p
+    end
+
+    local factor = t.penalty ^ decisions
+    for i=1, #p.dist do
+        p.dist[i].confidence = p.dist[i].confidence * factor
+    end
+    return p
+end
+
+-- Winner returns the class of the distribution with the highest value of the key. In case of a
+-- tie, the class which appears first in the distribution wins.
+function tree.Winner(dist, key)
+    local winner = nil
+    for i=1, #dist do
+        if winner == nil or dist[i][key] > winner[key] then
+            winner = dist[i]
+        end
+    end
+
+    if winner == nil then
+        return nil
+    end
+    return winner.value
+end
+
+-- Average returns the mean of the numeric scores weighted by their record counts, given as pairs
+-- of score and record count. If none of the scores has any record, the plain mean is returned.
+function tree.Average(scores)
+    local sum, weights, plain, n = 0, 0, 0, 0
+    for i=1, #scores do
+        local score, count = scores[i][1], scores[i][2]
+        if type(score) == 'number' then
+            sum, weights = sum + score * count, weights + count
+            plain, n = plain + score, n + 1
+        end
+    end
+
+    if weights > 0 then
+        return sum / weights
+    elseif n > 0 then
+        return plain / n
+    end
+    return nil
 end
 
 -- If a Node's predicate evaluates to UNKNOWN while traversing the tree, evaluation is stopped
 -- and the current winner is returned as the final prediction.
 function tree.LastPrediction(t, n, v)
-    return true -- stop
+    return tree.Predict(n), true -- stop
 end
 
 -- If a Node's predicate value evaluates to UNKNOWN while traversing the tree, abort the scoring
 -- process and give no prediction.
 function tree.NullPrediction(t, n, v)
-    t.last = nil
-    return true -- stop
+    return nil, true -- stop
 end
 
 -- Comparisons with missing values other than checks for missing values always evaluate to FALSE. 
@@ -188,15 +322,21 @@ end
 -- upon first discovery of a Node who's predicate value cannot be determined due to missing 
 -- values.
 function tree.None(t, n, v)
-    return false -- continue
+    return nil, false -- continue
 end
 
 -- If a Node's predicate value evaluates to UNKNOWN while traversing the tree, evaluate the 
 -- attribute defaultChild which gives the child to continue traversing with. Requires the 
 -- presence of the attribute defaultChild in every non-leaf Node.
 function tree.DefaultChild(t, n, v)
-    --return t.next(v, {n.children[n.def]})
-    return true
+    for i=1, #n.children do
+        if n.children[i].id == n.default then
+            return tree.Penalize(t, tree.Walk(t, n.children[i], v), 1), true
+        end
+    end
+
+    -- Without a default child, the current winner is returned
+    return tree.Predict(n), true
 end
 
 -- If a Node's predicate value evaluates to UNKNOWN while traversing the tree, the confidences for
@@ -207,7 +347,32 @@ end
 -- confidence. Note that weightedConfidence should be applied recursively to deal with situations 
 -- where several predicates within the tree evaluate to UNKNOWN during the scoring of a case.
 function tree.WeightedConfidence(t, n, v)
-    return true
+    local total = n.count or 0
+    if total == 0 then
+        for i=1, #n.children do
+            total = total + (n.children[i].count or 0)
+        end
+    end
+
+    local p, scores = {count = n.count, dist = {}}, {}
+    for i=1, #n.children do
+        local child = n.children[i]
+        if total > 0 and child.test(v) ~= false then
+            local scored = tree.Walk(t, child, v)
+            if scored ~= nil then
+                tree.Merge(p.dist, scored, (child.count or 0) / total)
+                table.insert(scores, {scored.score, child.count or 0})
+            end
+        end
+    end
+
+    -- The numeric predictions of regression trees are averaged instead
+    if t.regression then
+        p.score = tree.Average(scores)
+    else
+        p.score = tree.Winner(p.dist, 'confidence')
+    end
+    return tree.Penalize(t, p, 1), true -- stop
 end
     
 -- If a Node's predicate value evaluates to UNKNOWN while traversing the tree, we consider evaluation
@@ -222,11 +387,264 @@ end
 -- score according to this virtual Node. Requires the presence of attribute recordCount in all 
 -- ScoreDistribution elements.
 function tree.AggregateNodes(t, n, v)
-    
+    local leaves = {}
+    local decisions = tree.Reach(t, n, v, leaves)
+
+    local p, scores = {count = 0, dist = {}}, {}
+    for i=1, #leaves do
+        tree.Merge(p.dist, tree.Predict(leaves[i]), 0)
+        table.insert(scores, {leaves[i].score, leaves[i].count or 0})
+    end
+
+    -- The confidences are the proportions of the accumulated record counts
+    for i=1, #p.dist do
+        p.count = p.count + p.dist[i].count
+    end
+    for i=1, #p.dist do
+        if p.count > 0 then
+            p.dist[i].confidence = p.dist[i].count / p.count
+            p.dist[i].probability = p.dist[i].confidence
+        end
+    end
+
+    if t.regression then
+        p.score = tree.Average(scores)
+    else
+        p.score = tree.Winner(p.dist, 'count')
+    end
+    return tree.Penalize(t, p, decisions), true -- stop
 end
-    
 
+-- Reach collects the leaf nodes which may be reached from the node, following every child whose
+-- predicate is UNKNOWN and stopping at the first child whose predicate is TRUE. It returns the
+-- number of UNKNOWN predicates which were followed.
+function tree.Reach(t, n, v, leaves)
+    if #n.children == 0 then
+        table.insert(leaves, n)
+        return 0
+    end
+
+    local decisions = 0
+    for i=1, #n.children do
+        local x = n.children[i].test(v)
+        if x == true then
+            return decisions + tree.Reach(t, n.children[i], v, leaves)
+        elseif x == nil then
+            decisions = decisions + 1 + tree.Reach(t, n.children[i], v, leaves)
+        end
+    end
+
+    -- The record may not reach any of the children
+    if t.noTrueChild == 'returnLastPrediction' then
+        table.insert(leaves, n)
+    end
+    return decisions
+end
+
+-- The missing value strategies, by their name in the TreeModel
+tree.strategies = {
+    lastPrediction = tree.LastPrediction,
+    nullPrediction = tree.NullPrediction,
+    defaultChild = tree.DefaultChild,
+    weightedConfidence = tree.WeightedConfidence,
+    aggregateNodes = tree.AggregateNodes,
+    none = tree.None,
+}
+
+-- Prepare applies the mining schema to the input record and returns a new record which only
+-- contains the mining fields, with their missing, invalid and outlier values treated.
+function tree.Prepare(v, fields)
+    local out = {}
+    for i=1, #fields do
+        local f, x = fields[i], v[fields[i].name]
+        if type(x) == 'table' or type(x) == 'userdata' then
+            out[f.name] = tree.TreatAll(f, x)
+        else
+            out[f.name] = tree.Treat(f, x)
+        end
+    end
+
+    -- The derived fields of an enclosing model are kept, so the segments can refer to them
+    local derived = tree.DerivedOf(v)
+    if derived ~= nil then
+        for i=1, #derived do
+            if out[derived[i]] == nil then
+                out[derived[i]] = v[derived[i]]
+            end
+        end
+        setmetatable(out, {derived = derived})
+    end
+    return out
+end
+
+-- Derive computes the derived fields of the record in their order, so each of them can refer to
+-- the fields of the record and to the fields derived before it. The record is not modified, and
+-- the derived fields of an enclosing model are kept.
+function tree.Derive(v, fields)
+    local derived = {}
+    for _, name in ipairs(tree.DerivedOf(v) or {}) do
+        table.insert(derived, name)
+    end
+
+    local out = setmetatable({}, {
+        __index = function(_, k) return v[k] end,
+        derived = derived,
+    })
+
+    for i=1, #fields do
+        local f = fields[i]
+        out[f.name] = f.eval(out)
+        table.insert(derived, f.name)
+    end
+    return out
+end
+
+-- DerivedOf returns the names of the derived fields of the record, if any.
+function tree.DerivedOf(v)
+    if type(v) ~= 'table' then
+        return nil
+    end
+
+    local mt = getmetatable(v)
+    if type(mt) == 'table' then
+        return mt.derived
+    end
+    return nil
+end
+
+-- Bind returns the record of the arguments of a user-defined function, by the names of its
+-- parameters.
+function tree.Bind(names, ...)
+    local v, args = {}, {...}
+    for i=1, #names do
+        v[names[i]] = args[i]
+    end
+    return v
+end
+
+-- TreatAll applies the treatments of a mining field to each value of a collection, such as a
+-- series of values, and drops the values which are missing after the treatments.
+function tree.TreatAll(f, values)
+    local out = {}
+    for i=1, #values do
+        local x = tree.Treat(f, values[i])
+        if x ~= nil then
+            table.insert(out, x)
+        end
+    end
+    return out
+end
+
+-- Treat applies the missing, invalid and outlier value treatments of a mining field.
+function tree.Treat(f, raw)
+    local x, status = tree.Validate(f, raw)
+
+    -- The invalid values are either returned as is, replaced or treated as missing values
+    if status == 'invalid' then
+        if f.invalid == 'asIs' then
+            return raw
+        elseif f.invalid == 'asValue' then
+            return f.invalidValue
+        elseif f.invalid == 'asMissing' then
+            status = 'missing'
+        else
+            error("field '" .. f.name .. "' has an invalid value '" .. tostring(raw) .. "'")
+        end
+    end
+
+    -- The outliers are either returned as is, treated as missing values or clamped
+    if status == 'valid' and type(x) == 'number' then
+        local low, high = f.low or -1/0, f.high or 1/0
+        if x < low or x > high then
+            if f.outliers == 'asMissingValues' then
+                status = 'missing'
+            elseif f.outliers == 'asExtremeValues' then
+                x = math.max(low, math.min(high, x))
+            end
+        end
+    end
+
+    -- The missing values are replaced if a replacement value is specified
+    if status == 'missing' then
+        if f.replacement ~= nil then
+            return f.replacement
+        elseif f.treatment == 'returnInvalid' then
+            error("field '" .. f.name .. "' has a missing value")
+        end
+        return nil
+    end
+    return x
+end
+
+-- Validate converts the value to the data type of the field and checks whether it is a valid,
+-- an invalid or a missing value with respect to the values and intervals of the field.
+function tree.Validate(f, raw)
+    if Unknown(raw) or (f.values and f.values[raw] == 'missing') then
+        return nil, 'missing'
+    end
+
+    local x = tree.Cast(raw, f.dataType)
+    if x == nil then
+        return nil, 'invalid'
+    end
 
+    -- Explicitly listed values take precedence over the intervals
+    local property = f.values and f.values[x]
+    if property ~= nil then
+        return x, property
+    end
+
+    if f.intervals and type(x) == 'number' then
+        for i=1, #f.intervals do
+            if tree.InInterval(x, f.intervals[i]) then
+                return x, 'valid'
+            end
+        end
+        return x, 'invalid'
+    end
+
+    if f.closed then
+        return x, 'invalid'
+    end
+    return x, 'valid'
+end
+
+-- Cast converts the value to the data type, or returns nil if the conversion is not possible.
+function tree.Cast(x, dataType)
+    if dataType == 'integer' then
+        local n = tonumber(x)
+        if n ~= nil and n == math.floor(n) then
+            return n
+        end
+        return nil
+    elseif dataType == 'float' or dataType == 'double' then
+        return tonumber(x)
+    elseif dataType == 'boolean' then
+        if x == true or x == 'true' or x == 1 or x == '1' then
+            return true
+        elseif x == false or x == 'false' or x == 0 or x == '0' then
+            return false
+        end
+        return nil
+    elseif dataType == 'string' then
+        return tostring(x)
+    end
+    return x
+end
+
+-- InInterval checks whether the number is within the interval, which is a table of the closure
+-- and the left and right margins.
+function tree.InInterval(x, interval)
+    local closure, left, right = interval[1], interval[2], interval[3]
+    if closure == 'closedClosed' then
+        return left <= x and x <= right
+    elseif closure == 'closedOpen' then
+        return left <= x and x < right
+    elseif closure == 'openClosed' then
+        return left < x and x <= right
+    end
+    return left < x and x < right
+end
 
 -- Checks if the value is missing
 function Unknown(v)
@@ -1,22 +1,156 @@
 package pmml2lua
 
 import (
+	"context"
 	"testing"
 
+	"github.com/kelindar/lua"
 	"github.com/kelindar/pmml2lua/schema"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDecisionTree1(t *testing.T) {
-
-	var out schema.DecisionTree
+	var out schema.PMML
 	body, global, code := scopeFor("fixtures/tree1.xml", &out)
-	global.DecisionTree(out, global)
+	global.DecisionTree(*out.Models[0].TreeModel, global)
 	body.With(
-		NewStatement().Return().Call(out.ModelName, "v"),
+		NewStatement().Return().Call(out.Models[0].Name(), "v"),
 	)
 
-	assert.Contains(t, code(), `xxxx`)
+	assert.Contains(t, code(), "function golfing(v)")
+	assert.Contains(t, code(), "model.golfing = model.golfing or tree.NewTree({missing='weightedConfidence'}, {")
+	assert.Contains(t, code(), "return golfing(v)")
+}
+
+func TestMissingValueStrategy(t *testing.T) {
+	td := []struct {
+		input  map[string]interface{} // The input data
+		expect map[string]interface{} // The expected score for each strategy
+	}{
+		{
+			input: map[string]interface{}{"outlook": "sunny", "temperature": 60},
+			expect: map[string]interface{}{
+				"lastPrediction":     "will play",
+				"nullPrediction":     "will play",
+				"defaultChild":       "will play",
+				"weightedConfidence": "will play",
+				"aggregateNodes":     "will play",
+				"none":               "will play",
+			},
+		},
+		{
+			input: map[string]interface{}{"outlook": "sunny"},
+			expect: map[string]interface{}{
+				"lastPrediction":     "will play",
+				"nullPrediction":     nil,
+				"defaultChild":       "will play",
+				"weightedConfidence": "will play",
+				"aggregateNodes":     "will play",
+				"none":               nil,
+			},
+		},
+		{
+			input: map[string]interface{}{"temperature": 45, "humidity": 85},
+			expect: map[string]interface{}{
+				"lastPrediction":     "will play",
+				"nullPrediction":     nil,
+				"defaultChild":       "no play",
+				"weightedConfidence": "will play",
+				"aggregateNodes":     "may play",
+				"none":               nil,
+			},
+		},
+		{
+			input: map[string]interface{}{"outlook": "rain", "humidity": 85},
+			expect: map[string]interface{}{
+				"lastPrediction":     "may play",
+				"nullPrediction":     "may play",
+				"defaultChild":       "may play",
+				"weightedConfidence": "may play",
+				"aggregateNodes":     "may play",
+				"none":               "may play",
+			},
+		},
+	}
+
+	for strategy := range td[0].expect {
+		t.Run(strategy, func(t *testing.T) {
+			var out schema.PMML
+			body, global, code := scopeFor("fixtures/tree1.xml", &out)
+			model := *out.Models[0].TreeModel
+			model.MissingValueStrategy = strategy
+
+			global.DataDictionary(*out.DataDictionary)
+			global.DecisionTree(model, global)
+			body.With(
+				NewStatement().Return().Call("json.encode", model.ModelName+"(v)"),
+			)
+
+			s := makeScript(code())
+			for _, tt := range td {
+				r, err := runScript(s, tt.input)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect[strategy], valueOf(r), tt.input)
+			}
+		})
+	}
+}
+
+func TestRegressionTree(t *testing.T) {
+	td := []struct {
+		input  map[string]interface{} // The input data
+		expect map[string]interface{} // The expected score for each strategy
+	}{
+		{
+			input: map[string]interface{}{"x": 5, "y": 2},
+			expect: map[string]interface{}{
+				"weightedConfidence": 10.0,
+				"aggregateNodes":     10.0,
+				"lastPrediction":     10.0,
+			},
+		},
+		{
+			input: map[string]interface{}{"y": 2},
+			expect: map[string]interface{}{
+				"weightedConfidence": 18.0,
+				"aggregateNodes":     1400 / 60.0,
+				"lastPrediction":     20.0,
+			},
+		},
+		{
+			input: map[string]interface{}{},
+			expect: map[string]interface{}{
+				"weightedConfidence": 19.5,
+				"aggregateNodes":     19.5,
+				"lastPrediction":     20.0,
+			},
+		},
+	}
+
+	for strategy := range td[0].expect {
+		t.Run(strategy, func(t *testing.T) {
+			var out schema.PMML
+			body, global, code := scopeFor("fixtures/tree3.xml", &out)
+			model := *out.Models[0].TreeModel
+			model.MissingValueStrategy = strategy
+
+			global.DataDictionary(*out.DataDictionary)
+			global.DecisionTree(model, global)
+			body.With(
+				NewStatement().Return().Call("json.encode", model.ModelName+"(v)"),
+			)
+
+			assert.Contains(t, code(), "regression=true")
+			assert.Contains(t, code(), "score=13.75")
+
+			s := makeScript(code())
+			for _, tt := range td {
+				r, err := runScript(s, tt.input)
+				assert.NoError(t, err)
+				assert.InDelta(t, tt.expect[strategy], valueOf(r), 1e-9, tt.input)
+			}
+		})
+	}
 }
 
 func TestNode(t *testing.T) {
@@ -38,5 +172,178 @@ func TestNode(t *testing.T) {
 	body, global, code := scopeFor(input, &out)
 	body.Node(out, schema.DecisionTree{}, global)
 
-	assert.Contains(t, code(), `local x = (v.outlook and v.outlook == 'sunny'); if x then`)
+	assert.Contains(t, code(), `tree.NewNode({id='1', score='will play', default='2', count=100, dist={{value='will play', count=60, confidence=0.6}, {value='may play', count=30, confidence=0.3}, {value='no play', count=10, confidence=0.1}}}, function(v)`)
+	assert.Contains(t, code(), `return (v.outlook ~= nil or nil) and v.outlook == 'sunny'`)
+	assert.Contains(t, code(), "end, {")
+	assert.Contains(t, code(), "end),\n\t})")
+}
+
+func TestNode_NoPredicate(t *testing.T) {
+	_, err := NewScope().Node(schema.Node{ID: "1"}, schema.DecisionTree{}, NewScope()).Compile()
+	assert.Error(t, err)
+}
+
+func TestNoTrueChildStrategy(t *testing.T) {
+	input := `<TreeModel modelName="edge" functionName="classification" missingValueStrategy="none">
+		<Node id="root" score="root">
+			<True/>
+			<Node id="a" score="a">
+				<SimplePredicate field="x" operator="greaterThan" value="10"/>
+				<Node id="a1" score="a1">
+					<SimplePredicate field="y" operator="greaterThan" value="0"/>
+				</Node>
+				<Node id="a2" score="a2">
+					<SimplePredicate field="y" operator="lessThan" value="-5"/>
+				</Node>
+			</Node>
+		</Node>
+	</TreeModel>`
+
+	td := []struct {
+		input  map[string]interface{} // The input data
+		expect map[string]interface{} // The expected score for each strategy
+	}{
+		{
+			input: map[string]interface{}{"x": 20, "y": 1},
+			expect: map[string]interface{}{
+				"":                     "a1",
+				"returnNullPrediction": "a1",
+				"returnLastPrediction": "a1",
+			},
+		},
+		{
+			input: map[string]interface{}{"x": 20, "y": -1},
+			expect: map[string]interface{}{
+				"":                     nil,
+				"returnNullPrediction": nil,
+				"returnLastPrediction": "a",
+			},
+		},
+		{
+			input: map[string]interface{}{"x": 20},
+			expect: map[string]interface{}{
+				"":                     nil,
+				"returnNullPrediction": nil,
+				"returnLastPrediction": "a",
+			},
+		},
+		{
+			input: map[string]interface{}{"x": 5},
+			expect: map[string]interface{}{
+				"":                     nil,
+				"returnNullPrediction": nil,
+				"returnLastPrediction": "root",
+			},
+		},
+	}
+
+	for strategy := range td[0].expect {
+		t.Run(strategy, func(t *testing.T) {
+			var out schema.DecisionTree
+			body, global, code := scopeFor(input, &out)
+			out.NoTrueChildStrategy = strategy
+			global.DecisionTree(out, global)
+			body.With(
+				NewStatement().Return().Call("json.encode", out.ModelName+"(v)"),
+			)
+
+			if strategy != "" {
+				assert.Contains(t, code(), "noTrueChild='"+strategy+"'")
+			}
+
+			s := makeScript(code())
+			for _, tt := range td {
+				r, err := runScript(s, tt.input)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect[strategy], valueOf(r), tt.input)
+			}
+		})
+	}
+}
+
+func TestRootPredicate(t *testing.T) {
+	input := `<TreeModel modelName="edge" functionName="classification">
+		<Node id="root" score="root">
+			<SimplePredicate field="x" operator="lessThan" value="5"/>
+			<Node id="a" score="a">
+				<SimplePredicate field="y" operator="greaterThan" value="0"/>
+			</Node>
+		</Node>
+	</TreeModel>`
+
+	td := []struct {
+		noTrueChild string                 // The noTrueChildStrategy of the tree
+		missing     string                 // The missingValueStrategy of the tree
+		input       map[string]interface{} // The input data
+	}{
+		{noTrueChild: "returnLastPrediction", missing: "none", input: map[string]interface{}{"x": 7}},
+		{noTrueChild: "returnLastPrediction", missing: "none", input: map[string]interface{}{"y": 1}},
+		{noTrueChild: "returnNullPrediction", missing: "none", input: map[string]interface{}{"y": 1}},
+		{noTrueChild: "returnLastPrediction", missing: "lastPrediction", input: map[string]interface{}{"y": 1}},
+		{noTrueChild: "returnNullPrediction", missing: "lastPrediction", input: map[string]interface{}{"y": 1}},
+	}
+
+	for _, tc := range td {
+		var out schema.DecisionTree
+		body, global, code := scopeFor(input, &out)
+		out.NoTrueChildStrategy = tc.noTrueChild
+		out.MissingValueStrategy = tc.missing
+		global.DecisionTree(out, global)
+		body.With(
+			NewStatement().Return().Call("json.encode", out.ModelName+"(v)"),
+		)
+
+		// The record does not reach the root node, so there is no prediction
+		r, err := runScript(makeScript(code()), tc.input)
+		assert.NoError(t, err, tc.noTrueChild, tc.missing)
+		assert.Nil(t, r, tc.noTrueChild, tc.missing)
+	}
+}
+
+func TestMissingValuePenalty(t *testing.T) {
+	td := []struct {
+		strategy string                 // The missing value strategy
+		input    map[string]interface{} // The input data
+		expect   float64                // The expected confidence of the winner
+	}{
+		{strategy: "defaultChild", input: map[string]interface{}{"outlook": "sunny", "temperature": 60}, expect: 0.9},
+		{strategy: "defaultChild", input: map[string]interface{}{"outlook": "sunny"}, expect: 0.9 * 0.8},
+		{strategy: "defaultChild", input: map[string]interface{}{}, expect: 0.9 * 0.8 * 0.8},
+		{strategy: "defaultChild", input: map[string]interface{}{"temperature": 45, "humidity": 85}, expect: 0.6 * 0.8},
+		{strategy: "weightedConfidence", input: map[string]interface{}{"temperature": 45, "humidity": 85}, expect: 0.4 * 0.8},
+		{strategy: "aggregateNodes", input: map[string]interface{}{"temperature": 45, "humidity": 85}, expect: 28.0 / 60 * 0.8 * 0.8},
+		{strategy: "lastPrediction", input: map[string]interface{}{"temperature": 45, "humidity": 85}, expect: 0.6},
+	}
+
+	for _, tt := range td {
+		t.Run(tt.strategy, func(t *testing.T) {
+			var out schema.PMML
+			body, global, code := scopeFor("fixtures/tree1.xml", &out)
+			model := *out.Models[0].TreeModel
+			model.MissingValueStrategy = tt.strategy
+			model.MissingValuePenalty = 0.8
+
+			global.DecisionTree(model, global)
+			body.With(
+				Append("golfing(v)"),
+				Append("local p = model.golfing.predict(v)"),
+				Append("for i=1, #p.dist do if p.dist[i].value == p.score then return p.dist[i].confidence end end"),
+			)
+
+			assert.Contains(t, code(), "penalty=0.8")
+
+			s := makeScript(code())
+			v, err := s.Run(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expect, float64(v.(lua.Number)), 1e-9)
+		})
+	}
+}
+
+// valueOf returns the predicted value of the result
+func valueOf(r *Result) interface{} {
+	if r == nil {
+		return nil
+	}
+	return r.Value
 }